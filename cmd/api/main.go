@@ -1,13 +1,17 @@
 package main
 
 import (
+	"context"
 	"mini-ecommerce/config"
+	"mini-ecommerce/internal/interfaces/cli"
 	"mini-ecommerce/internal/interfaces/http/routes"
 	"mini-ecommerce/pkg/logger"
 	"mini-ecommerce/pkg/validation"
+	"os"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/recover"
+	"github.com/redis/go-redis/v9"
 )
 
 func main() {
@@ -16,6 +20,17 @@ func main() {
 	if err != nil {
 		logger.Fatal(err, "[ErrMain-1]Failed to load config")
 	}
+	if err := validation.RegisterPasswordPolicy(validation.PasswordPolicy{
+		MinLength:     cfg.Password.MinLength,
+		MaxLength:     cfg.Password.MaxLength,
+		MaxBytes:      cfg.Password.MaxBytes,
+		RequireUpper:  cfg.Password.RequireUpper,
+		RequireLower:  cfg.Password.RequireLower,
+		RequireDigit:  cfg.Password.RequireDigit,
+		RequireSymbol: cfg.Password.RequireSymbol,
+	}); err != nil {
+		logger.Fatal(err, "[ErrMain-5]Failed to register password policy")
+	}
 	app := fiber.New()
 
 	app.Use(recover.New())
@@ -25,7 +40,25 @@ func main() {
 		logger.Fatal(err, "[ErrMain-2]Failed to connect to database")
 	}
 	defer db.Close()
-	routes.SetupRoutes(app, db.DB)
+
+	// Arguments select a maintenance command, see cli.Run.
+	if len(os.Args) > 1 {
+		if err := cli.Run(context.Background(), db.DB, os.Args[1:]); err != nil {
+			logger.Fatal(err, "[ErrMain-6]Command failed")
+		}
+		return
+	}
+
+	var rdb *redis.Client
+	if cfg.Redis.Enabled {
+		r, err := config.ConnectRedis(cfg)
+		if err != nil {
+			logger.Fatal(err, "[ErrMain-4]Failed to connect to redis")
+		}
+		defer r.Close()
+		rdb = r.Client
+	}
+	routes.SetupRoutes(app, db.DB, rdb, cfg)
 
 	addr := cfg.Server.Host + ":" + cfg.Server.Port
 	logger.Info("Starting server on: " + addr)
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"fmt"
+	"math"
 	"mini-ecommerce/pkg/utils"
 	"os"
 	"time"
@@ -9,12 +11,16 @@ import (
 )
 
 type Config struct {
-	Server ServerConfig
-	DB     DBConfig
-	JWT    JWTConfig
-	Rate   RateLimitConfig
-	CORS   CORSConfig
-	App    AppEnv
+	Server   ServerConfig
+	DB       DBConfig
+	Redis    RedisConfig
+	JWT      JWTConfig
+	Auth     AuthConfig
+	Password PasswordConfig
+	Mail     MailConfig
+	Rate     RateLimitConfig
+	CORS     CORSConfig
+	App      AppEnv
 }
 
 type ServerConfig struct {
@@ -23,13 +29,15 @@ type ServerConfig struct {
 }
 
 type JWTConfig struct {
-	SecretKey string
-	Expire    time.Duration
+	SecretKey     string
+	Expire        time.Duration
+	RefreshExpire time.Duration
 }
 
 type RateLimitConfig struct {
-	RequestPerMinute int
-	Minutes          int
+	RequestPerMinute     int
+	Minutes              int
+	AuthRequestPerMinute int
 }
 
 type CORSConfig struct {
@@ -47,9 +55,71 @@ type DBConfig struct {
 	SSLMode string
 }
 
+type RedisConfig struct {
+	Enabled  bool
+	Host     string
+	Port     string
+	Password string
+	DB       int
+}
+
+type AuthConfig struct {
+	// RevocationStore selects the token revocation backend: "memory" or "redis"
+	RevocationStore          string
+	RequireEmailVerification bool
+	EmailVerificationExpire  time.Duration
+	PasswordResetExpire      time.Duration
+	// PasswordResetURL is the page that password reset emails link to. It
+	// gets the token as a "token" query parameter and must POST it with the
+	// new password to /auth/reset-password. When empty the links open the
+	// form served by GET /auth/reset-password.
+	PasswordResetURL string
+	// ThrottleStore selects the login throttling backend: "memory" or "redis"
+	ThrottleStore      string
+	LoginMaxAttempts   int
+	LoginAttemptWindow time.Duration
+	LoginLockoutBase   time.Duration
+	LoginLockoutMax    time.Duration
+	// TOTPIssuer is the account issuer shown by authenticator apps
+	TOTPIssuer string
+	// TOTPEncryptionKey encrypts TOTP secrets at rest
+	TOTPEncryptionKey string
+	// TOTPRequiredRoles lists the roles that must enroll in two-factor authentication
+	TOTPRequiredRoles []string
+}
+
+type PasswordConfig struct {
+	// HashAlgorithm selects the algorithm for new hashes: "argon2id" or "bcrypt"
+	HashAlgorithm     string
+	BcryptCost        int
+	Argon2Memory      int
+	Argon2Iterations  int
+	Argon2Parallelism int
+	MinLength         int
+	MaxLength         int
+	RequireUpper      bool
+	RequireLower      bool
+	RequireDigit      bool
+	RequireSymbol     bool
+	// MaxBytes caps the encoded length of passwords; set for bcrypt
+	MaxBytes int
+}
+
+type MailConfig struct {
+	// Driver selects the mailer: "log" for development or "smtp"
+	Driver    string
+	Host      string
+	Port      string
+	Username  string
+	Password  string
+	From      string
+	OutputDir string
+}
+
 type AppEnv struct {
 	Environment string
 	Debug       bool
+	BaseURL     string
 }
 
 func LoadConfig() (*Config, error) {
@@ -66,10 +136,94 @@ func LoadConfig() (*Config, error) {
 	if err != nil {
 		return nil, err
 	}
+	RateLimitAuthRequestsPerMinute, err := utils.GetEnvAsInt("RATE_LIMIT_AUTH_REQUESTS_PER_MINUTE", 5)
+	if err != nil {
+		return nil, err
+	}
 	JWTExpirationHours, err := utils.GetEnvAsInt("JWT_EXPIRATION_HOURS", 24)
 	if err != nil {
 		return nil, err
 	}
+	JWTRefreshExpirationHours, err := utils.GetEnvAsInt("JWT_REFRESH_EXPIRATION_HOURS", 720)
+	if err != nil {
+		return nil, err
+	}
+	RedisEnabled, err := utils.GetEnvAsBool("REDIS_ENABLED", false)
+	if err != nil {
+		return nil, err
+	}
+	RedisDB, err := utils.GetEnvAsInt("REDIS_DB", 0)
+	if err != nil {
+		return nil, err
+	}
+	RequireEmailVerification, err := utils.GetEnvAsBool("AUTH_REQUIRE_EMAIL_VERIFICATION", false)
+	if err != nil {
+		return nil, err
+	}
+	EmailVerificationExpirationHours, err := utils.GetEnvAsInt("AUTH_EMAIL_VERIFICATION_EXPIRATION_HOURS", 24)
+	if err != nil {
+		return nil, err
+	}
+	PasswordResetExpirationMinutes, err := utils.GetEnvAsInt("AUTH_PASSWORD_RESET_EXPIRATION_MINUTES", 30)
+	if err != nil {
+		return nil, err
+	}
+	LoginMaxAttempts, err := utils.GetEnvAsInt("AUTH_LOGIN_MAX_ATTEMPTS", 5)
+	if err != nil {
+		return nil, err
+	}
+	LoginAttemptWindowMinutes, err := utils.GetEnvAsInt("AUTH_LOGIN_ATTEMPT_WINDOW_MINUTES", 15)
+	if err != nil {
+		return nil, err
+	}
+	LoginLockoutBaseMinutes, err := utils.GetEnvAsInt("AUTH_LOGIN_LOCKOUT_BASE_MINUTES", 1)
+	if err != nil {
+		return nil, err
+	}
+	LoginLockoutMaxMinutes, err := utils.GetEnvAsInt("AUTH_LOGIN_LOCKOUT_MAX_MINUTES", 60)
+	if err != nil {
+		return nil, err
+	}
+	PasswordBcryptCost, err := utils.GetEnvAsInt("PASSWORD_BCRYPT_COST", 12)
+	if err != nil {
+		return nil, err
+	}
+	PasswordArgon2MemoryKiB, err := utils.GetEnvAsInt("PASSWORD_ARGON2_MEMORY_KIB", 65536)
+	if err != nil {
+		return nil, err
+	}
+	PasswordArgon2Iterations, err := utils.GetEnvAsInt("PASSWORD_ARGON2_ITERATIONS", 3)
+	if err != nil {
+		return nil, err
+	}
+	PasswordArgon2Parallelism, err := utils.GetEnvAsInt("PASSWORD_ARGON2_PARALLELISM", 2)
+	if err != nil {
+		return nil, err
+	}
+	PasswordMinLength, err := utils.GetEnvAsInt("PASSWORD_MIN_LENGTH", 8)
+	if err != nil {
+		return nil, err
+	}
+	PasswordMaxLength, err := utils.GetEnvAsInt("PASSWORD_MAX_LENGTH", 128)
+	if err != nil {
+		return nil, err
+	}
+	PasswordRequireUpper, err := utils.GetEnvAsBool("PASSWORD_REQUIRE_UPPER", false)
+	if err != nil {
+		return nil, err
+	}
+	PasswordRequireLower, err := utils.GetEnvAsBool("PASSWORD_REQUIRE_LOWER", false)
+	if err != nil {
+		return nil, err
+	}
+	PasswordRequireDigit, err := utils.GetEnvAsBool("PASSWORD_REQUIRE_DIGIT", false)
+	if err != nil {
+		return nil, err
+	}
+	PasswordRequireSymbol, err := utils.GetEnvAsBool("PASSWORD_REQUIRE_SYMBOL", false)
+	if err != nil {
+		return nil, err
+	}
 	AppDebug, err := utils.GetEnvAsBool("APP_DEBUG", true)
 	if err != nil {
 		return nil, err
@@ -88,13 +242,59 @@ func LoadConfig() (*Config, error) {
 			DBName:  getEnv("DB_NAME", "mini_ecommerce_db"),
 			SSLMode: getEnv("DB_SSLMODE", "disable"),
 		},
+		Redis: RedisConfig{
+			Enabled:  RedisEnabled,
+			Host:     getEnv("REDIS_HOST", "localhost"),
+			Port:     getEnv("REDIS_PORT", "6379"),
+			Password: getEnv("REDIS_PASSWORD", ""),
+			DB:       RedisDB,
+		},
 		JWT: JWTConfig{
-			SecretKey: getEnv("JWT_SECRET_KEY", "your_secret_key"),
-			Expire:    time.Duration(JWTExpirationHours) * time.Hour,
+			SecretKey:     getEnv("JWT_SECRET_KEY", "your_secret_key"),
+			Expire:        time.Duration(JWTExpirationHours) * time.Hour,
+			RefreshExpire: time.Duration(JWTRefreshExpirationHours) * time.Hour,
+		},
+		Auth: AuthConfig{
+			RevocationStore:          getEnv("AUTH_REVOCATION_STORE", "memory"),
+			RequireEmailVerification: RequireEmailVerification,
+			EmailVerificationExpire:  time.Duration(EmailVerificationExpirationHours) * time.Hour,
+			PasswordResetExpire:      time.Duration(PasswordResetExpirationMinutes) * time.Minute,
+			PasswordResetURL:         getEnv("AUTH_PASSWORD_RESET_URL", ""),
+			ThrottleStore:            getEnv("AUTH_THROTTLE_STORE", "memory"),
+			LoginMaxAttempts:         LoginMaxAttempts,
+			LoginAttemptWindow:       time.Duration(LoginAttemptWindowMinutes) * time.Minute,
+			LoginLockoutBase:         time.Duration(LoginLockoutBaseMinutes) * time.Minute,
+			LoginLockoutMax:          time.Duration(LoginLockoutMaxMinutes) * time.Minute,
+			TOTPIssuer:               getEnv("AUTH_TOTP_ISSUER", "Mini E-Commerce"),
+			TOTPEncryptionKey:        getEnv("AUTH_TOTP_ENCRYPTION_KEY", ""),
+			TOTPRequiredRoles:        utils.GetEnvAsSlice("AUTH_TOTP_REQUIRED_ROLES", []string{}, ","),
+		},
+		Password: PasswordConfig{
+			HashAlgorithm:     getEnv("PASSWORD_HASH_ALGORITHM", "argon2id"),
+			BcryptCost:        PasswordBcryptCost,
+			Argon2Memory:      PasswordArgon2MemoryKiB,
+			Argon2Iterations:  PasswordArgon2Iterations,
+			Argon2Parallelism: PasswordArgon2Parallelism,
+			MinLength:         PasswordMinLength,
+			MaxLength:         PasswordMaxLength,
+			RequireUpper:      PasswordRequireUpper,
+			RequireLower:      PasswordRequireLower,
+			RequireDigit:      PasswordRequireDigit,
+			RequireSymbol:     PasswordRequireSymbol,
+		},
+		Mail: MailConfig{
+			Driver:    getEnv("EMAIL_DRIVER", "log"),
+			Host:      getEnv("EMAIL_HOST", "localhost"),
+			Port:      getEnv("EMAIL_PORT", "587"),
+			Username:  getEnv("EMAIL_USERNAME", ""),
+			Password:  getEnv("EMAIL_PASSWORD", ""),
+			From:      getEnv("EMAIL_FROM", "noreply@ecommerce.com"),
+			OutputDir: getEnv("EMAIL_OUTPUT_DIR", ""),
 		},
 		Rate: RateLimitConfig{
-			RequestPerMinute: RateLimitRequestsPerMinute,
-			Minutes:          RateLimitMinutes,
+			RequestPerMinute:     RateLimitRequestsPerMinute,
+			Minutes:              RateLimitMinutes,
+			AuthRequestPerMinute: RateLimitAuthRequestsPerMinute,
 		},
 		CORS: CORSConfig{
 			AllowedOrigins: utils.GetEnvAsSlice("CORS_ALLOWED_ORIGINS", []string{"*"}, ","),
@@ -104,15 +304,46 @@ func LoadConfig() (*Config, error) {
 		App: AppEnv{
 			Environment: getEnv("APP_ENV", "development"),
 			Debug:       AppDebug,
+			BaseURL:     getEnv("APP_BASE_URL", "http://localhost:3000"),
 		},
 	}
 	if cfg.App.Environment == "production" {
 		cfg.App.Debug = false
 	}
+	// bcrypt cannot hash passwords longer than 72 bytes.
+	if cfg.Password.HashAlgorithm == "bcrypt" {
+		cfg.Password.MaxBytes = 72
+		if cfg.Password.MaxLength <= 0 || cfg.Password.MaxLength > 72 {
+			cfg.Password.MaxLength = 72
+		}
+	}
+	if err := cfg.Password.validateArgon2(); err != nil {
+		return nil, err
+	}
+	if cfg.Auth.TOTPEncryptionKey == "" {
+		cfg.Auth.TOTPEncryptionKey = cfg.JWT.SecretKey
+	}
 
 	return cfg, nil
 }
 
+// maxArgon2MemoryKiB caps the memory of a single hash at 4 GiB
+const maxArgon2MemoryKiB = 4 * 1024 * 1024
+
+// validateArgon2 rejects argon2id costs that argon2 cannot run with or that
+// do not fit its unsigned parameters.
+func (p PasswordConfig) validateArgon2() error {
+	switch {
+	case p.Argon2Parallelism < 1 || p.Argon2Parallelism > math.MaxUint8:
+		return fmt.Errorf("PASSWORD_ARGON2_PARALLELISM must be between 1 and %d", math.MaxUint8)
+	case p.Argon2Iterations < 1 || int64(p.Argon2Iterations) > math.MaxUint32:
+		return fmt.Errorf("PASSWORD_ARGON2_ITERATIONS must be between 1 and %d", uint32(math.MaxUint32))
+	case p.Argon2Memory < 8*p.Argon2Parallelism || p.Argon2Memory > maxArgon2MemoryKiB:
+		return fmt.Errorf("PASSWORD_ARGON2_MEMORY_KIB must be between %d and %d", 8*p.Argon2Parallelism, maxArgon2MemoryKiB)
+	}
+	return nil
+}
+
 func getEnv(key string, defaultValue string) string {
 	value := os.Getenv(key)
 	if value == "" {
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"context"
+
+	"mini-ecommerce/pkg/logger"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type Redis struct {
+	Client *redis.Client
+}
+
+func ConnectRedis(cfg *Config) (*Redis, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.Redis.Host + ":" + cfg.Redis.Port,
+		Password: cfg.Redis.Password,
+		DB:       cfg.Redis.DB,
+	})
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		logger.Error(err, "[ErrRedis-1]Failed to ping redis")
+		return nil, err
+	}
+
+	logger.Info("Successfully connected to redis")
+	return &Redis{Client: client}, nil
+}
+
+func (r *Redis) Close() error {
+	if err := r.Client.Close(); err != nil {
+		logger.Error(err, "[ErrRedis-2]Failed to close redis connection")
+		return err
+	}
+
+	logger.Info("Redis connection closed")
+	return nil
+}
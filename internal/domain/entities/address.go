@@ -1,5 +1,7 @@
 package entities
 
+import "time"
+
 // UserAddress represents a user's shipping address
 type UserAddress struct {
 	ID            int
@@ -14,4 +16,6 @@ type UserAddress struct {
 	PostalCode    string
 	Country       string
 	IsDefault     bool
-}
\ No newline at end of file
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
@@ -0,0 +1,18 @@
+package entities
+
+import "time"
+
+// APIKey is a long-lived credential for server-to-server integrations.
+// Only the hash of the key is stored; Prefix identifies it in listings.
+type APIKey struct {
+	ID         int
+	UserID     int
+	Name       string
+	Prefix     string
+	KeyHash    string
+	Scopes     []Permission
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
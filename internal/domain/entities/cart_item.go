@@ -5,6 +5,8 @@ type CartItem struct {
 	ID        int
 	CartID    int
 	ProductID int
+	// VariantID is required for products with variants
+	VariantID *int
 	Quantity  int
 	UnitPrice float64
 }
\ No newline at end of file
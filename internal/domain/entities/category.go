@@ -1,11 +1,20 @@
 package entities
 
-// Category represents a product category
+import "time"
+
+// Category represents a product category. Categories form a tree through
+// ParentID; a nil ParentID marks a root category.
 type Category struct {
 	ID          int
+	ParentID    *int
 	Name        string
+	Slug        string
 	Description string
 	ImageURL    string
 	IsActive    bool
 	SortOrder   int
-}
\ No newline at end of file
+	// ProductCount is the number of products directly in the category
+	ProductCount int64
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
@@ -1,5 +1,7 @@
 package entities
 
+import "time"
+
 // Order represents an order in the system
 type Order struct {
 	ID              string
@@ -14,4 +16,5 @@ type Order struct {
 	ShippingAddress map[string]interface{} // Store complete address snapshot
 	TrackingNumber  string
 	Notes           string
-}
\ No newline at end of file
+	CreatedAt       time.Time
+}
@@ -5,9 +5,10 @@ type OrderItem struct {
 	ID              int
 	OrderID         string
 	ProductID       int
+	VariantID       *int   // Set for products with variants
 	ProductName     string // Snapshot at time of order
 	Quantity        int
 	UnitPrice       float64
 	TotalPrice      float64
 	ProductSnapshot map[string]interface{} // Store product details at time of order
-}
\ No newline at end of file
+}
@@ -0,0 +1,13 @@
+package entities
+
+import "time"
+
+// PasswordResetToken is a single-use token that allows a user to set a new password
+type PasswordResetToken struct {
+	ID        int
+	UserID    int
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
@@ -1,5 +1,7 @@
 package entities
 
+import "time"
+
 // Payment represents a payment transaction record
 type Payment struct {
 	ID              string
@@ -7,7 +9,8 @@ type Payment struct {
 	Amount          float64
 	PaymentMethod   string
 	Status          string
-	TransactionID   string // External payment processor transaction ID
-	PaymentDetails  map[string]interface{}  // Store payment method specific details (masked)
+	TransactionID   string                 // External payment processor transaction ID
+	PaymentDetails  map[string]interface{} // Store payment method specific details (masked)
 	GatewayResponse map[string]interface{} // Store payment gateway response
-}
\ No newline at end of file
+	CreatedAt       time.Time
+}
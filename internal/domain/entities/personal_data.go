@@ -0,0 +1,10 @@
+package entities
+
+// PersonalData is what is stored about a user outside their profile and
+// address book, collected for a data export.
+type PersonalData struct {
+	Orders     []*Order
+	OrderItems map[string][]*OrderItem // keyed by order ID
+	Payments   []*Payment
+	Reviews    []*ProductReview
+}
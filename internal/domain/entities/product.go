@@ -1,16 +1,35 @@
 package entities
 
+import "time"
+
 // Product represents a product in the catalog
 type Product struct {
-	ID              int
-	Name            string
-	Description     string
-	Price           float64
-	StockQuantity   int
-	CategoryID      int
-	SKU             string
-	Specifications  map[string]interface{}
-	IsActive        bool
-	Weight          float64
-	Dimensions      map[string]interface{}
-}
\ No newline at end of file
+	ID             int
+	Name           string
+	Slug           string
+	Description    string
+	Price          float64
+	StockQuantity  int
+	CategoryID     int
+	SKU            string
+	Specifications map[string]interface{}
+	IsActive       bool
+	Weight         float64
+	Dimensions     map[string]interface{}
+	// Options names the option axes of the variants, such as size and color.
+	// A product with variants has its stock tracked per variant.
+	Options   []string
+	Variants  []*ProductVariant
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// Filled when reading, not saved
+	Category    *Category
+	Images      []*ProductImage
+	Rating      float64
+	ReviewCount int64
+	// MinPrice and MaxPrice span the prices of the active variants, or equal
+	// Price for products without variants
+	MinPrice float64
+	MaxPrice float64
+}
@@ -0,0 +1,31 @@
+package entities
+
+// ProductFacets counts the products of a listing per filter value. Each
+// dimension is counted without its own filter, so choosing a value keeps the
+// alternatives of that dimension visible.
+type ProductFacets struct {
+	Categories     []*CategoryFacet
+	PriceRanges    []*PriceRangeFacet
+	Specifications []*SpecFacet
+}
+
+type CategoryFacet struct {
+	ID    int
+	Name  string
+	Slug  string
+	Count int64
+}
+
+// PriceRangeFacet covers prices from Min up to but excluding Max. The last
+// range has no Max.
+type PriceRangeFacet struct {
+	Min   float64
+	Max   *float64
+	Count int64
+}
+
+type SpecFacet struct {
+	Key   string
+	Value string
+	Count int64
+}
@@ -1,11 +1,16 @@
 package entities
 
+import "time"
+
 // ProductImage represents an image for a product
 type ProductImage struct {
 	ID        int
 	ProductID int
+	// VariantID is set for images showing one variant
+	VariantID *int
 	URL       string
 	AltText   string
 	IsPrimary bool
 	SortOrder int
-}
\ No newline at end of file
+	CreatedAt time.Time
+}
@@ -0,0 +1,56 @@
+package entities
+
+import (
+	"slices"
+	"time"
+)
+
+// ProductVariant is a purchasable combination of the product's options, such
+// as size M in red. Price and Weight override the product's when set.
+type ProductVariant struct {
+	ID        int
+	ProductID int
+	SKU       string
+	// Options holds a value for each of the product's options
+	Options       map[string]string
+	Price         *float64
+	StockQuantity int
+	Weight        *float64
+	IsActive      bool
+	SortOrder     int
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+
+	// Filled when reading, not saved
+	Images []*ProductImage
+}
+
+// UnitPrice is the variant's own price, or the product's when not overridden.
+func (v *ProductVariant) UnitPrice(product *Product) float64 {
+	if v.Price != nil {
+		return *v.Price
+	}
+	return product.Price
+}
+
+// UnitWeight is the variant's own weight, or the product's when not
+// overridden.
+func (v *ProductVariant) UnitWeight(product *Product) float64 {
+	if v.Weight != nil {
+		return *v.Weight
+	}
+	return product.Weight
+}
+
+// SameOptionNames reports whether both lists hold the same option names,
+// ignoring their order.
+func SameOptionNames(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	sorted := slices.Clone(a)
+	slices.Sort(sorted)
+	other := slices.Clone(b)
+	slices.Sort(other)
+	return slices.Equal(sorted, other)
+}
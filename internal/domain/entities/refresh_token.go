@@ -0,0 +1,19 @@
+package entities
+
+import "time"
+
+// RefreshToken represents a long-lived, server-side refresh token issued to a user device.
+// Tokens rotated from the same login share a FamilyID so a whole chain can be revoked at once.
+type RefreshToken struct {
+	ID           int
+	UserID       int
+	FamilyID     string
+	TokenHash    string
+	DeviceID     string
+	UserAgent    string
+	IPAddress    string
+	ExpiresAt    time.Time
+	RevokedAt    *time.Time
+	ReplacedByID *int
+	CreatedAt    time.Time
+}
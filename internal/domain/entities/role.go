@@ -0,0 +1,56 @@
+package entities
+
+// Permission is a named capability that can be granted to a role
+type Permission string
+
+const (
+	PermissionAll Permission = "*"
+
+	PermissionCategoryWrite     Permission = "category:write"
+	PermissionProductWrite      Permission = "product:write"
+	PermissionProductStockWrite Permission = "product:stock:write"
+	PermissionUserRead          Permission = "user:read"
+	PermissionUserManage        Permission = "user:manage"
+)
+
+const (
+	RoleAdmin     = "admin"
+	RoleCustomer  = "customer"
+	RoleWarehouse = "warehouse"
+	RoleSupport   = "support"
+)
+
+// RolePermissions declares which permissions each role is granted.
+// Adding a role only requires a new entry here.
+var RolePermissions = map[string][]Permission{
+	RoleAdmin:     {PermissionAll},
+	RoleCustomer:  {},
+	RoleWarehouse: {PermissionProductStockWrite},
+	RoleSupport:   {PermissionUserRead},
+}
+
+// IsValidRole reports whether the role is declared in RolePermissions.
+func IsValidRole(role string) bool {
+	_, ok := RolePermissions[role]
+	return ok
+}
+
+// IsValidPermission reports whether the permission is one of the declared constants.
+func IsValidPermission(permission Permission) bool {
+	switch permission {
+	case PermissionAll, PermissionCategoryWrite, PermissionProductWrite, PermissionProductStockWrite,
+		PermissionUserRead, PermissionUserManage:
+		return true
+	}
+	return false
+}
+
+// HasPermission reports whether the role is granted the permission.
+func HasPermission(role string, permission Permission) bool {
+	for _, p := range RolePermissions[role] {
+		if p == PermissionAll || p == permission {
+			return true
+		}
+	}
+	return false
+}
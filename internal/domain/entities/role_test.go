@@ -0,0 +1,47 @@
+package entities
+
+import "testing"
+
+func TestHasPermission(t *testing.T) {
+	tests := []struct {
+		role       string
+		permission Permission
+		want       bool
+	}{
+		{RoleAdmin, PermissionProductWrite, true},
+		{RoleAdmin, PermissionUserManage, true},
+		{RoleCustomer, PermissionProductWrite, false},
+		{RoleCustomer, PermissionProductStockWrite, false},
+		{RoleWarehouse, PermissionProductStockWrite, true},
+		{RoleWarehouse, PermissionProductWrite, false},
+		{RoleWarehouse, PermissionUserRead, false},
+		{RoleSupport, PermissionUserRead, true},
+		{RoleSupport, PermissionUserManage, false},
+		{RoleSupport, PermissionProductStockWrite, false},
+		{"unknown", PermissionProductWrite, false},
+	}
+	for _, tt := range tests {
+		if got := HasPermission(tt.role, tt.permission); got != tt.want {
+			t.Errorf("HasPermission(%q, %q) = %v, want %v", tt.role, tt.permission, got, tt.want)
+		}
+	}
+}
+
+func TestRolePermissionsAreDeclared(t *testing.T) {
+	for role, permissions := range RolePermissions {
+		if !IsValidRole(role) {
+			t.Errorf("IsValidRole(%q) = false for a declared role", role)
+		}
+		for _, permission := range permissions {
+			if !IsValidPermission(permission) {
+				t.Errorf("role %q is granted undeclared permission %q", role, permission)
+			}
+		}
+	}
+	if IsValidRole("root") {
+		t.Error(`IsValidRole("root") = true, want false`)
+	}
+	if IsValidPermission("product:delete") {
+		t.Error(`IsValidPermission("product:delete") = true, want false`)
+	}
+}
@@ -0,0 +1,17 @@
+package entities
+
+import "time"
+
+// Session is a signed-in device. Its ID is shared by the refresh token family
+// and the access tokens issued for it, so revoking it signs the device out.
+type Session struct {
+	ID             string
+	UserID         int
+	DeviceID       string
+	UserAgent      string
+	IPAddress      string
+	LastActivityAt time.Time
+	ExpiresAt      time.Time
+	RevokedAt      *time.Time
+	CreatedAt      time.Time
+}
@@ -0,0 +1,12 @@
+package entities
+
+import "time"
+
+// UserTwoFactor holds a user's TOTP enrollment. The secret is stored encrypted.
+type UserTwoFactor struct {
+	UserID          int
+	SecretEncrypted string
+	ConfirmedAt     *time.Time
+	// LastUsedStep is the TOTP time step of the last accepted code, used to block replays
+	LastUsedStep int64
+}
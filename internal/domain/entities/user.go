@@ -1,5 +1,7 @@
 package entities
 
+import "time"
+
 // User represents a user in the system
 type User struct {
 	ID            int
@@ -10,4 +12,13 @@ type User struct {
 	Role          string
 	EmailVerified bool
 	IsActive      bool
+	// TwoFactorEnabled is set once TOTP enrollment has been confirmed
+	TwoFactorEnabled bool
+	// PasswordChangedAt invalidates every access token issued before it
+	PasswordChangedAt *time.Time
+	// AnonymizedAt is set once the account has been erased; the row is kept
+	// so orders and payments still reference it
+	AnonymizedAt *time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
 }
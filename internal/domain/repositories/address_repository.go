@@ -1 +1,22 @@
-package repositories
\ No newline at end of file
+package repositories
+
+import (
+	"context"
+	"errors"
+	"mini-ecommerce/internal/domain/entities"
+)
+
+var ErrAddressNotFound = errors.New("address not found")
+
+// AddressRepository keeps at most one default address per user. Every method
+// is scoped to the owning user.
+type AddressRepository interface {
+	ListByUserId(ctx context.Context, userID int) ([]*entities.UserAddress, error)
+	GetById(ctx context.Context, userID, id int) (*entities.UserAddress, error)
+	// Create stores the address; a user's first address always becomes the default.
+	Create(ctx context.Context, address *entities.UserAddress) error
+	Update(ctx context.Context, address *entities.UserAddress) error
+	// Delete removes the address and promotes the most recently updated
+	// remaining address when the default was deleted.
+	Delete(ctx context.Context, userID, id int) error
+}
@@ -0,0 +1,21 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"mini-ecommerce/internal/domain/entities"
+	"time"
+)
+
+var ErrAPIKeyNotFound = errors.New("api key not found")
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *entities.APIKey) error
+	GetByHash(ctx context.Context, keyHash string) (*entities.APIKey, error)
+	ListByUserId(ctx context.Context, userID int) ([]*entities.APIKey, error)
+	// Revoke returns ErrAPIKeyNotFound when the user owns no active key with the ID.
+	Revoke(ctx context.Context, userID, id int) error
+	// TouchLastUsed records usage, skipping the write when the stored value is
+	// newer than the given threshold.
+	TouchLastUsed(ctx context.Context, id int, usedAt time.Time, threshold time.Duration) error
+}
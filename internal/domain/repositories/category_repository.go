@@ -1 +1,58 @@
-package repositories
\ No newline at end of file
+package repositories
+
+import (
+	"context"
+	"errors"
+	"mini-ecommerce/internal/domain/entities"
+)
+
+var (
+	ErrCategoryNotFound      = errors.New("category not found")
+	ErrCategoryCycle         = errors.New("category cannot be moved below itself or one of its subcategories")
+	ErrCategoryHasChildren   = errors.New("category still has subcategories")
+	ErrCategoryHasProducts   = errors.New("category still has products")
+	ErrCategoryOrderMismatch = errors.New("category ids do not match the subcategories of the parent")
+)
+
+// CategoryFilter narrows List results. Nil fields are ignored; RootOnly
+// takes precedence over ParentID.
+type CategoryFilter struct {
+	ParentID *int
+	RootOnly bool
+	IsActive *bool
+	Offset   int
+	Limit    int
+}
+
+type CategoryRepository interface {
+	GetById(ctx context.Context, id int) (*entities.Category, error)
+	// GetBySlug looks up the current slug first and then previous slugs, so
+	// the returned category's slug can differ from the requested one.
+	GetBySlug(ctx context.Context, slug string) (*entities.Category, error)
+	// SlugExists reports whether the slug is used by another category, now
+	// or previously.
+	SlugExists(ctx context.Context, slug string, exceptID int) (bool, error)
+	List(ctx context.Context, filter CategoryFilter) ([]*entities.Category, int64, error)
+	// ListAll returns every category ordered for display, to build the tree.
+	ListAll(ctx context.Context, activeOnly bool) ([]*entities.Category, error)
+	// ListSubtree returns the category followed by all of its descendants.
+	// With activeOnly, inactive descendants and everything below them are skipped.
+	ListSubtree(ctx context.Context, id int, activeOnly bool) ([]*entities.Category, error)
+	// ListAncestors returns the path from the root down to the category itself.
+	ListAncestors(ctx context.Context, id int) ([]*entities.Category, error)
+	// ExistsByName reports whether a sibling other than exceptID already uses
+	// the name, compared case-insensitively.
+	ExistsByName(ctx context.Context, parentID *int, name string, exceptID int) (bool, error)
+	Create(ctx context.Context, category *entities.Category) error
+	// Update saves the category. Moving it below itself or one of its
+	// descendants fails with ErrCategoryCycle. A replaced slug is kept as an
+	// alias.
+	Update(ctx context.Context, category *entities.Category) error
+	// Reorder sets the sort order of the children of parentID, or of the root
+	// categories when it is nil, to their position in ids. ids must list every
+	// child exactly once, otherwise ErrCategoryOrderMismatch is returned and
+	// nothing changes.
+	Reorder(ctx context.Context, parentID *int, ids []int) error
+	// Delete removes a category without subcategories or products.
+	Delete(ctx context.Context, id int) error
+}
@@ -0,0 +1,21 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"mini-ecommerce/internal/domain/entities"
+)
+
+var (
+	ErrPasswordResetTokenNotFound = errors.New("password reset token not found")
+	ErrPasswordResetTokenUsed     = errors.New("password reset token already used")
+)
+
+type PasswordResetRepository interface {
+	Create(ctx context.Context, token *entities.PasswordResetToken) error
+	GetByHash(ctx context.Context, tokenHash string) (*entities.PasswordResetToken, error)
+	// MarkUsed consumes the token. It returns ErrPasswordResetTokenUsed if it was already consumed.
+	MarkUsed(ctx context.Context, id int) error
+	// InvalidateByUserId consumes every outstanding token of the user.
+	InvalidateByUserId(ctx context.Context, userID int) error
+}
@@ -0,0 +1,11 @@
+package repositories
+
+import (
+	"context"
+	"mini-ecommerce/internal/domain/entities"
+)
+
+type PersonalDataRepository interface {
+	// GetByUserId collects the orders, payments and reviews of a user.
+	GetByUserId(ctx context.Context, userID int) (*entities.PersonalData, error)
+}
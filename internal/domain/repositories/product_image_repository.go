@@ -0,0 +1,29 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"mini-ecommerce/internal/domain/entities"
+)
+
+var (
+	ErrProductImageNotFound = errors.New("product image not found")
+	ErrImageOrderMismatch   = errors.New("image ids do not match the product's images")
+)
+
+// ProductImageRepository keeps exactly one primary image per product that has
+// images: the first image becomes primary, setting another one demotes the
+// current one, and deleting the primary image promotes the next in order.
+type ProductImageRepository interface {
+	// ListByProductId returns the product's images in display order.
+	ListByProductId(ctx context.Context, productID int) ([]*entities.ProductImage, error)
+	// Create appends the image to the product's images. An image of a variant
+	// of another product fails with ErrProductVariantNotFound.
+	Create(ctx context.Context, image *entities.ProductImage) error
+	SetPrimary(ctx context.Context, productID, id int) error
+	Delete(ctx context.Context, productID, id int) error
+	// Reorder sets the sort order of the product's images to their position
+	// in ids. ids must list every image of the product exactly once, otherwise
+	// ErrImageOrderMismatch is returned and nothing changes.
+	Reorder(ctx context.Context, productID int, ids []int) error
+}
@@ -1 +1,74 @@
-package repositories
\ No newline at end of file
+package repositories
+
+import (
+	"context"
+	"errors"
+	"mini-ecommerce/internal/domain/entities"
+)
+
+var (
+	ErrProductNotFound    = errors.New("product not found")
+	ErrProductHasOrders   = errors.New("product has been ordered")
+	ErrInvalidProductSort = errors.New("unsupported product sort field")
+	ErrProductHasVariants = errors.New("product options cannot change while it has variants")
+)
+
+// ProductFilter narrows List and Facets results. Nil and zero fields are
+// ignored.
+type ProductFilter struct {
+	CategoryID *int
+	// Search matches name and description case-insensitively
+	Search   string
+	MinPrice *float64
+	MaxPrice *float64
+	IsActive *bool
+	InStock  bool
+	// Specs matches keys of the specifications; a product matches a key when
+	// its value equals any of the listed values
+	Specs map[string][]string
+	// SortBy is one of name, price, created_at, updated_at and
+	// stock_quantity; other values fail with ErrInvalidProductSort
+	SortBy   string
+	SortDesc bool
+	Offset   int
+	Limit    int
+}
+
+type ProductRepository interface {
+	// GetById returns the product with its category, all images, options,
+	// variants, price range, rating and review count.
+	GetById(ctx context.Context, id int) (*entities.Product, error)
+	// GetBySlug looks up the current slug first and then previous slugs, so
+	// the returned product's slug can differ from the requested one.
+	GetBySlug(ctx context.Context, slug string) (*entities.Product, error)
+	// SlugExists reports whether the slug is used by another product, now or
+	// previously.
+	SlugExists(ctx context.Context, slug string, exceptID int) (bool, error)
+	// GetBySKU returns the product with the SKU, loaded like GetById.
+	GetBySKU(ctx context.Context, sku string) (*entities.Product, error)
+	ExistsBySKU(ctx context.Context, sku string, exceptID int) (bool, error)
+	// List returns products with their category, primary image, price range,
+	// rating and review count.
+	List(ctx context.Context, filter ProductFilter) ([]*entities.Product, int64, error)
+	// ListByIds returns the products with the given ids in no particular
+	// order, loaded like List. Unknown ids are skipped.
+	ListByIds(ctx context.Context, ids []int) ([]*entities.Product, error)
+	// ListAfterId returns up to limit products with an id above afterID in id
+	// order, loaded like GetById, to walk the whole catalog in batches.
+	ListAfterId(ctx context.Context, afterID, limit int) ([]*entities.Product, error)
+	// Facets counts the products matching the filter per category, price
+	// range and specification value. priceBounds are the ascending upper
+	// bounds of all but the last price range.
+	Facets(ctx context.Context, filter ProductFilter, priceBounds []float64) (*entities.ProductFacets, error)
+	// Create saves the product together with its options, variants and images
+	// in their given order.
+	Create(ctx context.Context, product *entities.Product) error
+	// Update saves the product's own fields and options; images and variants
+	// are managed through their repositories. A replaced slug is kept as an
+	// alias. Changing the options of a product with variants fails with
+	// ErrProductHasVariants, and its stock quantity is left unchanged.
+	Update(ctx context.Context, product *entities.Product) error
+	// Delete removes a product that was never ordered, with its images and
+	// variants.
+	Delete(ctx context.Context, id int) error
+}
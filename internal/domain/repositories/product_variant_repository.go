@@ -0,0 +1,34 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"mini-ecommerce/internal/domain/entities"
+)
+
+var (
+	ErrProductVariantNotFound  = errors.New("product variant not found")
+	ErrDuplicateVariantOptions = errors.New("another variant has the same options")
+	ErrVariantHasOrders        = errors.New("product variant has been ordered")
+)
+
+// ProductVariantRepository manages the variants of a product. While a product
+// has variants its stock quantity is the sum of its active variants' stock,
+// kept up to date on every change.
+type ProductVariantRepository interface {
+	// ListByProductId returns the product's variants in display order, each
+	// with its images.
+	ListByProductId(ctx context.Context, productID int) ([]*entities.ProductVariant, error)
+	GetById(ctx context.Context, productID, id int) (*entities.ProductVariant, error)
+	ExistsBySKU(ctx context.Context, sku string, exceptID int) (bool, error)
+	// Create appends the variant to the product's variants. Options equal to
+	// another variant's fail with ErrDuplicateVariantOptions.
+	Create(ctx context.Context, variant *entities.ProductVariant) error
+	Update(ctx context.Context, variant *entities.ProductVariant) error
+	// UpdateStock sets the stock quantity of the variant and leaves its other
+	// fields unchanged.
+	UpdateStock(ctx context.Context, productID, id, quantity int) error
+	// Delete removes a variant that was never ordered. Its images stay with
+	// the product.
+	Delete(ctx context.Context, productID, id int) error
+}
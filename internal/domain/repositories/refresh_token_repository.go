@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"mini-ecommerce/internal/domain/entities"
+)
+
+var (
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+	ErrRefreshTokenRevoked  = errors.New("refresh token already revoked")
+)
+
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *entities.RefreshToken) error
+	GetByHash(ctx context.Context, tokenHash string) (*entities.RefreshToken, error)
+	// Rotate revokes the old token and stores its replacement in a single transaction.
+	// It returns ErrRefreshTokenRevoked when the old token was already revoked.
+	Rotate(ctx context.Context, oldID int, newToken *entities.RefreshToken) error
+	RevokeFamily(ctx context.Context, familyID string) error
+	RevokeAllByUserId(ctx context.Context, userID int) error
+}
@@ -0,0 +1,28 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"mini-ecommerce/internal/domain/entities"
+	"time"
+)
+
+var ErrSessionNotFound = errors.New("session not found")
+
+type SessionRepository interface {
+	Create(ctx context.Context, session *entities.Session) error
+	GetById(ctx context.Context, id string) (*entities.Session, error)
+	// ListActiveByUserId returns unrevoked, unexpired sessions, most recently active first.
+	ListActiveByUserId(ctx context.Context, userID int) ([]*entities.Session, error)
+	// Extend records a refresh on an active session. It returns ErrSessionNotFound
+	// when the session is missing or revoked.
+	Extend(ctx context.Context, id, ipAddress, userAgent string, expiresAt time.Time) error
+	// TouchLastActivity skips the write when the stored value is newer than the threshold.
+	TouchLastActivity(ctx context.Context, id string, at time.Time, threshold time.Duration) error
+	// Revoke revokes the user's session and its refresh tokens. It returns
+	// ErrSessionNotFound when the user owns no active session with the ID.
+	Revoke(ctx context.Context, userID int, id string) error
+	// RevokeAllByUserId revokes every session and refresh token of the user
+	// except the session exceptID, which may be empty.
+	RevokeAllByUserId(ctx context.Context, userID int, exceptID string) error
+}
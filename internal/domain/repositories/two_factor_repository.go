@@ -0,0 +1,27 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"mini-ecommerce/internal/domain/entities"
+)
+
+var (
+	ErrTwoFactorNotFound    = errors.New("two-factor enrollment not found")
+	ErrTOTPStepReplayed     = errors.New("totp code already used")
+	ErrRecoveryCodeNotFound = errors.New("recovery code not found")
+)
+
+type TwoFactorRepository interface {
+	GetByUserId(ctx context.Context, userID int) (*entities.UserTwoFactor, error)
+	// SavePending stores a new, unconfirmed secret, replacing any previous pending one.
+	SavePending(ctx context.Context, twoFactor *entities.UserTwoFactor) error
+	// Enable confirms the enrollment, flags the user and replaces the recovery codes atomically.
+	Enable(ctx context.Context, userID int, step int64, recoveryCodeHashes []string) error
+	Disable(ctx context.Context, userID int) error
+	ReplaceRecoveryCodes(ctx context.Context, userID int, recoveryCodeHashes []string) error
+	// UseStep records an accepted TOTP step; it returns ErrTOTPStepReplayed for a step already used.
+	UseStep(ctx context.Context, userID int, step int64) error
+	UseRecoveryCode(ctx context.Context, userID int, codeHash string) error
+	CountRecoveryCodes(ctx context.Context, userID int) (int64, error)
+}
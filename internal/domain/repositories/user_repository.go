@@ -5,10 +5,49 @@ import (
 	"mini-ecommerce/internal/domain/entities"
 )
 
+// UserFilter narrows List and Search results. Nil/empty fields are ignored.
+type UserFilter struct {
+	Role     string
+	IsActive *bool
+	Offset   int
+	Limit    int
+}
+
+// UserProfileUpdate lists the profile columns to write; nil fields are left
+// unchanged.
+type UserProfileUpdate struct {
+	Name  *string
+	Phone *string
+	// Email also marks the address as unverified
+	Email *string
+	// Password is a new hash; it also sets the password change time, which
+	// invalidates access tokens issued before
+	Password *string
+}
+
+// The update methods of UserRepository write only their own columns and skip
+// anonymized users, so they never undo a concurrent change to other columns.
+// They fail with "user not found" when no user matches.
 type UserRepository interface {
 	GetById(ctx context.Context, id int) (*entities.User, error)
 	GetByEmail(ctx context.Context, email string) (*entities.User, error)
 	Create(ctx context.Context, user *entities.User) error
-	Update(ctx context.Context, user *entities.User) error
-	Delete(ctx context.Context, id int) error
+	UpdateProfile(ctx context.Context, id int, update UserProfileUpdate) error
+	// UpdatePassword stores a new password hash and the time of the change.
+	UpdatePassword(ctx context.Context, id int, hash string) error
+	// UpgradePasswordHash replaces the hash with one of the current algorithm
+	// without counting as a password change. It does nothing when the hash is
+	// no longer current, as the password changed meanwhile.
+	UpgradePasswordHash(ctx context.Context, id int, current, upgraded string) error
+	SetRole(ctx context.Context, id int, role string) error
+	SetActive(ctx context.Context, id int, active bool) error
+	// MarkEmailVerified verifies the user's email only while it still equals
+	// email, so a link sent to a previous address verifies nothing.
+	MarkEmailVerified(ctx context.Context, id int, email string) error
+	// Anonymize erases the personal data of a user while keeping the row, and
+	// with it the user's orders and payments. Credentials, sessions, API keys,
+	// addresses and carts are deleted.
+	Anonymize(ctx context.Context, id int) error
+	List(ctx context.Context, filter UserFilter) ([]*entities.User, int64, error)
+	Search(ctx context.Context, query string, filter UserFilter) ([]*entities.User, int64, error)
 }
@@ -0,0 +1,47 @@
+package audit
+
+import (
+	"context"
+	"mini-ecommerce/pkg/logger"
+)
+
+const (
+	EventLoginLockout         = "auth.login_lockout"
+	EventPersonalDataExported = "privacy.data_exported"
+	EventAccountErased        = "privacy.account_erased"
+)
+
+// Event is a security relevant action worth keeping a trail of
+type Event struct {
+	Type    string
+	UserID  int
+	Subject string
+	IP      string
+	Details map[string]interface{}
+}
+
+// Recorder persists or forwards audit events
+type Recorder interface {
+	Record(ctx context.Context, event Event)
+}
+
+type logRecorder struct{}
+
+// NewLogRecorder returns a recorder that writes events to the application log
+// tagged with audit=true so they can be routed separately.
+func NewLogRecorder() Recorder {
+	return &logRecorder{}
+}
+
+func (l *logRecorder) Record(ctx context.Context, event Event) {
+	fields := make(map[string]interface{}, len(event.Details)+3)
+	for key, value := range event.Details {
+		fields[key] = value
+	}
+	fields["subject"] = event.Subject
+	fields["ip"] = event.IP
+	if event.UserID != 0 {
+		fields["user_id"] = event.UserID
+	}
+	logger.Audit(event.Type, fields)
+}
@@ -1 +1,140 @@
-package auth
\ No newline at end of file
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrExpiredToken = errors.New("token has expired")
+)
+
+// Claims is the payload carried by access tokens
+type Claims struct {
+	UserID    int    `json:"user_id"`
+	Email     string `json:"email"`
+	Role      string `json:"role"`
+	SessionID string `json:"sid"`
+	jwt.RegisteredClaims
+}
+
+// ActionClaims is the payload of single-purpose tokens such as email verification links
+type ActionClaims struct {
+	UserID  int    `json:"user_id"`
+	Email   string `json:"email"`
+	Purpose string `json:"purpose"`
+	jwt.RegisteredClaims
+}
+
+const (
+	PurposeEmailVerification  = "email_verification"
+	PurposeTwoFactorChallenge = "two_factor_challenge"
+)
+
+type JWTService interface {
+	GenerateToken(userID int, email, role, sessionID string) (string, *Claims, error)
+	ValidateToken(tokenString string) (*Claims, error)
+	GenerateActionToken(purpose string, userID int, email string, ttl time.Duration) (string, error)
+	ValidateActionToken(purpose, tokenString string) (*ActionClaims, error)
+}
+
+type jwtServiceImpl struct {
+	secretKey []byte
+	expire    time.Duration
+}
+
+func NewJWTService(secretKey string, expire time.Duration) JWTService {
+	return &jwtServiceImpl{
+		secretKey: []byte(secretKey),
+		expire:    expire,
+	}
+}
+
+// GenerateToken signs a new access token for the given user session.
+func (j *jwtServiceImpl) GenerateToken(userID int, email, role, sessionID string) (string, *Claims, error) {
+	now := time.Now()
+	claims := &Claims{
+		UserID:    userID,
+		Email:     email,
+		Role:      role,
+		SessionID: sessionID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(j.expire)),
+		},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signed, err := token.SignedString(j.secretKey)
+	if err != nil {
+		return "", nil, err
+	}
+	return signed, claims, nil
+}
+
+// ValidateToken parses the token, checks its signature and expiry and returns its claims.
+func (j *jwtServiceImpl) ValidateToken(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		return j.secretKey, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrExpiredToken
+		}
+		return nil, ErrInvalidToken
+	}
+	if !token.Valid {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+// GenerateActionToken signs a short-lived token that is only valid for the given purpose.
+// Action tokens use a key derived per purpose so they can never pass as access tokens.
+func (j *jwtServiceImpl) GenerateActionToken(purpose string, userID int, email string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := &ActionClaims{
+		UserID:  userID,
+		Email:   email,
+		Purpose: purpose,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(j.actionKey(purpose))
+}
+
+// ValidateActionToken verifies an action token issued for the given purpose.
+func (j *jwtServiceImpl) ValidateActionToken(purpose, tokenString string) (*ActionClaims, error) {
+	claims := &ActionClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		return j.actionKey(purpose), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrExpiredToken
+		}
+		return nil, ErrInvalidToken
+	}
+	if !token.Valid || claims.Purpose != purpose {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+func (j *jwtServiceImpl) actionKey(purpose string) []byte {
+	mac := hmac.New(sha256.New, j.secretKey)
+	mac.Write([]byte("action:" + purpose))
+	return mac.Sum(nil)
+}
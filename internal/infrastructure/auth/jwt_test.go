@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestValidateToken(t *testing.T) {
+	service := NewJWTService("test secret", time.Hour)
+	resign := func(method jwt.SigningMethod, key interface{}) func(t *testing.T) string {
+		return func(t *testing.T) string {
+			_, claims, err := service.GenerateToken(1, "jane@example.com", "customer", "session")
+			if err != nil {
+				t.Fatal(err)
+			}
+			signed, err := jwt.NewWithClaims(method, claims).SignedString(key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return signed
+		}
+	}
+	tests := []struct {
+		name    string
+		token   func(t *testing.T) string
+		wantErr error
+	}{
+		{
+			name: "valid",
+			token: func(t *testing.T) string {
+				signed, _, err := service.GenerateToken(1, "jane@example.com", "customer", "session")
+				if err != nil {
+					t.Fatal(err)
+				}
+				return signed
+			},
+		},
+		{
+			name: "expired",
+			token: func(t *testing.T) string {
+				signed, _, err := NewJWTService("test secret", -time.Minute).GenerateToken(1, "jane@example.com", "customer", "session")
+				if err != nil {
+					t.Fatal(err)
+				}
+				return signed
+			},
+			wantErr: ErrExpiredToken,
+		},
+		{
+			name: "wrong key",
+			token: func(t *testing.T) string {
+				signed, _, err := NewJWTService("other secret", time.Hour).GenerateToken(1, "jane@example.com", "customer", "session")
+				if err != nil {
+					t.Fatal(err)
+				}
+				return signed
+			},
+			wantErr: ErrInvalidToken,
+		},
+		{name: "HS512 with the same key", token: resign(jwt.SigningMethodHS512, []byte("test secret")), wantErr: ErrInvalidToken},
+		{name: "alg none", token: resign(jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType), wantErr: ErrInvalidToken},
+		{
+			name: "action token",
+			token: func(t *testing.T) string {
+				signed, err := service.GenerateActionToken(PurposeEmailVerification, 1, "jane@example.com", time.Hour)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return signed
+			},
+			wantErr: ErrInvalidToken,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := service.ValidateToken(tt.token(t))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ValidateToken() = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (claims.UserID != 1 || claims.Role != "customer" || claims.SessionID != "session") {
+				t.Errorf("claims = %+v, want user 1, role customer, session \"session\"", claims)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ThrottlePolicy controls when repeated login failures lead to a lockout.
+// After MaxAttempts failures within Window the key is locked for BaseLockout,
+// doubling with every further failure up to MaxLockout.
+type ThrottlePolicy struct {
+	MaxAttempts int
+	Window      time.Duration
+	BaseLockout time.Duration
+	MaxLockout  time.Duration
+}
+
+// lockoutFor returns the lockout earned by the given number of consecutive failures.
+func (p ThrottlePolicy) lockoutFor(failures int) time.Duration {
+	if p.MaxAttempts <= 0 || failures < p.MaxAttempts {
+		return 0
+	}
+	lockout := p.BaseLockout
+	for i := p.MaxAttempts; i < failures && lockout < p.MaxLockout; i++ {
+		lockout *= 2
+	}
+	if lockout > p.MaxLockout {
+		lockout = p.MaxLockout
+	}
+	return lockout
+}
+
+// LoginThrottler tracks failed login attempts per key, e.g. an email or a client IP.
+type LoginThrottler interface {
+	// LockedFor returns how long the key is still locked out, or zero.
+	LockedFor(ctx context.Context, key string) (time.Duration, error)
+	// RegisterFailure records a failed attempt and returns the lockout it triggered, if any.
+	RegisterFailure(ctx context.Context, key string) (time.Duration, error)
+	Reset(ctx context.Context, key string) error
+}
+
+type throttleEntry struct {
+	failures    int
+	lastFailure time.Time
+	lockedUntil time.Time
+}
+
+type memoryLoginThrottler struct {
+	mu        sync.Mutex
+	policy    ThrottlePolicy
+	entries   map[string]*throttleEntry
+	lastPrune time.Time
+}
+
+func NewMemoryLoginThrottler(policy ThrottlePolicy) LoginThrottler {
+	return &memoryLoginThrottler{
+		policy:  policy,
+		entries: make(map[string]*throttleEntry),
+	}
+}
+
+func (m *memoryLoginThrottler) LockedFor(ctx context.Context, key string) (time.Duration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, ok := m.entries[key]
+	if !ok {
+		return 0, nil
+	}
+	if remaining := time.Until(entry.lockedUntil); remaining > 0 {
+		return remaining, nil
+	}
+	return 0, nil
+}
+
+func (m *memoryLoginThrottler) RegisterFailure(ctx context.Context, key string) (time.Duration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	m.prune(now)
+	entry, ok := m.entries[key]
+	if !ok || m.expired(entry, now) {
+		entry = &throttleEntry{}
+		m.entries[key] = entry
+	}
+	entry.failures++
+	entry.lastFailure = now
+	lockout := m.policy.lockoutFor(entry.failures)
+	if lockout > 0 {
+		entry.lockedUntil = now.Add(lockout)
+	}
+	return lockout, nil
+}
+
+func (m *memoryLoginThrottler) Reset(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, key)
+	return nil
+}
+
+func (m *memoryLoginThrottler) expired(entry *throttleEntry, now time.Time) bool {
+	return now.After(entry.lockedUntil) && now.Sub(entry.lastFailure) > m.policy.Window
+}
+
+// prune drops stale entries at most once per minute to keep memory bounded.
+func (m *memoryLoginThrottler) prune(now time.Time) {
+	if now.Sub(m.lastPrune) < time.Minute {
+		return
+	}
+	m.lastPrune = now
+	for key, entry := range m.entries {
+		if m.expired(entry, now) {
+			delete(m.entries, key)
+		}
+	}
+}
+
+const (
+	redisThrottleFailPrefix = "auth:login:fail:"
+	redisThrottleLockPrefix = "auth:login:lock:"
+)
+
+type redisLoginThrottler struct {
+	client *redis.Client
+	policy ThrottlePolicy
+}
+
+func NewRedisLoginThrottler(client *redis.Client, policy ThrottlePolicy) LoginThrottler {
+	return &redisLoginThrottler{
+		client: client,
+		policy: policy,
+	}
+}
+
+func (r *redisLoginThrottler) LockedFor(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := r.client.PTTL(ctx, redisThrottleLockPrefix+key).Result()
+	if err != nil {
+		return 0, err
+	}
+	if ttl < 0 {
+		return 0, nil
+	}
+	return ttl, nil
+}
+
+func (r *redisLoginThrottler) RegisterFailure(ctx context.Context, key string) (time.Duration, error) {
+	failKey := redisThrottleFailPrefix + key
+	failures, err := r.client.Incr(ctx, failKey).Result()
+	if err != nil {
+		return 0, err
+	}
+	lockout := r.policy.lockoutFor(int(failures))
+	// Keep the counter alive for the window, or for as long as the lockout lasts.
+	if err := r.client.Expire(ctx, failKey, r.policy.Window+lockout).Err(); err != nil {
+		return 0, err
+	}
+	if lockout > 0 {
+		if err := r.client.Set(ctx, redisThrottleLockPrefix+key, failures, lockout).Err(); err != nil {
+			return 0, err
+		}
+	}
+	return lockout, nil
+}
+
+func (r *redisLoginThrottler) Reset(ctx context.Context, key string) error {
+	return r.client.Del(ctx, redisThrottleFailPrefix+key, redisThrottleLockPrefix+key).Err()
+}
@@ -0,0 +1,77 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestThrottlePolicyLockoutFor(t *testing.T) {
+	policy := ThrottlePolicy{
+		MaxAttempts: 3,
+		Window:      15 * time.Minute,
+		BaseLockout: time.Minute,
+		MaxLockout:  10 * time.Minute,
+	}
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{failures: 1, want: 0},
+		{failures: 2, want: 0},
+		{failures: 3, want: time.Minute},
+		{failures: 4, want: 2 * time.Minute},
+		{failures: 5, want: 4 * time.Minute},
+		{failures: 6, want: 8 * time.Minute},
+		{failures: 7, want: 10 * time.Minute},
+		{failures: 50, want: 10 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := policy.lockoutFor(tt.failures); got != tt.want {
+			t.Errorf("lockoutFor(%d) = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+}
+
+func TestThrottlePolicyDisabled(t *testing.T) {
+	policy := ThrottlePolicy{BaseLockout: time.Minute, MaxLockout: time.Hour}
+	if got := policy.lockoutFor(100); got != 0 {
+		t.Errorf("lockoutFor with MaxAttempts 0 = %v, want 0", got)
+	}
+}
+
+func TestMemoryLoginThrottler(t *testing.T) {
+	ctx := context.Background()
+	throttler := NewMemoryLoginThrottler(ThrottlePolicy{
+		MaxAttempts: 2,
+		Window:      time.Minute,
+		BaseLockout: time.Minute,
+		MaxLockout:  time.Hour,
+	})
+
+	if lockout, _ := throttler.RegisterFailure(ctx, "a@example.com"); lockout != 0 {
+		t.Fatalf("first failure locked out for %v", lockout)
+	}
+	if lockout, _ := throttler.RegisterFailure(ctx, "a@example.com"); lockout != time.Minute {
+		t.Fatalf("second failure locked out for %v, want 1m", lockout)
+	}
+	if lockout, _ := throttler.RegisterFailure(ctx, "a@example.com"); lockout != 2*time.Minute {
+		t.Fatalf("third failure locked out for %v, want 2m", lockout)
+	}
+	if remaining, _ := throttler.LockedFor(ctx, "a@example.com"); remaining <= time.Minute {
+		t.Fatalf("LockedFor = %v, want more than 1m", remaining)
+	}
+	if remaining, _ := throttler.LockedFor(ctx, "b@example.com"); remaining != 0 {
+		t.Fatalf("another key is locked for %v", remaining)
+	}
+
+	if err := throttler.Reset(ctx, "a@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if remaining, _ := throttler.LockedFor(ctx, "a@example.com"); remaining != 0 {
+		t.Fatalf("LockedFor after Reset = %v, want 0", remaining)
+	}
+	if lockout, _ := throttler.RegisterFailure(ctx, "a@example.com"); lockout != 0 {
+		t.Fatalf("failure after Reset locked out for %v", lockout)
+	}
+}
@@ -1,15 +1,219 @@
 package auth
 
 import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/bcrypt"
 )
 
-func HashPassword(password string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+const (
+	AlgorithmArgon2id = "argon2id"
+	AlgorithmBcrypt   = "bcrypt"
+)
+
+var (
+	ErrUnknownHashAlgorithm = errors.New("unknown password hash algorithm")
+	ErrInvalidArgon2Params  = errors.New("invalid argon2id parameters")
+)
+
+// PasswordHasher hashes passwords into self-describing strings that carry the
+// algorithm and its parameters, so stored hashes keep verifying after the
+// configuration changes.
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Verify(password, encodedHash string) bool
+	// NeedsRehash reports whether the hash was produced by another algorithm
+	// or with parameters other than the current ones.
+	NeedsRehash(encodedHash string) bool
+}
+
+// Argon2Params are the argon2id cost parameters. Memory is in KiB.
+type Argon2Params struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultArgon2Params follows the OWASP recommendation for argon2id.
+var DefaultArgon2Params = Argon2Params{
+	Memory:      64 * 1024,
+	Iterations:  3,
+	Parallelism: 2,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+// Validate checks the parameters against the minimums of RFC 9106: argon2
+// needs at least one lane and pass and 8 KiB of memory per lane.
+func (p Argon2Params) Validate() error {
+	switch {
+	case p.Parallelism < 1:
+		return fmt.Errorf("%w: parallelism must be at least 1", ErrInvalidArgon2Params)
+	case p.Iterations < 1:
+		return fmt.Errorf("%w: iterations must be at least 1", ErrInvalidArgon2Params)
+	case p.Memory < 8*uint32(p.Parallelism):
+		return fmt.Errorf("%w: memory must be at least %d KiB", ErrInvalidArgon2Params, 8*uint32(p.Parallelism))
+	case p.SaltLength < 8:
+		return fmt.Errorf("%w: salt length must be at least 8 bytes", ErrInvalidArgon2Params)
+	case p.KeyLength < 16:
+		return fmt.Errorf("%w: key length must be at least 16 bytes", ErrInvalidArgon2Params)
+	}
+	return nil
+}
+
+type hashAlgorithm interface {
+	PasswordHasher
+	// Recognizes reports whether the encoded hash was produced by this algorithm.
+	Recognizes(encodedHash string) bool
+}
+
+type passwordHasherImpl struct {
+	current    hashAlgorithm
+	algorithms []hashAlgorithm
+}
+
+// NewPasswordHasher returns a hasher that creates hashes with the given
+// algorithm and verifies hashes of every supported algorithm.
+func NewPasswordHasher(algorithm string, argon2Params Argon2Params, bcryptCost int) (PasswordHasher, error) {
+	argon := &argon2idHasher{params: argon2Params}
+	bc := &bcryptHasher{cost: bcryptCost}
+	h := &passwordHasherImpl{algorithms: []hashAlgorithm{argon, bc}}
+	switch algorithm {
+	case AlgorithmArgon2id:
+		if err := argon2Params.Validate(); err != nil {
+			return nil, err
+		}
+		h.current = argon
+	case AlgorithmBcrypt:
+		h.current = bc
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownHashAlgorithm, algorithm)
+	}
+	return h, nil
+}
+
+func (h *passwordHasherImpl) Hash(password string) (string, error) {
+	return h.current.Hash(password)
+}
+
+func (h *passwordHasherImpl) Verify(password, encodedHash string) bool {
+	for _, algorithm := range h.algorithms {
+		if algorithm.Recognizes(encodedHash) {
+			return algorithm.Verify(password, encodedHash)
+		}
+	}
+	return false
+}
+
+func (h *passwordHasherImpl) NeedsRehash(encodedHash string) bool {
+	if !h.current.Recognizes(encodedHash) {
+		return true
+	}
+	return h.current.NeedsRehash(encodedHash)
+}
+
+// argon2idHasher encodes hashes in the PHC string format:
+// $argon2id$v=19$m=65536,t=3,p=2$<salt>$<key>
+type argon2idHasher struct {
+	params Argon2Params
+}
+
+func (a *argon2idHasher) Recognizes(encodedHash string) bool {
+	return strings.HasPrefix(encodedHash, "$argon2id$")
+}
+
+func (a *argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, a.params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, a.params.Iterations, a.params.Memory, a.params.Parallelism, a.params.KeyLength)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, a.params.Memory, a.params.Iterations, a.params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+func (a *argon2idHasher) Verify(password, encodedHash string) bool {
+	params, salt, key, err := decodeArgon2id(encodedHash)
+	if err != nil {
+		return false
+	}
+	other := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, params.KeyLength)
+	return subtle.ConstantTimeCompare(key, other) == 1
+}
+
+func (a *argon2idHasher) NeedsRehash(encodedHash string) bool {
+	params, salt, _, err := decodeArgon2id(encodedHash)
+	if err != nil {
+		return true
+	}
+	return params.Memory != a.params.Memory ||
+		params.Iterations != a.params.Iterations ||
+		params.Parallelism != a.params.Parallelism ||
+		params.KeyLength != a.params.KeyLength ||
+		uint32(len(salt)) != a.params.SaltLength
+}
+
+func decodeArgon2id(encodedHash string) (Argon2Params, []byte, []byte, error) {
+	var params Argon2Params
+	parts := strings.Split(encodedHash, "$")
+	if len(parts) != 6 || parts[1] != AlgorithmArgon2id {
+		return params, nil, nil, ErrUnknownHashAlgorithm
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return params, nil, nil, err
+	}
+	if version != argon2.Version {
+		return params, nil, nil, fmt.Errorf("unsupported argon2 version %d", version)
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return params, nil, nil, err
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, err
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return params, nil, nil, err
+	}
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+	return params, salt, key, nil
+}
+
+// bcryptHasher is kept so existing hashes keep verifying. bcrypt only uses the
+// first 72 bytes of a password and rejects longer ones when hashing.
+type bcryptHasher struct {
+	cost int
+}
+
+func (b *bcryptHasher) Recognizes(encodedHash string) bool {
+	return strings.HasPrefix(encodedHash, "$2a$") ||
+		strings.HasPrefix(encodedHash, "$2b$") ||
+		strings.HasPrefix(encodedHash, "$2y$")
+}
+
+func (b *bcryptHasher) Hash(password string) (string, error) {
+	bytes, err := bcrypt.GenerateFromPassword([]byte(password), b.cost)
 	return string(bytes), err
 }
 
-func ComparePassword(password, hash string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
-	return err == nil
+func (b *bcryptHasher) Verify(password, encodedHash string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(encodedHash), []byte(password)) == nil
+}
+
+func (b *bcryptHasher) NeedsRehash(encodedHash string) bool {
+	cost, err := bcrypt.Cost([]byte(encodedHash))
+	return err != nil || cost != b.cost
 }
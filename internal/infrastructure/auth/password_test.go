@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestArgon2ParamsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*Argon2Params)
+		wantErr bool
+	}{
+		{name: "defaults", modify: func(p *Argon2Params) {}},
+		{name: "no parallelism", modify: func(p *Argon2Params) { p.Parallelism = 0 }, wantErr: true},
+		{name: "no iterations", modify: func(p *Argon2Params) { p.Iterations = 0 }, wantErr: true},
+		{name: "memory below 8 KiB per lane", modify: func(p *Argon2Params) { p.Parallelism = 4; p.Memory = 31 }, wantErr: true},
+		{name: "memory of 8 KiB per lane", modify: func(p *Argon2Params) { p.Parallelism = 4; p.Memory = 32 }},
+		{name: "short salt", modify: func(p *Argon2Params) { p.SaltLength = 4 }, wantErr: true},
+		{name: "short key", modify: func(p *Argon2Params) { p.KeyLength = 8 }, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := DefaultArgon2Params
+			tt.modify(&params)
+			err := params.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidArgon2Params) {
+				t.Errorf("Validate() = %v, want ErrInvalidArgon2Params", err)
+			}
+		})
+	}
+}
+
+func TestNewPasswordHasherRejectsInvalidArgon2Params(t *testing.T) {
+	params := DefaultArgon2Params
+	params.Parallelism = 0
+	if _, err := NewPasswordHasher(AlgorithmArgon2id, params, 10); !errors.Is(err, ErrInvalidArgon2Params) {
+		t.Errorf("NewPasswordHasher = %v, want ErrInvalidArgon2Params", err)
+	}
+	// Configured argon2 parameters only matter when argon2id hashes new passwords.
+	if _, err := NewPasswordHasher(AlgorithmBcrypt, params, 10); err != nil {
+		t.Errorf("NewPasswordHasher with bcrypt = %v", err)
+	}
+}
+
+func TestPasswordHasherVerifiesBothAlgorithms(t *testing.T) {
+	params := Argon2Params{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+	argon, err := NewPasswordHasher(AlgorithmArgon2id, params, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bc, err := NewPasswordHasher(AlgorithmBcrypt, params, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bcryptHash, err := bc.Hash("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	argonHash, err := argon.Hash("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !argon.Verify("correct horse", bcryptHash) || !argon.Verify("correct horse", argonHash) {
+		t.Error("a valid password was rejected")
+	}
+	if argon.Verify("wrong horse", argonHash) || argon.Verify("wrong horse", bcryptHash) {
+		t.Error("a wrong password was accepted")
+	}
+	if !argon.NeedsRehash(bcryptHash) || argon.NeedsRehash(argonHash) {
+		t.Error("NeedsRehash should only flag the bcrypt hash")
+	}
+}
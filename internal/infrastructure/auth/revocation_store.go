@@ -0,0 +1,79 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// TokenRevocationStore keeps the IDs (jti) of access tokens revoked before their expiry.
+// Entries only need to live until the token would have expired on its own.
+type TokenRevocationStore interface {
+	Revoke(ctx context.Context, jti string, expiresAt time.Time) error
+	IsRevoked(ctx context.Context, jti string) (bool, error)
+}
+
+type memoryRevocationStore struct {
+	mu      sync.RWMutex
+	revoked map[string]time.Time
+}
+
+// NewMemoryRevocationStore returns a process-local store, suitable for a single instance.
+func NewMemoryRevocationStore() TokenRevocationStore {
+	return &memoryRevocationStore{
+		revoked: make(map[string]time.Time),
+	}
+}
+
+func (m *memoryRevocationStore) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	for id, exp := range m.revoked {
+		if now.After(exp) {
+			delete(m.revoked, id)
+		}
+	}
+	if now.Before(expiresAt) {
+		m.revoked[jti] = expiresAt
+	}
+	return nil
+}
+
+func (m *memoryRevocationStore) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	exp, ok := m.revoked[jti]
+	return ok && time.Now().Before(exp), nil
+}
+
+const redisRevocationPrefix = "auth:revoked:"
+
+type redisRevocationStore struct {
+	client *redis.Client
+}
+
+// NewRedisRevocationStore returns a store shared by every instance using the same Redis.
+func NewRedisRevocationStore(client *redis.Client) TokenRevocationStore {
+	return &redisRevocationStore{
+		client: client,
+	}
+}
+
+func (r *redisRevocationStore) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+	return r.client.Set(ctx, redisRevocationPrefix+jti, 1, ttl).Err()
+}
+
+func (r *redisRevocationStore) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	n, err := r.client.Exists(ctx, redisRevocationPrefix+jti).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
@@ -0,0 +1,61 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryRevocationStore(t *testing.T) {
+	store := NewMemoryRevocationStore()
+	ctx := context.Background()
+	isRevoked := func(jti string) bool {
+		t.Helper()
+		revoked, err := store.IsRevoked(ctx, jti)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return revoked
+	}
+
+	if err := store.Revoke(ctx, "long", time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Revoke(ctx, "short", time.Now().Add(20*time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Revoke(ctx, "expired", time.Now().Add(-time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if !isRevoked("long") || !isRevoked("short") {
+		t.Fatal("tokens are not revoked before they expire")
+	}
+	if isRevoked("expired") {
+		t.Error("a token revoked after its expiry is reported as revoked")
+	}
+	if isRevoked("unknown") {
+		t.Error("an unknown jti is reported as revoked")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if isRevoked("short") {
+		t.Error("a token is still revoked after its expiry")
+	}
+	if !isRevoked("long") {
+		t.Error("a token is no longer revoked before its expiry")
+	}
+	// Revoking prunes the entries whose token has expired.
+	if err := store.Revoke(ctx, "next", time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	revoked := store.(*memoryRevocationStore).revoked
+	if _, ok := revoked["short"]; ok {
+		t.Error("the entry of an expired token was not removed")
+	}
+	if _, ok := revoked["expired"]; ok {
+		t.Error("a token revoked after its expiry was stored")
+	}
+	if len(revoked) != 2 {
+		t.Errorf("store holds %d entries, want 2", len(revoked))
+	}
+}
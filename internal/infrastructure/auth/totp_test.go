@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"testing"
+	"time"
+)
+
+// rfc6238Secret is the SHA-1 key of the RFC 6238 test vectors, base32 encoded
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestValidateTOTP(t *testing.T) {
+	// The RFC vectors have eight digits; six digit codes are their last six.
+	tests := []struct {
+		name     string
+		unix     int64
+		code     string
+		wantStep int64
+		wantOK   bool
+	}{
+		{name: "rfc vector 59", unix: 59, code: "287082", wantStep: 1, wantOK: true},
+		{name: "rfc vector 1111111109", unix: 1111111109, code: "081804", wantStep: 37037036, wantOK: true},
+		{name: "rfc vector 1234567890", unix: 1234567890, code: "005924", wantStep: 41152263, wantOK: true},
+		{name: "rfc vector 2000000000", unix: 2000000000, code: "279037", wantStep: 66666666, wantOK: true},
+		{name: "previous step accepted", unix: 59 + totpPeriod, code: "287082", wantStep: 1, wantOK: true},
+		{name: "next step accepted", unix: 59 - totpPeriod, code: "287082", wantStep: 1, wantOK: true},
+		{name: "two steps late rejected", unix: 59 + 2*totpPeriod, code: "287082"},
+		{name: "wrong code", unix: 59, code: "287083"},
+		{name: "too short", unix: 59, code: "28708"},
+		{name: "eight digits", unix: 59, code: "94287082"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := ValidateTOTP(rfc6238Secret, tt.code, time.Unix(tt.unix, 0))
+			if ok != tt.wantOK || step != tt.wantStep {
+				t.Errorf("ValidateTOTP = (%d, %v), want (%d, %v)", step, ok, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestValidateTOTPSecretFormat(t *testing.T) {
+	at := time.Unix(59, 0)
+	if _, ok := ValidateTOTP(" gezdgnbvgy3tqojqgezdgnbvgy3tqojq ", "287082", at); !ok {
+		t.Error("lowercase secret with spaces around it was rejected")
+	}
+	if _, ok := ValidateTOTP("not base32!", "287082", at); ok {
+		t.Error("invalid secret was accepted")
+	}
+}
+
+func TestGenerateTOTPSecret(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	key, err := totpEncoding.DecodeString(secret)
+	if err != nil || len(key) != 20 {
+		t.Fatalf("secret %q does not decode to 20 bytes: %v", secret, err)
+	}
+	if _, ok := ValidateTOTP(secret, hotp(key, now.Unix()/totpPeriod), now); !ok {
+		t.Error("current code of a generated secret was rejected")
+	}
+}
+
+func TestNormalizeRecoveryCode(t *testing.T) {
+	if got := NormalizeRecoveryCode(" ABCDE fghjk "); got != "abcdefghjk" {
+		t.Errorf("NormalizeRecoveryCode = %q", got)
+	}
+}
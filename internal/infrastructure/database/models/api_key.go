@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+)
+
+// APIKey stores hashed personal API keys
+type APIKey struct {
+	ID         int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID     int        `gorm:"not null;index" json:"user_id"`
+	Name       string     `gorm:"not null;type:varchar(100)" json:"name"`
+	Prefix     string     `gorm:"not null;type:varchar(16)" json:"prefix"`
+	KeyHash    string     `gorm:"uniqueIndex;not null;type:varchar(64)" json:"-"`
+	Scopes     string     `gorm:"not null;type:text;default:''" json:"scopes"`
+	ExpiresAt  *time.Time `gorm:"type:timestamp with time zone" json:"expires_at"`
+	LastUsedAt *time.Time `gorm:"type:timestamp with time zone" json:"last_used_at"`
+	RevokedAt  *time.Time `gorm:"type:timestamp with time zone" json:"revoked_at"`
+	CreatedAt  time.Time  `gorm:"default:now()" json:"created_at"`
+}
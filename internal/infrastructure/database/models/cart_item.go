@@ -9,6 +9,7 @@ type CartItem struct {
 	ID        int       `gorm:"primaryKey;autoIncrement" json:"id"`
 	CartID    int       `gorm:"not null;index" json:"cart_id"`
 	ProductID int       `gorm:"not null;index" json:"product_id"`
+	VariantID *int      `gorm:"type:integer" json:"variant_id"` // Required for products with variants
 	Quantity  int       `gorm:"not null" json:"quantity"`
 	UnitPrice float64   `gorm:"not null;type:decimal(10,2)" json:"unit_price"`
 	AddedAt   time.Time `gorm:"default:now()" json:"added_at"`
@@ -7,11 +7,13 @@ import (
 // Category represents a product category
 type Category struct {
 	ID          int       `gorm:"primaryKey;autoIncrement" json:"id"`
-	Name        string    `gorm:"uniqueIndex;not null;type:varchar(255)" json:"name"`
+	ParentID    *int      `gorm:"index" json:"parent_id"`                 // References categories(id) ON DELETE RESTRICT
+	Name        string    `gorm:"not null;type:varchar(255)" json:"name"` // Unique among siblings
+	Slug        string    `gorm:"uniqueIndex;not null;type:varchar(150)" json:"slug"`
 	Description string    `gorm:"type:text" json:"description"`
 	ImageURL    string    `gorm:"type:varchar(500)" json:"image_url"`
-	IsActive    bool      `gorm:"default:true" json:"is_active"`
+	IsActive    bool      `json:"is_active"` // No gorm default, so inserts keep false
 	SortOrder   int       `gorm:"default:0" json:"sort_order"`
 	CreatedAt   time.Time `gorm:"default:now()" json:"created_at"`
 	UpdatedAt   time.Time `gorm:"default:now()" json:"updated_at"`
-}
\ No newline at end of file
+}
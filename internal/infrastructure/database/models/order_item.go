@@ -9,6 +9,7 @@ type OrderItem struct {
 	ID              int     `gorm:"primaryKey;autoIncrement" json:"id"`
 	OrderID         string  `gorm:"not null;type:varchar(50);index" json:"order_id"`
 	ProductID       int     `gorm:"not null;index" json:"product_id"`
+	VariantID       *int    `gorm:"index" json:"variant_id"` // Set for products with variants
 	ProductName     string  `gorm:"not null;type:varchar(255)" json:"product_name"` // Snapshot at time of order
 	Quantity        int     `gorm:"not null" json:"quantity"`
 	UnitPrice       float64 `gorm:"not null;type:decimal(10,2)" json:"unit_price"`
@@ -0,0 +1,15 @@
+package models
+
+import (
+	"time"
+)
+
+// PasswordResetToken stores hashed single-use password reset tokens
+type PasswordResetToken struct {
+	ID        int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID    int        `gorm:"not null;index" json:"user_id"`
+	TokenHash string     `gorm:"uniqueIndex;not null;type:varchar(64)" json:"-"`
+	ExpiresAt time.Time  `gorm:"not null;type:timestamp with time zone" json:"expires_at"`
+	UsedAt    *time.Time `gorm:"type:timestamp with time zone" json:"used_at"`
+	CreatedAt time.Time  `gorm:"default:now()" json:"created_at"`
+}
@@ -8,6 +8,7 @@ import (
 type ProductImage struct {
 	ID        int       `gorm:"primaryKey;autoIncrement" json:"id"`
 	ProductID int       `gorm:"not null;index" json:"product_id"`
+	VariantID *int      `gorm:"index" json:"variant_id"` // References product_variants(id) ON DELETE SET NULL
 	URL       string    `gorm:"not null;type:varchar(500)" json:"url"`
 	AltText   string    `gorm:"type:varchar(255)" json:"alt_text"`
 	IsPrimary bool      `gorm:"default:false" json:"is_primary"`
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+)
+
+// ProductOption is an option axis of a product, such as size or color
+type ProductOption struct {
+	ID        int    `gorm:"primaryKey;autoIncrement" json:"id"`
+	ProductID int    `gorm:"not null;index" json:"product_id"`
+	Name      string `gorm:"not null;type:varchar(50)" json:"name"` // Unique per product, case-insensitive
+	Position  int    `gorm:"not null;default:0" json:"position"`
+}
+
+// ProductVariant is a purchasable combination of a product's options
+type ProductVariant struct {
+	ID            int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	ProductID     int       `gorm:"not null;index" json:"product_id"`
+	SKU           string    `gorm:"uniqueIndex;not null;type:varchar(100)" json:"sku"`
+	Options       JSONB     `gorm:"not null;type:jsonb" json:"options"` // Unique per product
+	Price         *float64  `gorm:"type:decimal(10,2)" json:"price"`    // NULL uses the product price
+	StockQuantity int       `gorm:"not null;default:0" json:"stock_quantity"`
+	Weight        *float64  `gorm:"type:decimal(8,2)" json:"weight"` // NULL uses the product weight
+	IsActive      bool      `gorm:"not null" json:"is_active"`
+	SortOrder     int       `gorm:"default:0" json:"sort_order"`
+	CreatedAt     time.Time `gorm:"default:now()" json:"created_at"`
+	UpdatedAt     time.Time `gorm:"default:now()" json:"updated_at"`
+}
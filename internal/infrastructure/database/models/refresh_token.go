@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+)
+
+// RefreshToken stores hashed refresh tokens issued per user device
+type RefreshToken struct {
+	ID           int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID       int        `gorm:"not null;index" json:"user_id"`
+	FamilyID     string     `gorm:"not null;type:varchar(36);index" json:"family_id"`
+	TokenHash    string     `gorm:"uniqueIndex;not null;type:varchar(64)" json:"-"`
+	DeviceID     string     `gorm:"type:varchar(255)" json:"device_id"`
+	UserAgent    string     `gorm:"type:varchar(500)" json:"user_agent"`
+	IPAddress    string     `gorm:"type:varchar(45)" json:"ip_address"`
+	ExpiresAt    time.Time  `gorm:"not null;type:timestamp with time zone" json:"expires_at"`
+	RevokedAt    *time.Time `gorm:"type:timestamp with time zone" json:"revoked_at"`
+	ReplacedByID *int       `gorm:"type:integer" json:"replaced_by_id"`
+	CreatedAt    time.Time  `gorm:"default:now()" json:"created_at"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+)
+
+// Session stores signed-in devices; refresh tokens reference it by family_id
+type Session struct {
+	ID             string     `gorm:"primaryKey;type:varchar(36)" json:"id"`
+	UserID         int        `gorm:"not null;index" json:"user_id"`
+	DeviceID       string     `gorm:"type:varchar(255)" json:"device_id"`
+	UserAgent      string     `gorm:"type:varchar(500)" json:"user_agent"`
+	IPAddress      string     `gorm:"type:varchar(45)" json:"ip_address"`
+	LastActivityAt time.Time  `gorm:"not null;type:timestamp with time zone" json:"last_activity_at"`
+	ExpiresAt      time.Time  `gorm:"not null;type:timestamp with time zone" json:"expires_at"`
+	RevokedAt      *time.Time `gorm:"type:timestamp with time zone" json:"revoked_at"`
+	CreatedAt      time.Time  `gorm:"default:now()" json:"created_at"`
+}
@@ -0,0 +1,14 @@
+package models
+
+import (
+	"time"
+)
+
+// SlugAlias keeps a previous slug of a category or product resolving to it
+type SlugAlias struct {
+	ID        int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	Resource  string    `gorm:"not null;type:varchar(20)" json:"resource"` // 'category', 'product'
+	Slug      string    `gorm:"not null;type:varchar(150)" json:"slug"`
+	TargetID  int       `gorm:"not null" json:"target_id"`
+	CreatedAt time.Time `gorm:"default:now()" json:"created_at"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+)
+
+// UserTwoFactor stores the encrypted TOTP secret of a user
+type UserTwoFactor struct {
+	UserID          int        `gorm:"primaryKey" json:"user_id"`
+	SecretEncrypted string     `gorm:"not null;type:text" json:"-"`
+	ConfirmedAt     *time.Time `gorm:"type:timestamp with time zone" json:"confirmed_at"`
+	LastUsedStep    int64      `gorm:"not null;default:0" json:"-"`
+	CreatedAt       time.Time  `gorm:"default:now()" json:"created_at"`
+	UpdatedAt       time.Time  `gorm:"default:now()" json:"updated_at"`
+}
+
+// TwoFactorRecoveryCode is a hashed single-use fallback for a lost authenticator
+type TwoFactorRecoveryCode struct {
+	ID        int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID    int        `gorm:"not null;index" json:"user_id"`
+	CodeHash  string     `gorm:"not null;type:varchar(64)" json:"-"`
+	UsedAt    *time.Time `gorm:"type:timestamp with time zone" json:"used_at"`
+	CreatedAt time.Time  `gorm:"default:now()" json:"created_at"`
+}
@@ -1 +1,193 @@
-package repositories
\ No newline at end of file
+package repositories
+
+import (
+	"context"
+	"errors"
+	"mini-ecommerce/internal/domain/entities"
+	"mini-ecommerce/internal/domain/repositories"
+	"mini-ecommerce/internal/infrastructure/database/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type addressRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewAddressRepositoryImpl(db *gorm.DB) repositories.AddressRepository {
+	return &addressRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *addressRepositoryImpl) ListByUserId(ctx context.Context, userID int) ([]*entities.UserAddress, error) {
+	var addresses []models.UserAddress
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).
+		Order("is_default DESC, id ASC").
+		Find(&addresses).Error
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*entities.UserAddress, 0, len(addresses))
+	for i := range addresses {
+		res = append(res, toAddressEntity(&addresses[i]))
+	}
+	return res, nil
+}
+
+func (r *addressRepositoryImpl) GetById(ctx context.Context, userID, id int) (*entities.UserAddress, error) {
+	var address models.UserAddress
+	err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&address).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.ErrAddressNotFound
+		}
+		return nil, err
+	}
+	return toAddressEntity(&address), nil
+}
+
+func (r *addressRepositoryImpl) Create(ctx context.Context, address *entities.UserAddress) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockAddressBook(tx, address.UserID); err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.Model(&models.UserAddress{}).Where("user_id = ?", address.UserID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			address.IsDefault = true
+		}
+		if address.IsDefault {
+			if err := clearDefaultAddress(tx, address.UserID, 0); err != nil {
+				return err
+			}
+		}
+		addressModel := toAddressModel(address)
+		addressModel.CreatedAt = time.Now()
+		addressModel.UpdatedAt = addressModel.CreatedAt
+		if err := tx.Create(addressModel).Error; err != nil {
+			return err
+		}
+		address.ID = addressModel.ID
+		address.CreatedAt = addressModel.CreatedAt
+		address.UpdatedAt = addressModel.UpdatedAt
+		return nil
+	})
+}
+
+func (r *addressRepositoryImpl) Update(ctx context.Context, address *entities.UserAddress) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockAddressBook(tx, address.UserID); err != nil {
+			return err
+		}
+		if address.IsDefault {
+			if err := clearDefaultAddress(tx, address.UserID, address.ID); err != nil {
+				return err
+			}
+		}
+		addressModel := toAddressModel(address)
+		addressModel.UpdatedAt = time.Now()
+		res := tx.Model(&models.UserAddress{}).
+			Where("id = ? AND user_id = ?", address.ID, address.UserID).
+			Select("label", "recipient_name", "phone", "address_line_1", "address_line_2",
+				"city", "state", "postal_code", "country", "is_default", "updated_at").
+			Updates(addressModel)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return repositories.ErrAddressNotFound
+		}
+		address.UpdatedAt = addressModel.UpdatedAt
+		return nil
+	})
+}
+
+func (r *addressRepositoryImpl) Delete(ctx context.Context, userID, id int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockAddressBook(tx, userID); err != nil {
+			return err
+		}
+		var address models.UserAddress
+		err := tx.Where("id = ? AND user_id = ?", id, userID).First(&address).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return repositories.ErrAddressNotFound
+			}
+			return err
+		}
+		if err := tx.Delete(&models.UserAddress{}, address.ID).Error; err != nil {
+			return err
+		}
+		if !address.IsDefault {
+			return nil
+		}
+		var next models.UserAddress
+		err = tx.Where("user_id = ?", userID).Order("updated_at DESC, id DESC").First(&next).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+		return tx.Model(&models.UserAddress{}).Where("id = ?", next.ID).
+			Updates(map[string]interface{}{"is_default": true, "updated_at": time.Now()}).Error
+	})
+}
+
+// lockAddressBook serializes default changes of one user by locking the
+// owning user row, so concurrent requests cannot both pass the checks and
+// then trip idx_user_addresses_unique_default.
+func lockAddressBook(tx *gorm.DB, userID int) error {
+	var user models.User
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").Where("id = ?", userID).First(&user).Error
+}
+
+func clearDefaultAddress(tx *gorm.DB, userID, exceptID int) error {
+	return tx.Model(&models.UserAddress{}).
+		Where("user_id = ? AND is_default = ? AND id <> ?", userID, true, exceptID).
+		Updates(map[string]interface{}{"is_default": false, "updated_at": time.Now()}).Error
+}
+
+func toAddressModel(address *entities.UserAddress) *models.UserAddress {
+	return &models.UserAddress{
+		ID:            address.ID,
+		UserID:        address.UserID,
+		Label:         address.Label,
+		RecipientName: address.RecipientName,
+		Phone:         address.Phone,
+		AddressLine1:  address.AddressLine1,
+		AddressLine2:  address.AddressLine2,
+		City:          address.City,
+		State:         address.State,
+		PostalCode:    address.PostalCode,
+		Country:       address.Country,
+		IsDefault:     address.IsDefault,
+		CreatedAt:     address.CreatedAt,
+		UpdatedAt:     address.UpdatedAt,
+	}
+}
+
+func toAddressEntity(address *models.UserAddress) *entities.UserAddress {
+	return &entities.UserAddress{
+		ID:            address.ID,
+		UserID:        address.UserID,
+		Label:         address.Label,
+		RecipientName: address.RecipientName,
+		Phone:         address.Phone,
+		AddressLine1:  address.AddressLine1,
+		AddressLine2:  address.AddressLine2,
+		City:          address.City,
+		State:         address.State,
+		PostalCode:    address.PostalCode,
+		Country:       address.Country,
+		IsDefault:     address.IsDefault,
+		CreatedAt:     address.CreatedAt,
+		UpdatedAt:     address.UpdatedAt,
+	}
+}
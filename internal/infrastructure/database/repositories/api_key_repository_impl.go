@@ -0,0 +1,117 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"mini-ecommerce/internal/domain/entities"
+	"mini-ecommerce/internal/domain/repositories"
+	"mini-ecommerce/internal/infrastructure/database/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type apiKeyRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyRepositoryImpl(db *gorm.DB) repositories.APIKeyRepository {
+	return &apiKeyRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *apiKeyRepositoryImpl) Create(ctx context.Context, key *entities.APIKey) error {
+	keyModel := toAPIKeyModel(key)
+	if err := r.db.WithContext(ctx).Create(keyModel).Error; err != nil {
+		return err
+	}
+	key.ID = keyModel.ID
+	key.CreatedAt = keyModel.CreatedAt
+	return nil
+}
+
+func (r *apiKeyRepositoryImpl) GetByHash(ctx context.Context, keyHash string) (*entities.APIKey, error) {
+	var key models.APIKey
+	err := r.db.WithContext(ctx).Where("key_hash = ?", keyHash).First(&key).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.ErrAPIKeyNotFound
+		}
+		return nil, err
+	}
+	return toAPIKeyEntity(&key), nil
+}
+
+func (r *apiKeyRepositoryImpl) ListByUserId(ctx context.Context, userID int) ([]*entities.APIKey, error) {
+	var keys []models.APIKey
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").Find(&keys).Error
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*entities.APIKey, 0, len(keys))
+	for i := range keys {
+		res = append(res, toAPIKeyEntity(&keys[i]))
+	}
+	return res, nil
+}
+
+func (r *apiKeyRepositoryImpl) Revoke(ctx context.Context, userID, id int) error {
+	res := r.db.WithContext(ctx).Model(&models.APIKey{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", time.Now())
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return repositories.ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+func (r *apiKeyRepositoryImpl) TouchLastUsed(ctx context.Context, id int, usedAt time.Time, threshold time.Duration) error {
+	return r.db.WithContext(ctx).Model(&models.APIKey{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, usedAt.Add(-threshold)).
+		Update("last_used_at", usedAt).Error
+}
+
+func toAPIKeyModel(key *entities.APIKey) *models.APIKey {
+	scopes := make([]string, 0, len(key.Scopes))
+	for _, scope := range key.Scopes {
+		scopes = append(scopes, string(scope))
+	}
+	return &models.APIKey{
+		ID:         key.ID,
+		UserID:     key.UserID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		KeyHash:    key.KeyHash,
+		Scopes:     strings.Join(scopes, ","),
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
+
+func toAPIKeyEntity(key *models.APIKey) *entities.APIKey {
+	scopes := []entities.Permission{}
+	if key.Scopes != "" {
+		for _, scope := range strings.Split(key.Scopes, ",") {
+			scopes = append(scopes, entities.Permission(scope))
+		}
+	}
+	return &entities.APIKey{
+		ID:         key.ID,
+		UserID:     key.UserID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		KeyHash:    key.KeyHash,
+		Scopes:     scopes,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
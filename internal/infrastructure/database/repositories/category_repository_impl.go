@@ -1 +1,361 @@
-package repositories
\ No newline at end of file
+package repositories
+
+import (
+	"context"
+	"errors"
+	"mini-ecommerce/internal/domain/entities"
+	"mini-ecommerce/internal/domain/repositories"
+	"mini-ecommerce/internal/infrastructure/database/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// categoryTreeLock is the advisory lock key that serializes moves, so two
+// concurrent moves cannot together create a cycle that neither sees alone.
+const categoryTreeLock = 7301
+
+const categoryColumns = "id, parent_id, name, slug, description, image_url, is_active, sort_order, created_at, updated_at"
+
+type categoryRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewCategoryRepositoryImpl(db *gorm.DB) repositories.CategoryRepository {
+	return &categoryRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *categoryRepositoryImpl) GetById(ctx context.Context, id int) (*entities.Category, error) {
+	var category models.Category
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&category).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.ErrCategoryNotFound
+		}
+		return nil, err
+	}
+	categories, err := r.toEntities(ctx, []models.Category{category})
+	if err != nil {
+		return nil, err
+	}
+	return categories[0], nil
+}
+
+func (r *categoryRepositoryImpl) GetBySlug(ctx context.Context, slug string) (*entities.Category, error) {
+	tx := r.db.WithContext(ctx)
+	var category models.Category
+	err := tx.Where("slug = ?", slug).First(&category).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		id, aliasErr := resolveSlugAlias(tx, slugResourceCategory, slug)
+		if aliasErr != nil {
+			if errors.Is(aliasErr, gorm.ErrRecordNotFound) {
+				return nil, repositories.ErrCategoryNotFound
+			}
+			return nil, aliasErr
+		}
+		return r.GetById(ctx, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	categories, err := r.toEntities(ctx, []models.Category{category})
+	if err != nil {
+		return nil, err
+	}
+	return categories[0], nil
+}
+
+func (r *categoryRepositoryImpl) SlugExists(ctx context.Context, slug string, exceptID int) (bool, error) {
+	return slugTaken(r.db.WithContext(ctx), &models.Category{}, slugResourceCategory, slug, exceptID)
+}
+
+func (r *categoryRepositoryImpl) List(ctx context.Context, filter repositories.CategoryFilter) ([]*entities.Category, int64, error) {
+	tx := r.db.WithContext(ctx).Model(&models.Category{})
+	switch {
+	case filter.RootOnly:
+		tx = tx.Where("parent_id IS NULL")
+	case filter.ParentID != nil:
+		tx = tx.Where("parent_id = ?", *filter.ParentID)
+	}
+	if filter.IsActive != nil {
+		tx = tx.Where("is_active = ?", *filter.IsActive)
+	}
+	tx = tx.Session(&gorm.Session{})
+	var total int64
+	if err := tx.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var categories []models.Category
+	if err := tx.Order("sort_order ASC, name ASC").Offset(filter.Offset).Limit(filter.Limit).Find(&categories).Error; err != nil {
+		return nil, 0, err
+	}
+	result, err := r.toEntities(ctx, categories)
+	if err != nil {
+		return nil, 0, err
+	}
+	return result, total, nil
+}
+
+func (r *categoryRepositoryImpl) ListAll(ctx context.Context, activeOnly bool) ([]*entities.Category, error) {
+	tx := r.db.WithContext(ctx)
+	if activeOnly {
+		tx = tx.Where("is_active = ?", true)
+	}
+	var categories []models.Category
+	if err := tx.Order("sort_order ASC, name ASC").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	return r.toEntities(ctx, categories)
+}
+
+func (r *categoryRepositoryImpl) ListSubtree(ctx context.Context, id int, activeOnly bool) ([]*entities.Category, error) {
+	childFilter := ""
+	if activeOnly {
+		childFilter = "WHERE c.is_active = TRUE"
+	}
+	var categories []models.Category
+	err := r.db.WithContext(ctx).Raw(`
+		WITH RECURSIVE subtree AS (
+			SELECT `+categoryColumns+`, 0 AS depth FROM categories WHERE id = ?
+			UNION ALL
+			SELECT `+prefixColumns("c", categoryColumns)+`, s.depth + 1
+			FROM categories c JOIN subtree s ON c.parent_id = s.id `+childFilter+`
+		)
+		SELECT `+categoryColumns+` FROM subtree ORDER BY depth ASC, sort_order ASC, name ASC`, id).
+		Scan(&categories).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(categories) == 0 {
+		return nil, repositories.ErrCategoryNotFound
+	}
+	return r.toEntities(ctx, categories)
+}
+
+func (r *categoryRepositoryImpl) ListAncestors(ctx context.Context, id int) ([]*entities.Category, error) {
+	var categories []models.Category
+	err := r.db.WithContext(ctx).Raw(`
+		WITH RECURSIVE ancestors AS (
+			SELECT `+categoryColumns+`, 0 AS depth FROM categories WHERE id = ?
+			UNION ALL
+			SELECT `+prefixColumns("c", categoryColumns)+`, a.depth + 1
+			FROM categories c JOIN ancestors a ON c.id = a.parent_id
+		)
+		SELECT `+categoryColumns+` FROM ancestors ORDER BY depth DESC`, id).
+		Scan(&categories).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(categories) == 0 {
+		return nil, repositories.ErrCategoryNotFound
+	}
+	return r.toEntities(ctx, categories)
+}
+
+func (r *categoryRepositoryImpl) ExistsByName(ctx context.Context, parentID *int, name string, exceptID int) (bool, error) {
+	tx := r.db.WithContext(ctx).Model(&models.Category{}).
+		Where("LOWER(name) = ? AND id <> ?", strings.ToLower(name), exceptID)
+	if parentID == nil {
+		tx = tx.Where("parent_id IS NULL")
+	} else {
+		tx = tx.Where("parent_id = ?", *parentID)
+	}
+	var count int64
+	if err := tx.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *categoryRepositoryImpl) Create(ctx context.Context, category *entities.Category) error {
+	categoryModel := toCategoryModel(category)
+	categoryModel.CreatedAt = time.Now()
+	categoryModel.UpdatedAt = categoryModel.CreatedAt
+	if err := r.db.WithContext(ctx).Create(categoryModel).Error; err != nil {
+		return err
+	}
+	category.ID = categoryModel.ID
+	category.CreatedAt = categoryModel.CreatedAt
+	category.UpdatedAt = categoryModel.UpdatedAt
+	return nil
+}
+
+func (r *categoryRepositoryImpl) Update(ctx context.Context, category *entities.Category) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if category.ParentID != nil {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", categoryTreeLock).Error; err != nil {
+				return err
+			}
+			var cyclic bool
+			err := tx.Raw(`
+				WITH RECURSIVE ancestors AS (
+					SELECT id, parent_id FROM categories WHERE id = ?
+					UNION
+					SELECT c.id, c.parent_id FROM categories c JOIN ancestors a ON c.id = a.parent_id
+				)
+				SELECT EXISTS (SELECT 1 FROM ancestors WHERE id = ?)`, *category.ParentID, category.ID).
+				Scan(&cyclic).Error
+			if err != nil {
+				return err
+			}
+			if cyclic {
+				return repositories.ErrCategoryCycle
+			}
+		}
+		var current models.Category
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "slug").
+			Where("id = ?", category.ID).First(&current).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return repositories.ErrCategoryNotFound
+			}
+			return err
+		}
+		if err := keepSlugAlias(tx, slugResourceCategory, current.Slug, category.Slug, category.ID); err != nil {
+			return err
+		}
+		categoryModel := toCategoryModel(category)
+		categoryModel.UpdatedAt = time.Now()
+		res := tx.Model(&models.Category{}).Where("id = ?", category.ID).
+			Select("parent_id", "name", "slug", "description", "image_url", "is_active", "sort_order", "updated_at").
+			Updates(categoryModel)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return repositories.ErrCategoryNotFound
+		}
+		category.UpdatedAt = categoryModel.UpdatedAt
+		return nil
+	})
+}
+
+func (r *categoryRepositoryImpl) Reorder(ctx context.Context, parentID *int, ids []int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Moves change the set of siblings, so they must not interleave.
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", categoryTreeLock).Error; err != nil {
+			return err
+		}
+		siblings := tx.Model(&models.Category{}).Clauses(clause.Locking{Strength: "UPDATE"})
+		if parentID == nil {
+			siblings = siblings.Where("parent_id IS NULL")
+		} else {
+			siblings = siblings.Where("parent_id = ?", *parentID)
+		}
+		var current []int
+		if err := siblings.Order("id").Pluck("id", &current).Error; err != nil {
+			return err
+		}
+		if !sameIDSet(current, ids) {
+			return repositories.ErrCategoryOrderMismatch
+		}
+		return applySortOrder(tx, "categories", ids, "updated_at = NOW()")
+	})
+}
+
+func (r *categoryRepositoryImpl) Delete(ctx context.Context, id int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var category models.Category
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&category).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return repositories.ErrCategoryNotFound
+			}
+			return err
+		}
+		var children int64
+		if err := tx.Model(&models.Category{}).Where("parent_id = ?", id).Count(&children).Error; err != nil {
+			return err
+		}
+		if children > 0 {
+			return repositories.ErrCategoryHasChildren
+		}
+		var products int64
+		if err := tx.Model(&models.Product{}).Where("category_id = ?", id).Count(&products).Error; err != nil {
+			return err
+		}
+		if products > 0 {
+			return repositories.ErrCategoryHasProducts
+		}
+		if err := tx.Where("resource = ? AND target_id = ?", slugResourceCategory, id).Delete(&models.SlugAlias{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Category{}, id).Error
+	})
+}
+
+// toEntities maps the categories and fills in their product counts.
+func (r *categoryRepositoryImpl) toEntities(ctx context.Context, categories []models.Category) ([]*entities.Category, error) {
+	result := make([]*entities.Category, 0, len(categories))
+	if len(categories) == 0 {
+		return result, nil
+	}
+	ids := make([]int, 0, len(categories))
+	for i := range categories {
+		ids = append(ids, categories[i].ID)
+	}
+	var counts []struct {
+		CategoryID int
+		Count      int64
+	}
+	err := r.db.WithContext(ctx).Model(&models.Product{}).
+		Select("category_id, COUNT(*) AS count").
+		Where("category_id IN ?", ids).
+		Group("category_id").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+	countByID := make(map[int]int64, len(counts))
+	for _, count := range counts {
+		countByID[count.CategoryID] = count.Count
+	}
+	for i := range categories {
+		category := toCategoryEntity(&categories[i])
+		category.ProductCount = countByID[category.ID]
+		result = append(result, category)
+	}
+	return result, nil
+}
+
+func prefixColumns(alias, columns string) string {
+	parts := strings.Split(columns, ", ")
+	for i, column := range parts {
+		parts[i] = alias + "." + column
+	}
+	return strings.Join(parts, ", ")
+}
+
+func toCategoryModel(category *entities.Category) *models.Category {
+	return &models.Category{
+		ID:          category.ID,
+		ParentID:    category.ParentID,
+		Name:        category.Name,
+		Slug:        category.Slug,
+		Description: category.Description,
+		ImageURL:    category.ImageURL,
+		IsActive:    category.IsActive,
+		SortOrder:   category.SortOrder,
+		CreatedAt:   category.CreatedAt,
+		UpdatedAt:   category.UpdatedAt,
+	}
+}
+
+func toCategoryEntity(category *models.Category) *entities.Category {
+	return &entities.Category{
+		ID:          category.ID,
+		ParentID:    category.ParentID,
+		Name:        category.Name,
+		Slug:        category.Slug,
+		Description: category.Description,
+		ImageURL:    category.ImageURL,
+		IsActive:    category.IsActive,
+		SortOrder:   category.SortOrder,
+		CreatedAt:   category.CreatedAt,
+		UpdatedAt:   category.UpdatedAt,
+	}
+}
@@ -0,0 +1,163 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// recordedStatement is a statement sent to a fakeDB with its arguments.
+type recordedStatement struct {
+	query string
+	args  []interface{}
+}
+
+// fakeDB is a database/sql connector that records statements instead of
+// running them, for tests of the SQL a repository sends. Every statement
+// succeeds; an INSERT returns id 1 and the column defaults of the schema for
+// its RETURNING columns, other queries return no rows.
+type fakeDB struct {
+	mu         sync.Mutex
+	statements []recordedStatement
+}
+
+// newFakeGormDB opens gorm with the postgres dialect on a fakeDB.
+func newFakeGormDB(t *testing.T) (*gorm.DB, *fakeDB) {
+	t.Helper()
+	fake := &fakeDB{}
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sql.OpenDB(fake)}), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db, fake
+}
+
+// find returns the recorded statements starting with prefix, ignoring
+// leading whitespace.
+func (f *fakeDB) find(prefix string) []recordedStatement {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var found []recordedStatement
+	for _, statement := range f.statements {
+		if strings.HasPrefix(strings.TrimSpace(statement.query), prefix) {
+			found = append(found, statement)
+		}
+	}
+	return found
+}
+
+func (f *fakeDB) record(query string, args []driver.NamedValue) {
+	values := make([]interface{}, 0, len(args))
+	for _, arg := range args {
+		values = append(values, arg.Value)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.statements = append(f.statements, recordedStatement{query: query, args: values})
+}
+
+func (f *fakeDB) Connect(ctx context.Context) (driver.Conn, error) {
+	return &fakeConn{db: f}, nil
+}
+
+func (f *fakeDB) Driver() driver.Driver {
+	return fakeDriver{db: f}
+}
+
+type fakeDriver struct {
+	db *fakeDB
+}
+
+func (d fakeDriver) Open(name string) (driver.Conn, error) {
+	return &fakeConn{db: d.db}, nil
+}
+
+type fakeConn struct {
+	db *fakeDB
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("fake database does not prepare statements")
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return fakeTx{}, nil
+}
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.db.record(query, args)
+	return driver.RowsAffected(1), nil
+}
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.db.record(query, args)
+	rows := &fakeRows{}
+	if _, returning, ok := strings.Cut(query, " RETURNING "); ok && strings.HasPrefix(query, "INSERT") {
+		row := make([]driver.Value, 0)
+		for _, column := range strings.Split(returning, ",") {
+			column = strings.Trim(strings.TrimSpace(column), `"`)
+			rows.columns = append(rows.columns, column)
+			row = append(row, columnDefault(column))
+		}
+		rows.rows = [][]driver.Value{row}
+	}
+	return rows, nil
+}
+
+// columnDefault is the value the schema fills in for an omitted column.
+func columnDefault(column string) driver.Value {
+	switch column {
+	case "id":
+		return int64(1)
+	case "is_active":
+		return true
+	case "created_at", "updated_at":
+		return time.Now()
+	}
+	return int64(0)
+}
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error {
+	return nil
+}
+
+func (fakeTx) Rollback() error {
+	return nil
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string {
+	return r.columns
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
@@ -0,0 +1,24 @@
+package repositories
+
+import (
+	"mini-ecommerce/internal/infrastructure/database/models"
+	"sync"
+	"testing"
+
+	"gorm.io/gorm/schema"
+)
+
+// TestInactiveIsInserted guards against a gorm default on is_active: gorm
+// leaves zero values with a default out of an INSERT, so a record created
+// inactive would get the column default, true.
+func TestInactiveIsInserted(t *testing.T) {
+	for _, model := range []interface{}{&models.Category{}, &models.Product{}} {
+		s, err := schema.Parse(model, &sync.Map{}, schema.NamingStrategy{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if field := s.LookUpField("is_active"); field == nil || field.HasDefaultValue {
+			t.Errorf("%s.is_active has a gorm default or is missing", s.Table)
+		}
+	}
+}
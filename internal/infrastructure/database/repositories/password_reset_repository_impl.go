@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"mini-ecommerce/internal/domain/entities"
+	"mini-ecommerce/internal/domain/repositories"
+	"mini-ecommerce/internal/infrastructure/database/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type passwordResetRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewPasswordResetRepositoryImpl(db *gorm.DB) repositories.PasswordResetRepository {
+	return &passwordResetRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *passwordResetRepositoryImpl) Create(ctx context.Context, token *entities.PasswordResetToken) error {
+	tokenModel := &models.PasswordResetToken{
+		UserID:    token.UserID,
+		TokenHash: token.TokenHash,
+		ExpiresAt: token.ExpiresAt,
+		CreatedAt: time.Now(),
+	}
+	if err := r.db.WithContext(ctx).Create(tokenModel).Error; err != nil {
+		return err
+	}
+	token.ID = tokenModel.ID
+	token.CreatedAt = tokenModel.CreatedAt
+	return nil
+}
+
+func (r *passwordResetRepositoryImpl) GetByHash(ctx context.Context, tokenHash string) (*entities.PasswordResetToken, error) {
+	var token models.PasswordResetToken
+	err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.ErrPasswordResetTokenNotFound
+		}
+		return nil, err
+	}
+	return &entities.PasswordResetToken{
+		ID:        token.ID,
+		UserID:    token.UserID,
+		TokenHash: token.TokenHash,
+		ExpiresAt: token.ExpiresAt,
+		UsedAt:    token.UsedAt,
+		CreatedAt: token.CreatedAt,
+	}, nil
+}
+
+func (r *passwordResetRepositoryImpl) MarkUsed(ctx context.Context, id int) error {
+	res := r.db.WithContext(ctx).Model(&models.PasswordResetToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return repositories.ErrPasswordResetTokenUsed
+	}
+	return nil
+}
+
+func (r *passwordResetRepositoryImpl) InvalidateByUserId(ctx context.Context, userID int) error {
+	return r.db.WithContext(ctx).Model(&models.PasswordResetToken{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", time.Now()).Error
+}
@@ -0,0 +1,129 @@
+package repositories
+
+import (
+	"context"
+	"mini-ecommerce/internal/domain/entities"
+	"mini-ecommerce/internal/domain/repositories"
+	"mini-ecommerce/internal/infrastructure/database/models"
+
+	"gorm.io/gorm"
+)
+
+type personalDataRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewPersonalDataRepositoryImpl(db *gorm.DB) repositories.PersonalDataRepository {
+	return &personalDataRepositoryImpl{
+		db: db,
+	}
+}
+
+// GetByUserId implements repositories.PersonalDataRepository.
+// Tables created by later migrations are skipped when they do not exist yet.
+func (r *personalDataRepositoryImpl) GetByUserId(ctx context.Context, userID int) (*entities.PersonalData, error) {
+	tx := r.db.WithContext(ctx)
+	data := &entities.PersonalData{OrderItems: make(map[string][]*entities.OrderItem)}
+
+	if tx.Migrator().HasTable(&models.Order{}) {
+		var orders []models.Order
+		if err := tx.Where("user_id = ?", userID).Order("created_at ASC").Find(&orders).Error; err != nil {
+			return nil, err
+		}
+		orderIDs := make([]string, 0, len(orders))
+		for i := range orders {
+			data.Orders = append(data.Orders, toOrderEntity(&orders[i]))
+			orderIDs = append(orderIDs, orders[i].ID)
+		}
+		if len(orderIDs) > 0 {
+			var items []models.OrderItem
+			if err := tx.Where("order_id IN ?", orderIDs).Order("id ASC").Find(&items).Error; err != nil {
+				return nil, err
+			}
+			for i := range items {
+				data.OrderItems[items[i].OrderID] = append(data.OrderItems[items[i].OrderID], toOrderItemEntity(&items[i]))
+			}
+			if tx.Migrator().HasTable(&models.Payment{}) {
+				var payments []models.Payment
+				if err := tx.Where("order_id IN ?", orderIDs).Order("created_at ASC").Find(&payments).Error; err != nil {
+					return nil, err
+				}
+				for i := range payments {
+					data.Payments = append(data.Payments, toPaymentEntity(&payments[i]))
+				}
+			}
+		}
+	}
+
+	if tx.Migrator().HasTable(&models.ProductReview{}) {
+		var reviews []models.ProductReview
+		if err := tx.Where("user_id = ?", userID).Order("created_at ASC").Find(&reviews).Error; err != nil {
+			return nil, err
+		}
+		for i := range reviews {
+			data.Reviews = append(data.Reviews, toReviewEntity(&reviews[i]))
+		}
+	}
+	return data, nil
+}
+
+func toOrderEntity(order *models.Order) *entities.Order {
+	return &entities.Order{
+		ID:              order.ID,
+		UserID:          order.UserID,
+		Status:          order.Status,
+		Subtotal:        order.Subtotal,
+		ShippingCost:    order.ShippingCost,
+		TaxAmount:       order.TaxAmount,
+		TotalAmount:     order.TotalAmount,
+		PaymentMethod:   order.PaymentMethod,
+		PaymentStatus:   order.PaymentStatus,
+		ShippingAddress: order.ShippingAddress,
+		TrackingNumber:  order.TrackingNumber,
+		Notes:           order.Notes,
+		CreatedAt:       order.CreatedAt,
+	}
+}
+
+func toOrderItemEntity(item *models.OrderItem) *entities.OrderItem {
+	return &entities.OrderItem{
+		ID:              item.ID,
+		OrderID:         item.OrderID,
+		ProductID:       item.ProductID,
+		ProductName:     item.ProductName,
+		Quantity:        item.Quantity,
+		UnitPrice:       item.UnitPrice,
+		TotalPrice:      item.TotalPrice,
+		ProductSnapshot: item.ProductSnapshot,
+	}
+}
+
+func toPaymentEntity(payment *models.Payment) *entities.Payment {
+	return &entities.Payment{
+		ID:              payment.ID,
+		OrderID:         payment.OrderID,
+		Amount:          payment.Amount,
+		PaymentMethod:   payment.PaymentMethod,
+		Status:          payment.Status,
+		TransactionID:   payment.TransactionID,
+		PaymentDetails:  payment.PaymentDetails,
+		GatewayResponse: payment.GatewayResponse,
+		CreatedAt:       payment.CreatedAt,
+	}
+}
+
+func toReviewEntity(review *models.ProductReview) *entities.ProductReview {
+	return &entities.ProductReview{
+		ID:                 review.ID,
+		ProductID:          review.ProductID,
+		UserID:             review.UserID,
+		OrderItemID:        review.OrderItemID,
+		Rating:             review.Rating,
+		Title:              review.Title,
+		ReviewText:         review.ReviewText,
+		IsVerifiedPurchase: review.IsVerifiedPurchase,
+		IsPublished:        review.IsPublished,
+		HelpfulCount:       review.HelpfulCount,
+		CreatedAt:          review.CreatedAt,
+	}
+}
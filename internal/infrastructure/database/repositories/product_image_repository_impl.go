@@ -0,0 +1,186 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"mini-ecommerce/internal/domain/entities"
+	"mini-ecommerce/internal/domain/repositories"
+	"mini-ecommerce/internal/infrastructure/database/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type productImageRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewProductImageRepositoryImpl(db *gorm.DB) repositories.ProductImageRepository {
+	return &productImageRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *productImageRepositoryImpl) ListByProductId(ctx context.Context, productID int) ([]*entities.ProductImage, error) {
+	var images []models.ProductImage
+	err := r.db.WithContext(ctx).Where("product_id = ?", productID).
+		Order("sort_order ASC, id ASC").Find(&images).Error
+	if err != nil {
+		return nil, err
+	}
+	return toProductImageEntities(images), nil
+}
+
+func (r *productImageRepositoryImpl) Create(ctx context.Context, image *entities.ProductImage) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := lockProduct(tx, image.ProductID); err != nil {
+			return err
+		}
+		if image.VariantID != nil {
+			var count int64
+			err := tx.Model(&models.ProductVariant{}).
+				Where("id = ? AND product_id = ?", *image.VariantID, image.ProductID).Count(&count).Error
+			if err != nil {
+				return err
+			}
+			if count == 0 {
+				return repositories.ErrProductVariantNotFound
+			}
+		}
+		var last struct {
+			Count        int64
+			MaxSortOrder int
+		}
+		err := tx.Model(&models.ProductImage{}).
+			Select("COUNT(*) AS count, COALESCE(MAX(sort_order), 0) AS max_sort_order").
+			Where("product_id = ?", image.ProductID).
+			Scan(&last).Error
+		if err != nil {
+			return err
+		}
+		if last.Count == 0 {
+			image.IsPrimary = true
+		} else if image.IsPrimary {
+			if err := clearPrimaryImage(tx, image.ProductID); err != nil {
+				return err
+			}
+		}
+		image.SortOrder = last.MaxSortOrder + 1
+		imageModel := toProductImageModel(image)
+		imageModel.CreatedAt = time.Now()
+		if err := tx.Create(imageModel).Error; err != nil {
+			return err
+		}
+		image.ID = imageModel.ID
+		image.CreatedAt = imageModel.CreatedAt
+		return nil
+	})
+}
+
+func (r *productImageRepositoryImpl) SetPrimary(ctx context.Context, productID, id int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := lockProduct(tx, productID); err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.Model(&models.ProductImage{}).Where("id = ? AND product_id = ?", id, productID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return repositories.ErrProductImageNotFound
+		}
+		if err := clearPrimaryImage(tx, productID); err != nil {
+			return err
+		}
+		return tx.Model(&models.ProductImage{}).Where("id = ?", id).Update("is_primary", true).Error
+	})
+}
+
+func (r *productImageRepositoryImpl) Delete(ctx context.Context, productID, id int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := lockProduct(tx, productID); err != nil {
+			return err
+		}
+		var image models.ProductImage
+		err := tx.Where("id = ? AND product_id = ?", id, productID).First(&image).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return repositories.ErrProductImageNotFound
+			}
+			return err
+		}
+		if err := tx.Delete(&models.ProductImage{}, id).Error; err != nil {
+			return err
+		}
+		if !image.IsPrimary {
+			return nil
+		}
+		var next models.ProductImage
+		err = tx.Where("product_id = ?", productID).Order("sort_order ASC, id ASC").First(&next).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return tx.Model(&models.ProductImage{}).Where("id = ?", next.ID).Update("is_primary", true).Error
+	})
+}
+
+func (r *productImageRepositoryImpl) Reorder(ctx context.Context, productID int, ids []int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := lockProduct(tx, productID); err != nil {
+			return err
+		}
+		var current []int
+		if err := tx.Model(&models.ProductImage{}).Where("product_id = ?", productID).Order("id").Pluck("id", &current).Error; err != nil {
+			return err
+		}
+		if !sameIDSet(current, ids) {
+			return repositories.ErrImageOrderMismatch
+		}
+		return applySortOrder(tx, "product_images", ids, "")
+	})
+}
+
+// clearPrimaryImage demotes the product's primary image. It must run before
+// another image is promoted, as the unique index allows one primary image.
+func clearPrimaryImage(tx *gorm.DB, productID int) error {
+	return tx.Model(&models.ProductImage{}).
+		Where("product_id = ? AND is_primary = ?", productID, true).
+		Update("is_primary", false).Error
+}
+
+func toProductImageModel(image *entities.ProductImage) *models.ProductImage {
+	return &models.ProductImage{
+		ID:        image.ID,
+		ProductID: image.ProductID,
+		VariantID: image.VariantID,
+		URL:       image.URL,
+		AltText:   image.AltText,
+		IsPrimary: image.IsPrimary,
+		SortOrder: image.SortOrder,
+		CreatedAt: image.CreatedAt,
+	}
+}
+
+func toProductImageEntity(image *models.ProductImage) *entities.ProductImage {
+	return &entities.ProductImage{
+		ID:        image.ID,
+		ProductID: image.ProductID,
+		VariantID: image.VariantID,
+		URL:       image.URL,
+		AltText:   image.AltText,
+		IsPrimary: image.IsPrimary,
+		SortOrder: image.SortOrder,
+		CreatedAt: image.CreatedAt,
+	}
+}
+
+func toProductImageEntities(images []models.ProductImage) []*entities.ProductImage {
+	result := make([]*entities.ProductImage, 0, len(images))
+	for i := range images {
+		result = append(result, toProductImageEntity(&images[i]))
+	}
+	return result
+}
@@ -0,0 +1,204 @@
+package repositories
+
+import (
+	"fmt"
+	"mini-ecommerce/internal/domain/entities"
+	"mini-ecommerce/internal/domain/repositories"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxSpecFacetValues limits the values listed per specification key
+const maxSpecFacetValues = 20
+
+// productSortColumns whitelists the fields product listings can be sorted by.
+var productSortColumns = map[string]string{
+	"name":           "name",
+	"price":          "price",
+	"created_at":     "created_at",
+	"updated_at":     "updated_at",
+	"stock_quantity": "stock_quantity",
+}
+
+// productPrices selects the prices a product sells at: those of its active
+// variants, or its own price when it has none. It refers to the outer
+// products row, so FROM needs it as a LATERAL subquery.
+const productPrices = `SELECT COALESCE(product_variants.price, products.price) AS price
+	FROM product_variants
+	WHERE product_variants.product_id = products.id AND product_variants.is_active
+	UNION ALL
+	SELECT products.price
+	WHERE NOT EXISTS (SELECT 1 FROM product_variants
+		WHERE product_variants.product_id = products.id AND product_variants.is_active)`
+
+// objectSpecifications guards jsonb_each against rows whose specifications
+// are not a JSON object.
+const objectSpecifications = `CASE WHEN jsonb_typeof(products.specifications) = 'object' THEN products.specifications ELSE '{}'::jsonb END`
+
+// applyProductFilter adds the filter's conditions to a query on products.
+// Columns are qualified so the query can join other tables.
+func applyProductFilter(tx *gorm.DB, filter repositories.ProductFilter) *gorm.DB {
+	if filter.CategoryID != nil {
+		tx = tx.Where("products.category_id = ?", *filter.CategoryID)
+	}
+	if filter.Search != "" {
+		pattern := "%" + filter.Search + "%"
+		tx = tx.Where("(products.name ILIKE ? OR products.description ILIKE ?)", pattern, pattern)
+	}
+	if filter.MinPrice != nil || filter.MaxPrice != nil {
+		tx = applyPriceFilter(tx, filter.MinPrice, filter.MaxPrice)
+	}
+	if filter.IsActive != nil {
+		tx = tx.Where("products.is_active = ?", *filter.IsActive)
+	}
+	if filter.InStock {
+		tx = tx.Where("products.stock_quantity > 0")
+	}
+	for _, key := range sortedSpecKeys(filter.Specs) {
+		tx = tx.Where("products.specifications ->> ? IN ?", key, filter.Specs[key])
+	}
+	return tx
+}
+
+// applyPriceFilter keeps the products selling at a price within the limits,
+// so a product matches when one of its variants does.
+func applyPriceFilter(tx *gorm.DB, minPrice, maxPrice *float64) *gorm.DB {
+	conditions := make([]string, 0, 2)
+	args := make([]interface{}, 0, 2)
+	if minPrice != nil {
+		conditions = append(conditions, "prices.price >= ?")
+		args = append(args, *minPrice)
+	}
+	if maxPrice != nil {
+		conditions = append(conditions, "prices.price <= ?")
+		args = append(args, *maxPrice)
+	}
+	return tx.Where("EXISTS (SELECT 1 FROM ("+productPrices+") AS prices WHERE "+strings.Join(conditions, " AND ")+")", args...)
+}
+
+// productPriceOrder orders products by their lowest price ascending and by
+// their highest price descending, the end of the price range a shopper
+// sorting that way looks at.
+func productPriceOrder(desc bool) clause.OrderByColumn {
+	bound := "MIN"
+	if desc {
+		bound = "MAX"
+	}
+	return clause.OrderByColumn{
+		Column: clause.Column{Name: "(SELECT " + bound + "(prices.price) FROM (" + productPrices + ") AS prices)", Raw: true},
+		Desc:   desc,
+	}
+}
+
+// countPriceRanges counts products per price range, ignoring the filter's own
+// price limits. A product is counted in every range one of its prices falls
+// in. Empty ranges are left out.
+func countPriceRanges(tx *gorm.DB, filter repositories.ProductFilter, bounds []float64) ([]*entities.PriceRangeFacet, error) {
+	filter.MinPrice, filter.MaxPrice = nil, nil
+	var bucket strings.Builder
+	args := make([]interface{}, 0, len(bounds))
+	bucket.WriteString("CASE")
+	for i, bound := range bounds {
+		fmt.Fprintf(&bucket, " WHEN prices.price < ? THEN %d", i)
+		args = append(args, bound)
+	}
+	fmt.Fprintf(&bucket, " ELSE %d END", len(bounds))
+
+	var counts []struct {
+		Bucket int
+		Count  int64
+	}
+	err := applyProductFilter(tx.Table("products, LATERAL ("+productPrices+") AS prices"), filter).
+		Select(bucket.String()+" AS bucket, COUNT(DISTINCT products.id) AS count", args...).
+		Group("bucket").
+		Order("bucket ASC").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+	ranges := make([]*entities.PriceRangeFacet, 0, len(counts))
+	for _, count := range counts {
+		facet := &entities.PriceRangeFacet{Count: count.Count}
+		if count.Bucket > 0 {
+			facet.Min = bounds[count.Bucket-1]
+		}
+		if count.Bucket < len(bounds) {
+			max := bounds[count.Bucket]
+			facet.Max = &max
+		}
+		ranges = append(ranges, facet)
+	}
+	return ranges, nil
+}
+
+// countSpecValues counts products per scalar specification value. A key the
+// filter constrains is counted without its own constraint, the other keys
+// with the full filter.
+func countSpecValues(tx *gorm.DB, filter repositories.ProductFilter) ([]*entities.SpecFacet, error) {
+	filteredKeys := sortedSpecKeys(filter.Specs)
+	facets, err := querySpecValues(tx, filter, func(q *gorm.DB) *gorm.DB {
+		if len(filteredKeys) == 0 {
+			return q
+		}
+		return q.Where("spec.key NOT IN ?", filteredKeys)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range filteredKeys {
+		withoutKey := filter
+		withoutKey.Specs = make(map[string][]string, len(filter.Specs)-1)
+		for k, values := range filter.Specs {
+			if k != key {
+				withoutKey.Specs[k] = values
+			}
+		}
+		keyFacets, err := querySpecValues(tx, withoutKey, func(q *gorm.DB) *gorm.DB {
+			return q.Where("spec.key = ?", key)
+		})
+		if err != nil {
+			return nil, err
+		}
+		facets = append(facets, keyFacets...)
+	}
+	sort.SliceStable(facets, func(i, j int) bool {
+		return facets[i].Key < facets[j].Key
+	})
+	return facets, nil
+}
+
+func querySpecValues(tx *gorm.DB, filter repositories.ProductFilter, keys func(*gorm.DB) *gorm.DB) ([]*entities.SpecFacet, error) {
+	var rows []*entities.SpecFacet
+	q := applyProductFilter(tx.Table("products, jsonb_each("+objectSpecifications+") AS spec"), filter).
+		Select("spec.key AS key, spec.value #>> '{}' AS value, COUNT(*) AS count").
+		Where("jsonb_typeof(spec.value) IN ?", []string{"string", "number", "boolean"})
+	err := keys(q).
+		Group("spec.key, spec.value #>> '{}'").
+		Order("spec.key ASC, count DESC, spec.value #>> '{}' ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	facets := make([]*entities.SpecFacet, 0, len(rows))
+	perKey := make(map[string]int)
+	for _, row := range rows {
+		if perKey[row.Key] >= maxSpecFacetValues {
+			continue
+		}
+		perKey[row.Key]++
+		facets = append(facets, row)
+	}
+	return facets, nil
+}
+
+func sortedSpecKeys(specs map[string][]string) []string {
+	keys := make([]string, 0, len(specs))
+	for key := range specs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -1 +1,486 @@
-package repositories
\ No newline at end of file
+package repositories
+
+import (
+	"context"
+	"errors"
+	"mini-ecommerce/internal/domain/entities"
+	"mini-ecommerce/internal/domain/repositories"
+	"mini-ecommerce/internal/infrastructure/database/models"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type productRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewProductRepositoryImpl(db *gorm.DB) repositories.ProductRepository {
+	return &productRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *productRepositoryImpl) GetById(ctx context.Context, id int) (*entities.Product, error) {
+	var product models.Product
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&product).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.ErrProductNotFound
+		}
+		return nil, err
+	}
+	products, err := r.toEntities(ctx, []models.Product{product}, false)
+	if err != nil {
+		return nil, err
+	}
+	return products[0], nil
+}
+
+func (r *productRepositoryImpl) GetBySlug(ctx context.Context, slug string) (*entities.Product, error) {
+	tx := r.db.WithContext(ctx)
+	var product models.Product
+	err := tx.Where("slug = ?", slug).First(&product).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		id, aliasErr := resolveSlugAlias(tx, slugResourceProduct, slug)
+		if aliasErr != nil {
+			if errors.Is(aliasErr, gorm.ErrRecordNotFound) {
+				return nil, repositories.ErrProductNotFound
+			}
+			return nil, aliasErr
+		}
+		return r.GetById(ctx, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	products, err := r.toEntities(ctx, []models.Product{product}, false)
+	if err != nil {
+		return nil, err
+	}
+	return products[0], nil
+}
+
+func (r *productRepositoryImpl) SlugExists(ctx context.Context, slug string, exceptID int) (bool, error) {
+	return slugTaken(r.db.WithContext(ctx), &models.Product{}, slugResourceProduct, slug, exceptID)
+}
+
+func (r *productRepositoryImpl) GetBySKU(ctx context.Context, sku string) (*entities.Product, error) {
+	var product models.Product
+	err := r.db.WithContext(ctx).Where("sku = ?", sku).First(&product).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.ErrProductNotFound
+		}
+		return nil, err
+	}
+	products, err := r.toEntities(ctx, []models.Product{product}, false)
+	if err != nil {
+		return nil, err
+	}
+	return products[0], nil
+}
+
+func (r *productRepositoryImpl) ExistsBySKU(ctx context.Context, sku string, exceptID int) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Product{}).
+		Where("sku = ? AND id <> ?", sku, exceptID).Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *productRepositoryImpl) List(ctx context.Context, filter repositories.ProductFilter) ([]*entities.Product, int64, error) {
+	sortColumn := "created_at"
+	if filter.SortBy != "" {
+		column, ok := productSortColumns[filter.SortBy]
+		if !ok {
+			return nil, 0, repositories.ErrInvalidProductSort
+		}
+		sortColumn = column
+	}
+	tx := applyProductFilter(r.db.WithContext(ctx).Model(&models.Product{}), filter).Session(&gorm.Session{})
+	var total int64
+	if err := tx.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var products []models.Product
+	order := clause.OrderByColumn{Column: clause.Column{Table: "products", Name: sortColumn}, Desc: filter.SortDesc}
+	if sortColumn == "price" {
+		order = productPriceOrder(filter.SortDesc)
+	}
+	err := tx.Order(order).
+		Order("products.id ASC").Offset(filter.Offset).Limit(filter.Limit).Find(&products).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	result, err := r.toEntities(ctx, products, true)
+	if err != nil {
+		return nil, 0, err
+	}
+	return result, total, nil
+}
+
+func (r *productRepositoryImpl) ListByIds(ctx context.Context, ids []int) ([]*entities.Product, error) {
+	if len(ids) == 0 {
+		return []*entities.Product{}, nil
+	}
+	var products []models.Product
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&products).Error; err != nil {
+		return nil, err
+	}
+	return r.toEntities(ctx, products, true)
+}
+
+func (r *productRepositoryImpl) ListAfterId(ctx context.Context, afterID, limit int) ([]*entities.Product, error) {
+	var products []models.Product
+	err := r.db.WithContext(ctx).Where("id > ?", afterID).Order("id ASC").Limit(limit).Find(&products).Error
+	if err != nil {
+		return nil, err
+	}
+	return r.toEntities(ctx, products, false)
+}
+
+func (r *productRepositoryImpl) Facets(ctx context.Context, filter repositories.ProductFilter, priceBounds []float64) (*entities.ProductFacets, error) {
+	tx := r.db.WithContext(ctx)
+	facets := &entities.ProductFacets{}
+
+	withoutCategory := filter
+	withoutCategory.CategoryID = nil
+	err := applyProductFilter(tx.Model(&models.Product{}), withoutCategory).
+		Select("categories.id, categories.name, categories.slug, COUNT(*) AS count").
+		Joins("JOIN categories ON categories.id = products.category_id").
+		Group("categories.id, categories.name, categories.slug").
+		Order("count DESC, categories.name ASC").
+		Scan(&facets.Categories).Error
+	if err != nil {
+		return nil, err
+	}
+
+	priceRanges, err := countPriceRanges(tx, filter, priceBounds)
+	if err != nil {
+		return nil, err
+	}
+	facets.PriceRanges = priceRanges
+
+	specs, err := countSpecValues(tx, filter)
+	if err != nil {
+		return nil, err
+	}
+	facets.Specifications = specs
+	return facets, nil
+}
+
+func (r *productRepositoryImpl) Create(ctx context.Context, product *entities.Product) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		productModel := toProductModel(product)
+		productModel.CreatedAt = time.Now()
+		productModel.UpdatedAt = productModel.CreatedAt
+		if err := tx.Create(productModel).Error; err != nil {
+			return err
+		}
+		product.ID = productModel.ID
+		product.CreatedAt = productModel.CreatedAt
+		product.UpdatedAt = productModel.UpdatedAt
+		for i, image := range product.Images {
+			image.ProductID = product.ID
+			image.SortOrder = i + 1
+			imageModel := toProductImageModel(image)
+			imageModel.CreatedAt = product.CreatedAt
+			if err := tx.Create(imageModel).Error; err != nil {
+				return err
+			}
+			image.ID = imageModel.ID
+			image.CreatedAt = imageModel.CreatedAt
+		}
+		if err := saveProductOptions(tx, product.ID, product.Options); err != nil {
+			return err
+		}
+		for i, variant := range product.Variants {
+			variant.ProductID = product.ID
+			variant.SortOrder = i + 1
+			if err := createVariant(tx, variant); err != nil {
+				return err
+			}
+		}
+		if len(product.Variants) == 0 {
+			return nil
+		}
+		return syncVariantStock(tx, product.ID)
+	})
+}
+
+func (r *productRepositoryImpl) Update(ctx context.Context, product *entities.Product) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		current, err := lockProduct(tx, product.ID)
+		if err != nil {
+			return err
+		}
+		if err := keepSlugAlias(tx, slugResourceProduct, current.Slug, product.Slug, product.ID); err != nil {
+			return err
+		}
+		var variants int64
+		if err := tx.Model(&models.ProductVariant{}).Where("product_id = ?", product.ID).Count(&variants).Error; err != nil {
+			return err
+		}
+		options, err := productOptionNames(tx, []int{product.ID})
+		if err != nil {
+			return err
+		}
+		if !slices.Equal(options[product.ID], product.Options) {
+			if variants > 0 && !entities.SameOptionNames(options[product.ID], product.Options) {
+				return repositories.ErrProductHasVariants
+			}
+			if err := saveProductOptions(tx, product.ID, product.Options); err != nil {
+				return err
+			}
+		}
+		columns := []string{"name", "slug", "description", "price", "category_id", "sku",
+			"specifications", "is_active", "weight", "dimensions", "updated_at"}
+		// The stock of a product with variants follows its variants.
+		if variants == 0 {
+			columns = append(columns, "stock_quantity")
+		}
+		productModel := toProductModel(product)
+		productModel.UpdatedAt = time.Now()
+		res := tx.Model(&models.Product{}).Where("id = ?", product.ID).
+			Select(columns).
+			Updates(productModel)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return repositories.ErrProductNotFound
+		}
+		product.UpdatedAt = productModel.UpdatedAt
+		return nil
+	})
+}
+
+func (r *productRepositoryImpl) Delete(ctx context.Context, id int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := lockProduct(tx, id); err != nil {
+			return err
+		}
+		// Order items keep referencing the product; orders are created by a
+		// later migration.
+		if tx.Migrator().HasTable(&models.OrderItem{}) {
+			var ordered int64
+			if err := tx.Model(&models.OrderItem{}).Where("product_id = ?", id).Count(&ordered).Error; err != nil {
+				return err
+			}
+			if ordered > 0 {
+				return repositories.ErrProductHasOrders
+			}
+		}
+		if err := tx.Where("resource = ? AND target_id = ?", slugResourceProduct, id).Delete(&models.SlugAlias{}).Error; err != nil {
+			return err
+		}
+		// Images, options, variants, cart items and reviews are removed by ON
+		// DELETE CASCADE.
+		return tx.Delete(&models.Product{}, id).Error
+	})
+}
+
+// lockProduct locks the product row for the rest of the transaction. Image
+// changes lock it too, which keeps the single primary image consistent.
+func lockProduct(tx *gorm.DB, id int) (*models.Product, error) {
+	var product models.Product
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "slug").
+		Where("id = ?", id).First(&product).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.ErrProductNotFound
+		}
+		return nil, err
+	}
+	return &product, nil
+}
+
+// toEntities maps the products and fills in their category, images, price
+// range, rating and review count. With primaryOnly only the primary image is
+// loaded, otherwise the options and variants are loaded too.
+func (r *productRepositoryImpl) toEntities(ctx context.Context, products []models.Product, primaryOnly bool) ([]*entities.Product, error) {
+	result := make([]*entities.Product, 0, len(products))
+	if len(products) == 0 {
+		return result, nil
+	}
+	tx := r.db.WithContext(ctx)
+	ids := make([]int, 0, len(products))
+	categoryIDs := make([]int, 0, len(products))
+	for i := range products {
+		ids = append(ids, products[i].ID)
+		categoryIDs = append(categoryIDs, products[i].CategoryID)
+	}
+
+	var categories []models.Category
+	if err := tx.Where("id IN ?", categoryIDs).Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	categoryByID := make(map[int]*entities.Category, len(categories))
+	for i := range categories {
+		categoryByID[categories[i].ID] = toCategoryEntity(&categories[i])
+	}
+
+	imageQuery := tx.Where("product_id IN ?", ids)
+	if primaryOnly {
+		imageQuery = imageQuery.Where("is_primary = ?", true)
+	}
+	var imageModels []models.ProductImage
+	if err := imageQuery.Order("sort_order ASC, id ASC").Find(&imageModels).Error; err != nil {
+		return nil, err
+	}
+	images := toProductImageEntities(imageModels)
+	imagesByProduct := make(map[int][]*entities.ProductImage, len(products))
+	for _, image := range images {
+		imagesByProduct[image.ProductID] = append(imagesByProduct[image.ProductID], image)
+	}
+
+	type priceRange struct {
+		ProductID int
+		MinPrice  float64
+		MaxPrice  float64
+	}
+	var ranges []priceRange
+	err := tx.Model(&models.ProductVariant{}).
+		Select("product_variants.product_id, "+
+			"MIN(COALESCE(product_variants.price, products.price)) AS min_price, "+
+			"MAX(COALESCE(product_variants.price, products.price)) AS max_price").
+		Joins("JOIN products ON products.id = product_variants.product_id").
+		Where("product_variants.product_id IN ? AND product_variants.is_active = ?", ids, true).
+		Group("product_variants.product_id").
+		Scan(&ranges).Error
+	if err != nil {
+		return nil, err
+	}
+	rangeByProduct := make(map[int]priceRange, len(ranges))
+	for _, r := range ranges {
+		rangeByProduct[r.ProductID] = r
+	}
+
+	var optionsByProduct map[int][]string
+	var variantsByID map[int][]*entities.ProductVariant
+	if !primaryOnly {
+		if optionsByProduct, err = productOptionNames(tx, ids); err != nil {
+			return nil, err
+		}
+		if variantsByID, err = variantsByProduct(tx, ids, images); err != nil {
+			return nil, err
+		}
+	}
+
+	type reviewStats struct {
+		ProductID   int
+		Rating      float64
+		ReviewCount int64
+	}
+	statsByProduct := make(map[int]reviewStats, len(products))
+	// Reviews are created by a later migration.
+	if tx.Migrator().HasTable(&models.ProductReview{}) {
+		var stats []reviewStats
+		err := tx.Model(&models.ProductReview{}).
+			Select("product_id, ROUND(AVG(rating), 1) AS rating, COUNT(*) AS review_count").
+			Where("product_id IN ? AND is_published = ?", ids, true).
+			Group("product_id").
+			Scan(&stats).Error
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range stats {
+			statsByProduct[s.ProductID] = s
+		}
+	}
+
+	for i := range products {
+		product := toProductEntity(&products[i])
+		product.Category = categoryByID[product.CategoryID]
+		product.Images = imagesByProduct[product.ID]
+		product.Options = optionsByProduct[product.ID]
+		product.Variants = variantsByID[product.ID]
+		product.MinPrice, product.MaxPrice = product.Price, product.Price
+		if r, ok := rangeByProduct[product.ID]; ok {
+			product.MinPrice, product.MaxPrice = r.MinPrice, r.MaxPrice
+		}
+		product.Rating = statsByProduct[product.ID].Rating
+		product.ReviewCount = statsByProduct[product.ID].ReviewCount
+		result = append(result, product)
+	}
+	return result, nil
+}
+
+// productOptionNames returns the option names of the products in order.
+func productOptionNames(tx *gorm.DB, productIDs []int) (map[int][]string, error) {
+	var options []models.ProductOption
+	err := tx.Where("product_id IN ?", productIDs).Order("position ASC, id ASC").Find(&options).Error
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[int][]string, len(productIDs))
+	for _, option := range options {
+		result[option.ProductID] = append(result[option.ProductID], option.Name)
+	}
+	return result, nil
+}
+
+// saveProductOptions replaces the option names of the product.
+func saveProductOptions(tx *gorm.DB, productID int, names []string) error {
+	if err := tx.Where("product_id = ?", productID).Delete(&models.ProductOption{}).Error; err != nil {
+		return err
+	}
+	for i, name := range names {
+		option := &models.ProductOption{ProductID: productID, Name: name, Position: i + 1}
+		if err := tx.Create(option).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func toProductModel(product *entities.Product) *models.Product {
+	var sku *string
+	if product.SKU != "" {
+		sku = &product.SKU
+	}
+	return &models.Product{
+		ID:             product.ID,
+		Name:           product.Name,
+		Slug:           product.Slug,
+		Description:    product.Description,
+		Price:          product.Price,
+		StockQuantity:  product.StockQuantity,
+		CategoryID:     product.CategoryID,
+		SKU:            sku,
+		Specifications: product.Specifications,
+		IsActive:       product.IsActive,
+		Weight:         product.Weight,
+		Dimensions:     product.Dimensions,
+		CreatedAt:      product.CreatedAt,
+		UpdatedAt:      product.UpdatedAt,
+	}
+}
+
+func toProductEntity(product *models.Product) *entities.Product {
+	var sku string
+	if product.SKU != nil {
+		sku = *product.SKU
+	}
+	return &entities.Product{
+		ID:             product.ID,
+		Name:           product.Name,
+		Slug:           product.Slug,
+		Description:    product.Description,
+		Price:          product.Price,
+		StockQuantity:  product.StockQuantity,
+		CategoryID:     product.CategoryID,
+		SKU:            sku,
+		Specifications: product.Specifications,
+		IsActive:       product.IsActive,
+		Weight:         product.Weight,
+		Dimensions:     product.Dimensions,
+		CreatedAt:      product.CreatedAt,
+		UpdatedAt:      product.UpdatedAt,
+	}
+}
@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"context"
+	"mini-ecommerce/internal/domain/repositories"
+	"strings"
+	"testing"
+)
+
+func TestProductRepositoryListUsesVariantPrices(t *testing.T) {
+	db, fake := newFakeGormDB(t)
+	minPrice, maxPrice := 10.0, 20.0
+	filter := repositories.ProductFilter{MinPrice: &minPrice, MaxPrice: &maxPrice, SortBy: "price", SortDesc: true, Limit: 12}
+	if _, _, err := NewProductRepositoryImpl(db).List(context.Background(), filter); err != nil {
+		t.Fatal(err)
+	}
+	queries := fake.find(`SELECT * FROM "products"`)
+	if len(queries) != 1 {
+		t.Fatalf("got %d product queries, want 1", len(queries))
+	}
+	query := queries[0].query
+	for _, want := range []string{
+		"COALESCE(product_variants.price, products.price)",
+		"prices.price >= $1 AND prices.price <= $2",
+		"ORDER BY (SELECT MAX(prices.price)",
+	} {
+		if !strings.Contains(query, want) {
+			t.Errorf("query %q does not contain %q", query, want)
+		}
+	}
+	if args := queries[0].args; len(args) < 2 || args[0] != minPrice || args[1] != maxPrice {
+		t.Errorf("got args %v, want the price limits first", args)
+	}
+}
+
+func TestProductRepositoryFacetsCountVariantPrices(t *testing.T) {
+	db, fake := newFakeGormDB(t)
+	if _, err := NewProductRepositoryImpl(db).Facets(context.Background(), repositories.ProductFilter{}, []float64{50, 100}); err != nil {
+		t.Fatal(err)
+	}
+	queries := fake.find(`SELECT CASE WHEN prices.price < $1`)
+	if len(queries) != 1 {
+		t.Fatalf("got %d price range queries, want 1", len(queries))
+	}
+	for _, want := range []string{"LATERAL (SELECT COALESCE(product_variants.price, products.price)", "COUNT(DISTINCT products.id)"} {
+		if !strings.Contains(queries[0].query, want) {
+			t.Errorf("query %q does not contain %q", queries[0].query, want)
+		}
+	}
+}
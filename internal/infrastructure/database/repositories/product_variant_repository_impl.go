@@ -0,0 +1,273 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"mini-ecommerce/internal/domain/entities"
+	"mini-ecommerce/internal/domain/repositories"
+	"mini-ecommerce/internal/infrastructure/database/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type productVariantRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewProductVariantRepositoryImpl(db *gorm.DB) repositories.ProductVariantRepository {
+	return &productVariantRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *productVariantRepositoryImpl) ListByProductId(ctx context.Context, productID int) ([]*entities.ProductVariant, error) {
+	tx := r.db.WithContext(ctx)
+	var images []models.ProductImage
+	err := tx.Where("product_id = ? AND variant_id IS NOT NULL", productID).
+		Order("sort_order ASC, id ASC").Find(&images).Error
+	if err != nil {
+		return nil, err
+	}
+	variants, err := variantsByProduct(tx, []int{productID}, toProductImageEntities(images))
+	if err != nil {
+		return nil, err
+	}
+	if variants[productID] == nil {
+		return []*entities.ProductVariant{}, nil
+	}
+	return variants[productID], nil
+}
+
+func (r *productVariantRepositoryImpl) GetById(ctx context.Context, productID, id int) (*entities.ProductVariant, error) {
+	tx := r.db.WithContext(ctx)
+	var variant models.ProductVariant
+	err := tx.Where("id = ? AND product_id = ?", id, productID).First(&variant).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.ErrProductVariantNotFound
+		}
+		return nil, err
+	}
+	var images []models.ProductImage
+	if err := tx.Where("variant_id = ?", id).Order("sort_order ASC, id ASC").Find(&images).Error; err != nil {
+		return nil, err
+	}
+	result := toProductVariantEntity(&variant)
+	result.Images = toProductImageEntities(images)
+	return result, nil
+}
+
+func (r *productVariantRepositoryImpl) ExistsBySKU(ctx context.Context, sku string, exceptID int) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.ProductVariant{}).
+		Where("sku = ? AND id <> ?", sku, exceptID).Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *productVariantRepositoryImpl) Create(ctx context.Context, variant *entities.ProductVariant) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := lockProduct(tx, variant.ProductID); err != nil {
+			return err
+		}
+		if err := ensureUniqueVariantOptions(tx, variant); err != nil {
+			return err
+		}
+		var maxSortOrder int
+		err := tx.Model(&models.ProductVariant{}).Select("COALESCE(MAX(sort_order), 0)").
+			Where("product_id = ?", variant.ProductID).Scan(&maxSortOrder).Error
+		if err != nil {
+			return err
+		}
+		variant.SortOrder = maxSortOrder + 1
+		if err := createVariant(tx, variant); err != nil {
+			return err
+		}
+		return syncVariantStock(tx, variant.ProductID)
+	})
+}
+
+func (r *productVariantRepositoryImpl) Update(ctx context.Context, variant *entities.ProductVariant) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := lockProduct(tx, variant.ProductID); err != nil {
+			return err
+		}
+		if err := ensureUniqueVariantOptions(tx, variant); err != nil {
+			return err
+		}
+		variantModel := toProductVariantModel(variant)
+		variantModel.UpdatedAt = time.Now()
+		res := tx.Model(&models.ProductVariant{}).
+			Where("id = ? AND product_id = ?", variant.ID, variant.ProductID).
+			Select("sku", "options", "price", "stock_quantity", "weight", "is_active", "updated_at").
+			Updates(variantModel)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return repositories.ErrProductVariantNotFound
+		}
+		variant.UpdatedAt = variantModel.UpdatedAt
+		return syncVariantStock(tx, variant.ProductID)
+	})
+}
+
+func (r *productVariantRepositoryImpl) UpdateStock(ctx context.Context, productID, id, quantity int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := lockProduct(tx, productID); err != nil {
+			return err
+		}
+		res := tx.Model(&models.ProductVariant{}).
+			Where("id = ? AND product_id = ?", id, productID).
+			Updates(map[string]interface{}{"stock_quantity": quantity, "updated_at": time.Now()})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return repositories.ErrProductVariantNotFound
+		}
+		return syncVariantStock(tx, productID)
+	})
+}
+
+func (r *productVariantRepositoryImpl) Delete(ctx context.Context, productID, id int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := lockProduct(tx, productID); err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.Model(&models.ProductVariant{}).Where("id = ? AND product_id = ?", id, productID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return repositories.ErrProductVariantNotFound
+		}
+		// Order items keep referencing the variant; orders are created by a
+		// later migration.
+		if tx.Migrator().HasColumn(&models.OrderItem{}, "variant_id") {
+			var ordered int64
+			if err := tx.Model(&models.OrderItem{}).Where("variant_id = ?", id).Count(&ordered).Error; err != nil {
+				return err
+			}
+			if ordered > 0 {
+				return repositories.ErrVariantHasOrders
+			}
+		}
+		// Its images and cart items are handled by the foreign keys.
+		if err := tx.Delete(&models.ProductVariant{}, id).Error; err != nil {
+			return err
+		}
+		return syncVariantStock(tx, productID)
+	})
+}
+
+// createVariant inserts the variant. The caller holds the product lock.
+func createVariant(tx *gorm.DB, variant *entities.ProductVariant) error {
+	variantModel := toProductVariantModel(variant)
+	variantModel.CreatedAt = time.Now()
+	variantModel.UpdatedAt = variantModel.CreatedAt
+	if err := tx.Create(variantModel).Error; err != nil {
+		return err
+	}
+	variant.ID = variantModel.ID
+	variant.CreatedAt = variantModel.CreatedAt
+	variant.UpdatedAt = variantModel.UpdatedAt
+	return nil
+}
+
+// ensureUniqueVariantOptions fails when another variant of the product has
+// the same options.
+func ensureUniqueVariantOptions(tx *gorm.DB, variant *entities.ProductVariant) error {
+	options, err := json.Marshal(variant.Options)
+	if err != nil {
+		return err
+	}
+	var count int64
+	err = tx.Model(&models.ProductVariant{}).
+		Where("product_id = ? AND options = ?::jsonb AND id <> ?", variant.ProductID, string(options), variant.ID).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return repositories.ErrDuplicateVariantOptions
+	}
+	return nil
+}
+
+// syncVariantStock sets the stock of a product to the total stock of its
+// active variants. The caller holds the product lock.
+func syncVariantStock(tx *gorm.DB, productID int) error {
+	return tx.Exec(`UPDATE products SET updated_at = NOW(), stock_quantity = (
+		SELECT COALESCE(SUM(stock_quantity), 0) FROM product_variants WHERE product_id = ? AND is_active
+	) WHERE id = ?`, productID, productID).Error
+}
+
+// variantsByProduct loads the variants of the products in display order and
+// attaches the images that belong to a variant.
+func variantsByProduct(tx *gorm.DB, productIDs []int, images []*entities.ProductImage) (map[int][]*entities.ProductVariant, error) {
+	var variants []models.ProductVariant
+	err := tx.Where("product_id IN ?", productIDs).Order("sort_order ASC, id ASC").Find(&variants).Error
+	if err != nil {
+		return nil, err
+	}
+	imagesByVariant := make(map[int][]*entities.ProductImage)
+	for _, image := range images {
+		if image.VariantID != nil {
+			imagesByVariant[*image.VariantID] = append(imagesByVariant[*image.VariantID], image)
+		}
+	}
+	result := make(map[int][]*entities.ProductVariant, len(productIDs))
+	for i := range variants {
+		variant := toProductVariantEntity(&variants[i])
+		variant.Images = imagesByVariant[variant.ID]
+		result[variant.ProductID] = append(result[variant.ProductID], variant)
+	}
+	return result, nil
+}
+
+func toProductVariantModel(variant *entities.ProductVariant) *models.ProductVariant {
+	options := make(models.JSONB, len(variant.Options))
+	for name, value := range variant.Options {
+		options[name] = value
+	}
+	return &models.ProductVariant{
+		ID:            variant.ID,
+		ProductID:     variant.ProductID,
+		SKU:           variant.SKU,
+		Options:       options,
+		Price:         variant.Price,
+		StockQuantity: variant.StockQuantity,
+		Weight:        variant.Weight,
+		IsActive:      variant.IsActive,
+		SortOrder:     variant.SortOrder,
+		CreatedAt:     variant.CreatedAt,
+		UpdatedAt:     variant.UpdatedAt,
+	}
+}
+
+func toProductVariantEntity(variant *models.ProductVariant) *entities.ProductVariant {
+	options := make(map[string]string, len(variant.Options))
+	for name, value := range variant.Options {
+		if s, ok := value.(string); ok {
+			options[name] = s
+		}
+	}
+	return &entities.ProductVariant{
+		ID:            variant.ID,
+		ProductID:     variant.ProductID,
+		SKU:           variant.SKU,
+		Options:       options,
+		Price:         variant.Price,
+		StockQuantity: variant.StockQuantity,
+		Weight:        variant.Weight,
+		IsActive:      variant.IsActive,
+		SortOrder:     variant.SortOrder,
+		CreatedAt:     variant.CreatedAt,
+		UpdatedAt:     variant.UpdatedAt,
+	}
+}
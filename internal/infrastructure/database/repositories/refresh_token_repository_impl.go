@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"mini-ecommerce/internal/domain/entities"
+	"mini-ecommerce/internal/domain/repositories"
+	"mini-ecommerce/internal/infrastructure/database/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type refreshTokenRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewRefreshTokenRepositoryImpl(db *gorm.DB) repositories.RefreshTokenRepository {
+	return &refreshTokenRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *refreshTokenRepositoryImpl) Create(ctx context.Context, token *entities.RefreshToken) error {
+	tokenModel := toRefreshTokenModel(token)
+	if err := r.db.WithContext(ctx).Create(tokenModel).Error; err != nil {
+		return err
+	}
+	token.ID = tokenModel.ID
+	token.CreatedAt = tokenModel.CreatedAt
+	return nil
+}
+
+func (r *refreshTokenRepositoryImpl) GetByHash(ctx context.Context, tokenHash string) (*entities.RefreshToken, error) {
+	var token models.RefreshToken
+	err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.ErrRefreshTokenNotFound
+		}
+		return nil, err
+	}
+	return toRefreshTokenEntity(&token), nil
+}
+
+func (r *refreshTokenRepositoryImpl) Rotate(ctx context.Context, oldID int, newToken *entities.RefreshToken) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		tokenModel := toRefreshTokenModel(newToken)
+		if err := tx.Create(tokenModel).Error; err != nil {
+			return err
+		}
+		// Only a still-active token may be rotated; a concurrent rotation loses here.
+		res := tx.Model(&models.RefreshToken{}).
+			Where("id = ? AND revoked_at IS NULL", oldID).
+			Updates(map[string]interface{}{
+				"revoked_at":     time.Now(),
+				"replaced_by_id": tokenModel.ID,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return repositories.ErrRefreshTokenRevoked
+		}
+		newToken.ID = tokenModel.ID
+		newToken.CreatedAt = tokenModel.CreatedAt
+		return nil
+	})
+}
+
+func (r *refreshTokenRepositoryImpl) RevokeFamily(ctx context.Context, familyID string) error {
+	return r.db.WithContext(ctx).Model(&models.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+}
+
+func (r *refreshTokenRepositoryImpl) RevokeAllByUserId(ctx context.Context, userID int) error {
+	return r.db.WithContext(ctx).Model(&models.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
+
+func toRefreshTokenModel(token *entities.RefreshToken) *models.RefreshToken {
+	return &models.RefreshToken{
+		ID:           token.ID,
+		UserID:       token.UserID,
+		FamilyID:     token.FamilyID,
+		TokenHash:    token.TokenHash,
+		DeviceID:     token.DeviceID,
+		UserAgent:    token.UserAgent,
+		IPAddress:    token.IPAddress,
+		ExpiresAt:    token.ExpiresAt,
+		RevokedAt:    token.RevokedAt,
+		ReplacedByID: token.ReplacedByID,
+		CreatedAt:    time.Now(),
+	}
+}
+
+func toRefreshTokenEntity(token *models.RefreshToken) *entities.RefreshToken {
+	return &entities.RefreshToken{
+		ID:           token.ID,
+		UserID:       token.UserID,
+		FamilyID:     token.FamilyID,
+		TokenHash:    token.TokenHash,
+		DeviceID:     token.DeviceID,
+		UserAgent:    token.UserAgent,
+		IPAddress:    token.IPAddress,
+		ExpiresAt:    token.ExpiresAt,
+		RevokedAt:    token.RevokedAt,
+		ReplacedByID: token.ReplacedByID,
+		CreatedAt:    token.CreatedAt,
+	}
+}
@@ -0,0 +1,145 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"mini-ecommerce/internal/domain/entities"
+	"mini-ecommerce/internal/domain/repositories"
+	"mini-ecommerce/internal/infrastructure/database/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type sessionRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewSessionRepositoryImpl(db *gorm.DB) repositories.SessionRepository {
+	return &sessionRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *sessionRepositoryImpl) Create(ctx context.Context, session *entities.Session) error {
+	sessionModel := toSessionModel(session)
+	if err := r.db.WithContext(ctx).Create(sessionModel).Error; err != nil {
+		return err
+	}
+	session.CreatedAt = sessionModel.CreatedAt
+	return nil
+}
+
+func (r *sessionRepositoryImpl) GetById(ctx context.Context, id string) (*entities.Session, error) {
+	var session models.Session
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&session).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.ErrSessionNotFound
+		}
+		return nil, err
+	}
+	return toSessionEntity(&session), nil
+}
+
+func (r *sessionRepositoryImpl) ListActiveByUserId(ctx context.Context, userID int) ([]*entities.Session, error) {
+	var sessions []models.Session
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_activity_at DESC").
+		Find(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*entities.Session, 0, len(sessions))
+	for i := range sessions {
+		res = append(res, toSessionEntity(&sessions[i]))
+	}
+	return res, nil
+}
+
+func (r *sessionRepositoryImpl) Extend(ctx context.Context, id, ipAddress, userAgent string, expiresAt time.Time) error {
+	res := r.db.WithContext(ctx).Model(&models.Session{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"ip_address":       ipAddress,
+			"user_agent":       userAgent,
+			"last_activity_at": time.Now(),
+			"expires_at":       expiresAt,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return repositories.ErrSessionNotFound
+	}
+	return nil
+}
+
+func (r *sessionRepositoryImpl) TouchLastActivity(ctx context.Context, id string, at time.Time, threshold time.Duration) error {
+	return r.db.WithContext(ctx).Model(&models.Session{}).
+		Where("id = ? AND last_activity_at < ?", id, at.Add(-threshold)).
+		Update("last_activity_at", at).Error
+}
+
+func (r *sessionRepositoryImpl) Revoke(ctx context.Context, userID int, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		res := tx.Model(&models.Session{}).
+			Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+			Update("revoked_at", now)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return repositories.ErrSessionNotFound
+		}
+		return tx.Model(&models.RefreshToken{}).
+			Where("family_id = ? AND revoked_at IS NULL", id).
+			Update("revoked_at", now).Error
+	})
+}
+
+func (r *sessionRepositoryImpl) RevokeAllByUserId(ctx context.Context, userID int, exceptID string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		sessions := tx.Model(&models.Session{}).Where("user_id = ? AND revoked_at IS NULL", userID)
+		tokens := tx.Model(&models.RefreshToken{}).Where("user_id = ? AND revoked_at IS NULL", userID)
+		if exceptID != "" {
+			sessions = sessions.Where("id <> ?", exceptID)
+			tokens = tokens.Where("family_id <> ?", exceptID)
+		}
+		if err := sessions.Update("revoked_at", now).Error; err != nil {
+			return err
+		}
+		return tokens.Update("revoked_at", now).Error
+	})
+}
+
+func toSessionModel(session *entities.Session) *models.Session {
+	return &models.Session{
+		ID:             session.ID,
+		UserID:         session.UserID,
+		DeviceID:       session.DeviceID,
+		UserAgent:      session.UserAgent,
+		IPAddress:      session.IPAddress,
+		LastActivityAt: session.LastActivityAt,
+		ExpiresAt:      session.ExpiresAt,
+		RevokedAt:      session.RevokedAt,
+		CreatedAt:      time.Now(),
+	}
+}
+
+func toSessionEntity(session *models.Session) *entities.Session {
+	return &entities.Session{
+		ID:             session.ID,
+		UserID:         session.UserID,
+		DeviceID:       session.DeviceID,
+		UserAgent:      session.UserAgent,
+		IPAddress:      session.IPAddress,
+		LastActivityAt: session.LastActivityAt,
+		ExpiresAt:      session.ExpiresAt,
+		RevokedAt:      session.RevokedAt,
+		CreatedAt:      session.CreatedAt,
+	}
+}
@@ -0,0 +1,68 @@
+package repositories
+
+import (
+	"errors"
+	"mini-ecommerce/internal/infrastructure/database/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	slugResourceCategory = "category"
+	slugResourceProduct  = "product"
+)
+
+// resolveSlugAlias returns the id a previous slug points to, or
+// gorm.ErrRecordNotFound.
+func resolveSlugAlias(tx *gorm.DB, resource, slug string) (int, error) {
+	var alias models.SlugAlias
+	err := tx.Where("resource = ? AND slug = ?", resource, slug).First(&alias).Error
+	if err != nil {
+		return 0, err
+	}
+	return alias.TargetID, nil
+}
+
+// slugTaken reports whether the slug is the current slug of another row of
+// table or an alias pointing to another row. A row may take back its own
+// previous slugs.
+func slugTaken(tx *gorm.DB, table interface{}, resource, slug string, exceptID int) (bool, error) {
+	var count int64
+	if err := tx.Model(table).Where("slug = ? AND id <> ?", slug, exceptID).Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return true, nil
+	}
+	targetID, err := resolveSlugAlias(tx, resource, slug)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return targetID != exceptID, nil
+}
+
+// keepSlugAlias records oldSlug as an alias of id after a slug change and
+// drops the alias of the new slug, which is now the current one again.
+func keepSlugAlias(tx *gorm.DB, resource, oldSlug, newSlug string, id int) error {
+	if oldSlug == newSlug || oldSlug == "" {
+		return nil
+	}
+	if err := tx.Where("resource = ? AND slug = ? AND target_id = ?", resource, newSlug, id).
+		Delete(&models.SlugAlias{}).Error; err != nil {
+		return err
+	}
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "resource"}, {Name: "slug"}},
+		DoUpdates: clause.AssignmentColumns([]string{"target_id"}),
+	}).Create(&models.SlugAlias{
+		Resource:  resource,
+		Slug:      oldSlug,
+		TargetID:  id,
+		CreatedAt: time.Now(),
+	}).Error
+}
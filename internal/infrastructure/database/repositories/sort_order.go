@@ -0,0 +1,54 @@
+package repositories
+
+import (
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// sameIDSet reports whether ids lists every id of current exactly once.
+func sameIDSet(current, ids []int) bool {
+	if len(current) != len(ids) {
+		return false
+	}
+	remaining := make(map[int]bool, len(current))
+	for _, id := range current {
+		remaining[id] = true
+	}
+	for _, id := range ids {
+		if !remaining[id] {
+			return false
+		}
+		delete(remaining, id)
+	}
+	return true
+}
+
+// applySortOrder sets sort_order of the rows of table to their 1-based
+// position in ids in a single statement. set adds further assignments such
+// as "updated_at = NOW()".
+func applySortOrder(tx *gorm.DB, table string, ids []int, set string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	assignments := "sort_order = v.position"
+	if set != "" {
+		assignments += ", " + set
+	}
+	return tx.Exec(`
+		UPDATE `+table+` AS t SET `+assignments+`
+		FROM unnest(?::int[]) WITH ORDINALITY AS v(id, position)
+		WHERE t.id = v.id`, intArrayLiteral(ids)).Error
+}
+
+// intArrayLiteral renders ids as a Postgres array literal such as "{3,1,2}".
+// gorm expands slice arguments into a parenthesized list, which cannot be
+// unnested with its order intact.
+func intArrayLiteral(ids []int) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.Itoa(id)
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSameIDSet(t *testing.T) {
+	tests := []struct {
+		name    string
+		current []int
+		ids     []int
+		want    bool
+	}{
+		{name: "same order", current: []int{1, 2, 3}, ids: []int{1, 2, 3}, want: true},
+		{name: "reordered", current: []int{1, 2, 3}, ids: []int{3, 1, 2}, want: true},
+		{name: "both empty", want: true},
+		{name: "missing id", current: []int{1, 2, 3}, ids: []int{1, 2}},
+		{name: "extra id", current: []int{1, 2}, ids: []int{1, 2, 3}},
+		{name: "foreign id", current: []int{1, 2, 3}, ids: []int{1, 2, 4}},
+		{name: "duplicate in place of another", current: []int{1, 2, 3}, ids: []int{1, 1, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sameIDSet(tt.current, tt.ids); got != tt.want {
+				t.Errorf("sameIDSet(%v, %v) = %v, want %v", tt.current, tt.ids, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIntArrayLiteral(t *testing.T) {
+	if got := intArrayLiteral([]int{3, 1, 2}); got != "{3,1,2}" {
+		t.Errorf("intArrayLiteral = %q", got)
+	}
+	if got := intArrayLiteral(nil); got != "{}" {
+		t.Errorf("intArrayLiteral(nil) = %q", got)
+	}
+}
+
+func TestApplySortOrder(t *testing.T) {
+	db, fake := newFakeGormDB(t)
+	if err := applySortOrder(db, "categories", []int{3, 1, 2}, "updated_at = NOW()"); err != nil {
+		t.Fatal(err)
+	}
+	updates := fake.find("UPDATE categories")
+	if len(updates) != 1 {
+		t.Fatalf("got %d updates, want 1", len(updates))
+	}
+	if !strings.Contains(updates[0].query, "SET sort_order = v.position, updated_at = NOW()") || updates[0].args[0] != "{3,1,2}" {
+		t.Errorf("update %q with %v", updates[0].query, updates[0].args)
+	}
+
+	if err := applySortOrder(db, "categories", nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(fake.find("UPDATE categories")); got != 1 {
+		t.Errorf("an empty reorder sent %d more updates", got-1)
+	}
+}
@@ -0,0 +1,154 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"mini-ecommerce/internal/domain/entities"
+	"mini-ecommerce/internal/domain/repositories"
+	"mini-ecommerce/internal/infrastructure/database/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type twoFactorRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewTwoFactorRepositoryImpl(db *gorm.DB) repositories.TwoFactorRepository {
+	return &twoFactorRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *twoFactorRepositoryImpl) GetByUserId(ctx context.Context, userID int) (*entities.UserTwoFactor, error) {
+	var twoFactor models.UserTwoFactor
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&twoFactor).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.ErrTwoFactorNotFound
+		}
+		return nil, err
+	}
+	return &entities.UserTwoFactor{
+		UserID:          twoFactor.UserID,
+		SecretEncrypted: twoFactor.SecretEncrypted,
+		ConfirmedAt:     twoFactor.ConfirmedAt,
+		LastUsedStep:    twoFactor.LastUsedStep,
+	}, nil
+}
+
+func (r *twoFactorRepositoryImpl) SavePending(ctx context.Context, twoFactor *entities.UserTwoFactor) error {
+	now := time.Now()
+	twoFactorModel := &models.UserTwoFactor{
+		UserID:          twoFactor.UserID,
+		SecretEncrypted: twoFactor.SecretEncrypted,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"secret_encrypted": twoFactor.SecretEncrypted,
+			"confirmed_at":     nil,
+			"last_used_step":   0,
+			"updated_at":       now,
+		}),
+	}).Create(twoFactorModel).Error
+}
+
+func (r *twoFactorRepositoryImpl) Enable(ctx context.Context, userID int, step int64, recoveryCodeHashes []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		res := tx.Model(&models.UserTwoFactor{}).
+			Where("user_id = ? AND confirmed_at IS NULL", userID).
+			Updates(map[string]interface{}{
+				"confirmed_at":   now,
+				"last_used_step": step,
+				"updated_at":     now,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return repositories.ErrTwoFactorNotFound
+		}
+		if err := tx.Model(&models.User{}).Where("id = ?", userID).
+			Updates(map[string]interface{}{"two_factor_enabled": true, "updated_at": now}).Error; err != nil {
+			return err
+		}
+		return replaceRecoveryCodes(tx, userID, recoveryCodeHashes)
+	})
+}
+
+func (r *twoFactorRepositoryImpl) Disable(ctx context.Context, userID int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.TwoFactorRecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&models.UserTwoFactor{}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.User{}).Where("id = ?", userID).
+			Updates(map[string]interface{}{"two_factor_enabled": false, "updated_at": time.Now()}).Error
+	})
+}
+
+func (r *twoFactorRepositoryImpl) ReplaceRecoveryCodes(ctx context.Context, userID int, recoveryCodeHashes []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return replaceRecoveryCodes(tx, userID, recoveryCodeHashes)
+	})
+}
+
+func (r *twoFactorRepositoryImpl) UseStep(ctx context.Context, userID int, step int64) error {
+	res := r.db.WithContext(ctx).Model(&models.UserTwoFactor{}).
+		Where("user_id = ? AND last_used_step < ?", userID, step).
+		Update("last_used_step", step)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return repositories.ErrTOTPStepReplayed
+	}
+	return nil
+}
+
+func (r *twoFactorRepositoryImpl) UseRecoveryCode(ctx context.Context, userID int, codeHash string) error {
+	res := r.db.WithContext(ctx).Model(&models.TwoFactorRecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return repositories.ErrRecoveryCodeNotFound
+	}
+	return nil
+}
+
+func (r *twoFactorRepositoryImpl) CountRecoveryCodes(ctx context.Context, userID int) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.TwoFactorRecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+func replaceRecoveryCodes(tx *gorm.DB, userID int, recoveryCodeHashes []string) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&models.TwoFactorRecoveryCode{}).Error; err != nil {
+		return err
+	}
+	if len(recoveryCodeHashes) == 0 {
+		return nil
+	}
+	codes := make([]models.TwoFactorRecoveryCode, 0, len(recoveryCodeHashes))
+	for _, hash := range recoveryCodeHashes {
+		codes = append(codes, models.TwoFactorRecoveryCode{
+			UserID:    userID,
+			CodeHash:  hash,
+			CreatedAt: time.Now(),
+		})
+	}
+	return tx.Create(&codes).Error
+}
@@ -3,9 +3,11 @@ package repositories
 import (
 	"context"
 	"errors"
+	"fmt"
 	"mini-ecommerce/internal/domain/entities"
 	"mini-ecommerce/internal/domain/repositories"
 	"mini-ecommerce/internal/infrastructure/database/models"
+	"mini-ecommerce/pkg/utils"
 	"time"
 
 	"gorm.io/gorm"
@@ -48,51 +50,223 @@ func (r *userRepositoryImpl) GetByEmail(ctx context.Context, email string) (*ent
 
 func (r *userRepositoryImpl) Create(ctx context.Context, user *entities.User) error {
 	userModel := &models.User{
-		Email:         user.Email,
-		Name:          user.Name,
-		Password:      user.Password,
-		Role:          user.Role,
-		Phone:         user.Phone,
-		EmailVerified: user.EmailVerified,
-		IsActive:      user.IsActive,
-		CreatedAt:     time.Now(),
-		UpdatedAt:     time.Now(),
+		Email:             user.Email,
+		Name:              user.Name,
+		Password:          user.Password,
+		Role:              user.Role,
+		Phone:             user.Phone,
+		EmailVerified:     user.EmailVerified,
+		IsActive:          user.IsActive,
+		TwoFactorEnabled:  user.TwoFactorEnabled,
+		PasswordChangedAt: user.PasswordChangedAt,
+		AnonymizedAt:      user.AnonymizedAt,
+		CreatedAt:         time.Now(),
+		UpdatedAt:         time.Now(),
 	}
 	if err := r.db.WithContext(ctx).Create(userModel).Error; err != nil {
 		return err
 	}
 	user.ID = userModel.ID
+	user.CreatedAt = userModel.CreatedAt
+	user.UpdatedAt = userModel.UpdatedAt
 	return nil
 }
 
-func (r *userRepositoryImpl) Update(ctx context.Context, user *entities.User) error {
-	userModel := &models.User{
-		ID:            user.ID,
-		Email:         user.Email,
-		Name:          user.Name,
-		Password:      user.Password,
-		Role:          user.Role,
-		Phone:         user.Phone,
-		EmailVerified: user.EmailVerified,
-		IsActive:      user.IsActive,
-		UpdatedAt:     time.Now(),
+func (r *userRepositoryImpl) UpdateProfile(ctx context.Context, id int, update repositories.UserProfileUpdate) error {
+	now := time.Now()
+	changes := map[string]interface{}{"updated_at": now}
+	if update.Name != nil {
+		changes["name"] = *update.Name
+	}
+	if update.Phone != nil {
+		changes["phone"] = *update.Phone
+	}
+	if update.Email != nil {
+		changes["email"] = *update.Email
+		changes["email_verified"] = false
+	}
+	if update.Password != nil {
+		changes["password"] = *update.Password
+		changes["password_changed_at"] = now
+	}
+	return updateUserColumns(r.db.WithContext(ctx).Where("id = ?", id), changes)
+}
+
+func (r *userRepositoryImpl) UpdatePassword(ctx context.Context, id int, hash string) error {
+	return r.UpdateProfile(ctx, id, repositories.UserProfileUpdate{Password: &hash})
+}
+
+func (r *userRepositoryImpl) UpgradePasswordHash(ctx context.Context, id int, current, upgraded string) error {
+	return r.db.WithContext(ctx).Model(&models.User{}).
+		Where("id = ? AND password = ? AND anonymized_at IS NULL", id, current).
+		Updates(map[string]interface{}{
+			"password":   upgraded,
+			"updated_at": time.Now(),
+		}).Error
+}
+
+func (r *userRepositoryImpl) SetRole(ctx context.Context, id int, role string) error {
+	return updateUserColumns(r.db.WithContext(ctx).Where("id = ?", id), map[string]interface{}{
+		"role":       role,
+		"updated_at": time.Now(),
+	})
+}
+
+func (r *userRepositoryImpl) SetActive(ctx context.Context, id int, active bool) error {
+	return updateUserColumns(r.db.WithContext(ctx).Where("id = ?", id), map[string]interface{}{
+		"is_active":  active,
+		"updated_at": time.Now(),
+	})
+}
+
+func (r *userRepositoryImpl) MarkEmailVerified(ctx context.Context, id int, email string) error {
+	return updateUserColumns(r.db.WithContext(ctx).Where("id = ? AND email = ?", id, email), map[string]interface{}{
+		"email_verified": true,
+		"updated_at":     time.Now(),
+	})
+}
+
+// updateUserColumns writes the changes to the users matched by tx that are not
+// anonymized.
+func updateUserColumns(tx *gorm.DB, changes map[string]interface{}) error {
+	res := tx.Model(&models.User{}).Where("anonymized_at IS NULL").Updates(changes)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errors.New("user not found")
+	}
+	return nil
+}
+
+func (r *userRepositoryImpl) Anonymize(ctx context.Context, id int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		res := tx.Model(&models.User{}).
+			Where("id = ? AND anonymized_at IS NULL", id).
+			Updates(anonymizedUserColumns(id, now))
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return errors.New("user not found")
+		}
+		for _, owned := range erasedUserRows() {
+			if err := tx.Where("user_id = ?", id).Delete(owned).Error; err != nil {
+				return err
+			}
+		}
+		// Carts and orders are created by later migrations.
+		if tx.Migrator().HasTable(&models.Cart{}) {
+			if err := tx.Where("user_id = ?", id).Delete(&models.Cart{}).Error; err != nil {
+				return err
+			}
+		}
+		if tx.Migrator().HasTable(&models.Order{}) {
+			// Orders are kept for accounting. Only the parts of the shipping
+			// snapshot that identify the recipient are removed; the location
+			// needed for tax records stays.
+			shippingAddress := "shipping_address"
+			for _, field := range identifyingShippingFields {
+				shippingAddress += " - '" + field + "'"
+			}
+			err := tx.Model(&models.Order{}).Where("user_id = ?", id).
+				Updates(map[string]interface{}{
+					"shipping_address": gorm.Expr(shippingAddress),
+					"notes":            "",
+					"updated_at":       now,
+				}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// anonymizedUserColumns replaces everything that identifies the user. The
+// row itself stays so orders and payments keep their owner.
+func anonymizedUserColumns(id int, now time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"email":               fmt.Sprintf("deleted-user-%d@anonymized.invalid", id),
+		"name":                "Deleted User",
+		"phone":               "",
+		"password":            "",
+		"email_verified":      false,
+		"is_active":           false,
+		"two_factor_enabled":  false,
+		"password_changed_at": now,
+		"anonymized_at":       now,
+		"updated_at":          now,
+	}
+}
+
+// erasedUserRows are the models whose rows of the user are deleted on
+// erasure. Orders and payments are not among them.
+func erasedUserRows() []interface{} {
+	return []interface{}{
+		&models.UserAddress{},
+		&models.TwoFactorRecoveryCode{},
+		&models.UserTwoFactor{},
+		&models.APIKey{},
+		&models.PasswordResetToken{},
+		&models.RefreshToken{},
+		&models.Session{},
 	}
-	return r.db.WithContext(ctx).Save(userModel).Error
 }
 
-func (r *userRepositoryImpl) Delete(ctx context.Context, id int) error {
-	return r.db.WithContext(ctx).Delete(models.User{}, id).Error
+// identifyingShippingFields are the keys of an order's shipping snapshot
+// that name or reach the recipient.
+var identifyingShippingFields = []string{"recipient_name", "phone", "address_line_1", "address_line_2", "label"}
+
+func (r *userRepositoryImpl) List(ctx context.Context, filter repositories.UserFilter) ([]*entities.User, int64, error) {
+	return r.find(r.db.WithContext(ctx).Model(&models.User{}), filter)
+}
+
+func (r *userRepositoryImpl) Search(ctx context.Context, query string, filter repositories.UserFilter) ([]*entities.User, int64, error) {
+	pattern := "%" + utils.EscapeLike(query) + "%"
+	tx := r.db.WithContext(ctx).Model(&models.User{}).
+		Where("name ILIKE ? OR email ILIKE ? OR phone ILIKE ?", pattern, pattern, pattern)
+	return r.find(tx, filter)
+}
+
+func (r *userRepositoryImpl) find(tx *gorm.DB, filter repositories.UserFilter) ([]*entities.User, int64, error) {
+	if filter.Role != "" {
+		tx = tx.Where("role = ?", filter.Role)
+	}
+	if filter.IsActive != nil {
+		tx = tx.Where("is_active = ?", *filter.IsActive)
+	}
+	tx = tx.Session(&gorm.Session{})
+	var total int64
+	if err := tx.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var users []models.User
+	if err := tx.Order("id ASC").Offset(filter.Offset).Limit(filter.Limit).Find(&users).Error; err != nil {
+		return nil, 0, err
+	}
+	result := make([]*entities.User, 0, len(users))
+	for i := range users {
+		result = append(result, toEntity(&users[i]))
+	}
+	return result, total, nil
 }
 
 func toEntity(user *models.User) *entities.User {
 	return &entities.User{
-		ID:            user.ID,
-		Email:         user.Email,
-		Name:          user.Name,
-		Password:      user.Password,
-		Role:          user.Role,
-		Phone:         user.Phone,
-		EmailVerified: user.EmailVerified,
-		IsActive:      user.IsActive,
+		ID:                user.ID,
+		Email:             user.Email,
+		Name:              user.Name,
+		Password:          user.Password,
+		Role:              user.Role,
+		Phone:             user.Phone,
+		EmailVerified:     user.EmailVerified,
+		IsActive:          user.IsActive,
+		TwoFactorEnabled:  user.TwoFactorEnabled,
+		PasswordChangedAt: user.PasswordChangedAt,
+		AnonymizedAt:      user.AnonymizedAt,
+		CreatedAt:         user.CreatedAt,
+		UpdatedAt:         user.UpdatedAt,
 	}
 }
@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/gorm/schema"
+)
+
+func TestAnonymizedUserColumns(t *testing.T) {
+	now := time.Now()
+	columns := anonymizedUserColumns(7, now)
+	want := map[string]interface{}{
+		"email":              "deleted-user-7@anonymized.invalid",
+		"name":               "Deleted User",
+		"phone":              "",
+		"password":           "",
+		"email_verified":     false,
+		"is_active":          false,
+		"two_factor_enabled": false,
+		"anonymized_at":      now,
+	}
+	for column, value := range want {
+		if columns[column] != value {
+			t.Errorf("%s = %v, want %v", column, columns[column], value)
+		}
+	}
+}
+
+func TestErasedUserRowsKeepOrders(t *testing.T) {
+	var tables []string
+	for _, model := range erasedUserRows() {
+		s, err := schema.Parse(model, &sync.Map{}, schema.NamingStrategy{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		tables = append(tables, s.Table)
+	}
+	for _, table := range []string{"user_addresses", "user_two_factors", "api_keys", "password_reset_tokens", "refresh_tokens", "sessions"} {
+		if !slices.Contains(tables, table) {
+			t.Errorf("rows of %s are kept, want them deleted", table)
+		}
+	}
+	for _, table := range []string{"orders", "order_items", "payments", "users"} {
+		if slices.Contains(tables, table) {
+			t.Errorf("rows of %s are deleted, want them kept", table)
+		}
+	}
+	for _, field := range []string{"recipient_name", "phone", "address_line_1", "address_line_2"} {
+		if !slices.Contains(identifyingShippingFields, field) {
+			t.Errorf("shipping address field %s is kept", field)
+		}
+	}
+	for _, field := range []string{"city", "state", "postal_code", "country"} {
+		if slices.Contains(identifyingShippingFields, field) {
+			t.Errorf("shipping address field %s is removed, want it kept for tax records", field)
+		}
+	}
+}
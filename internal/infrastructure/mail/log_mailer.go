@@ -0,0 +1,40 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"mini-ecommerce/pkg/logger"
+)
+
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+type logMailer struct {
+	from      string
+	outputDir string
+}
+
+// NewLogMailer returns a development mailer that logs every message and, when
+// outputDir is set, also writes it to an .eml file in that directory.
+func NewLogMailer(from, outputDir string) Mailer {
+	return &logMailer{
+		from:      from,
+		outputDir: outputDir,
+	}
+}
+
+func (m *logMailer) Send(ctx context.Context, msg Message) error {
+	logger.Infof("[Mailer] to=%s subject=%q\n%s", msg.To, msg.Subject, msg.Body)
+	if m.outputDir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(m.outputDir, 0o755); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%d_%s.eml", time.Now().UnixNano(), unsafeFileChars.ReplaceAllString(msg.To, "_"))
+	return os.WriteFile(filepath.Join(m.outputDir, name), buildMessage(m.from, msg), 0o644)
+}
@@ -0,0 +1,53 @@
+package search
+
+import "context"
+
+// Highlights in Hit and Suggestion are HTML-escaped with matches wrapped in
+// HighlightStart and HighlightEnd.
+const (
+	HighlightStart = "<mark>"
+	HighlightEnd   = "</mark>"
+)
+
+// Query is a product search. Only active products are searched.
+type Query struct {
+	Text       string
+	CategoryID *int
+	Offset     int
+	Limit      int
+}
+
+// Hit is a matching product, ordered by descending Score.
+type Hit struct {
+	ProductID int
+	Score     float64
+	// Name is the product name with the matched words highlighted
+	Name string
+	// Snippet is the part of the description around the matched words
+	Snippet string
+}
+
+type Result struct {
+	Hits  []Hit
+	Total int64
+	// Fuzzy is set when nothing matched exactly and the hits are spelling
+	// corrections of the query
+	Fuzzy bool
+}
+
+// Suggestion completes a partially typed query to a product name.
+type Suggestion struct {
+	ProductID int
+	Slug      string
+	Name      string
+	// Highlight is the name with the typed prefix highlighted
+	Highlight string
+}
+
+// SearchIndex finds products by text. The index is kept separate from the
+// catalog repository so an external search engine can replace the database.
+type SearchIndex interface {
+	Search(ctx context.Context, query Query) (*Result, error)
+	// Suggest returns up to limit product names matching the typed prefix.
+	Suggest(ctx context.Context, prefix string, limit int) ([]Suggestion, error)
+}
@@ -0,0 +1,216 @@
+package search
+
+import (
+	"context"
+	"html"
+	"mini-ecommerce/pkg/utils"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// textSearchConfig must match the to_tsvector indexes on products
+	textSearchConfig = "english"
+	// markStart and markEnd delimit ts_headline matches until the text is
+	// HTML-escaped. They are control characters, so product text does not
+	// contain them.
+	markStart = "\x02"
+	markEnd   = "\x03"
+	// snippetLength caps the description shown for fuzzy hits, which have no
+	// matched words to center on
+	snippetLength = 160
+	// minFuzzyPrefix is the shortest prefix for which Suggest adds typo
+	// tolerant matches; shorter prefixes match too many names
+	minFuzzyPrefix = 3
+)
+
+var (
+	tsQuery = "websearch_to_tsquery('" + textSearchConfig + "', ?)"
+	// fullTextMatch uses the GIN indexes on name and description separately
+	fullTextMatch = "(to_tsvector('" + textSearchConfig + "', products.name) @@ " + tsQuery +
+		" OR to_tsvector('" + textSearchConfig + "', products.description) @@ " + tsQuery + ")"
+	// weightedDocument ranks name matches above description matches
+	weightedDocument = "setweight(to_tsvector('" + textSearchConfig + "', products.name), 'A') || " +
+		"setweight(to_tsvector('" + textSearchConfig + "', COALESCE(products.description, '')), 'B')"
+
+	nameHeadline    = `StartSel="` + markStart + `", StopSel="` + markEnd + `", HighlightAll=true`
+	snippetHeadline = `StartSel="` + markStart + `", StopSel="` + markEnd + `", MinWords=10, MaxWords=30, MaxFragments=2, FragmentDelimiter=" ... "`
+
+	highlightReplacer = strings.NewReplacer(markStart, HighlightStart, markEnd, HighlightEnd)
+)
+
+// postgresIndex searches the products table directly with full-text search,
+// ranked by ts_rank, and falls back to pg_trgm similarity for misspelled
+// queries.
+type postgresIndex struct {
+	db *gorm.DB
+}
+
+func NewPostgresIndex(db *gorm.DB) SearchIndex {
+	return &postgresIndex{
+		db: db,
+	}
+}
+
+func (p *postgresIndex) Search(ctx context.Context, query Query) (*Result, error) {
+	text := strings.TrimSpace(query.Text)
+	if text == "" {
+		return &Result{Hits: []Hit{}}, nil
+	}
+	result, err := p.fullText(ctx, query, text)
+	if err != nil || result.Total > 0 {
+		return result, err
+	}
+	return p.fuzzy(ctx, query, text)
+}
+
+func (p *postgresIndex) fullText(ctx context.Context, query Query, text string) (*Result, error) {
+	tx := p.products(ctx, query).Where(fullTextMatch, text, text).Session(&gorm.Session{})
+	var total int64
+	if err := tx.Count(&total).Error; err != nil {
+		return nil, err
+	}
+	var rows []Hit
+	err := tx.Select(
+		"products.id AS product_id, "+
+			"ts_rank("+weightedDocument+", "+tsQuery+") AS score, "+
+			"ts_headline('"+textSearchConfig+"', products.name, "+tsQuery+", ?) AS name, "+
+			"ts_headline('"+textSearchConfig+"', COALESCE(products.description, ''), "+tsQuery+", ?) AS snippet",
+		text, text, nameHeadline, text, snippetHeadline).
+		Order("score DESC, products.id ASC").
+		Offset(query.Offset).Limit(query.Limit).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for i := range rows {
+		rows[i].Name = highlight(rows[i].Name)
+		rows[i].Snippet = highlight(rows[i].Snippet)
+	}
+	return &Result{Hits: rows, Total: total}, nil
+}
+
+// fuzzy matches the query against the best matching part of product names,
+// so "iphnoe" still finds "Apple iPhone 15".
+func (p *postgresIndex) fuzzy(ctx context.Context, query Query, text string) (*Result, error) {
+	tx := p.products(ctx, query).Where("? <% products.name", text).Session(&gorm.Session{})
+	var total int64
+	if err := tx.Count(&total).Error; err != nil {
+		return nil, err
+	}
+	var rows []Hit
+	err := tx.Select("products.id AS product_id, word_similarity(?, products.name) AS score, "+
+		"products.name AS name, COALESCE(products.description, '') AS snippet", text).
+		Order("score DESC, products.id ASC").
+		Offset(query.Offset).Limit(query.Limit).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for i := range rows {
+		rows[i].Name = html.EscapeString(rows[i].Name)
+		rows[i].Snippet = html.EscapeString(truncateWords(rows[i].Snippet, snippetLength))
+	}
+	return &Result{Hits: rows, Total: total, Fuzzy: true}, nil
+}
+
+func (p *postgresIndex) Suggest(ctx context.Context, prefix string, limit int) ([]Suggestion, error) {
+	prefix = strings.TrimSpace(prefix)
+	suggestions := make([]Suggestion, 0, limit)
+	if prefix == "" || limit <= 0 {
+		return suggestions, nil
+	}
+	escaped := utils.EscapeLike(prefix)
+	var rows []Suggestion
+	// Names starting with the prefix come before names with a later word
+	// starting with it; shorter names first as they are closer to complete.
+	err := p.products(ctx, Query{}).
+		Select("products.id AS product_id, products.slug, products.name").
+		Where("products.name ILIKE ? OR products.name ILIKE ?", escaped+"%", "% "+escaped+"%").
+		Order(orderBy("products.name ILIKE ? DESC, LENGTH(products.name) ASC, products.name ASC", escaped+"%")).
+		Limit(limit).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) < limit && len([]rune(prefix)) >= minFuzzyPrefix {
+		seen := make([]int, 0, len(rows)+1)
+		seen = append(seen, 0)
+		for _, row := range rows {
+			seen = append(seen, row.ProductID)
+		}
+		var fuzzy []Suggestion
+		err := p.products(ctx, Query{}).
+			Select("products.id AS product_id, products.slug, products.name").
+			Where("? <% products.name AND products.id NOT IN ?", prefix, seen).
+			Order(orderBy("word_similarity(?, products.name) DESC, products.name ASC", prefix)).
+			Limit(limit - len(rows)).
+			Scan(&fuzzy).Error
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, fuzzy...)
+	}
+	for _, row := range rows {
+		row.Highlight = highlightPrefix(row.Name, prefix)
+		suggestions = append(suggestions, row)
+	}
+	return suggestions, nil
+}
+
+// products starts a query on the active products of the query's category.
+func (p *postgresIndex) products(ctx context.Context, query Query) *gorm.DB {
+	tx := p.db.WithContext(ctx).Table("products").Where("products.is_active = ?", true)
+	if query.CategoryID != nil {
+		tx = tx.Where("products.category_id = ?", *query.CategoryID)
+	}
+	return tx
+}
+
+// orderBy builds an ORDER BY with bound values, which Order does not take
+// as a plain string.
+func orderBy(sql string, vars ...interface{}) clause.OrderBy {
+	return clause.OrderBy{Expression: clause.Expr{SQL: sql, Vars: vars, WithoutParentheses: true}}
+}
+
+// highlight escapes ts_headline output and turns its markers into tags.
+func highlight(s string) string {
+	return highlightReplacer.Replace(html.EscapeString(s))
+}
+
+// highlightPrefix escapes the name and highlights the first word starting
+// with prefix, compared case-insensitively.
+func highlightPrefix(name, prefix string) string {
+	for i := 0; i+len(prefix) <= len(name); i++ {
+		if i > 0 && name[i-1] != ' ' {
+			continue
+		}
+		if strings.EqualFold(name[i:i+len(prefix)], prefix) {
+			return html.EscapeString(name[:i]) +
+				HighlightStart + html.EscapeString(name[i:i+len(prefix)]) + HighlightEnd +
+				html.EscapeString(name[i+len(prefix):])
+		}
+	}
+	return html.EscapeString(name)
+}
+
+// truncateWords shortens s to at most n bytes, cutting at a space.
+func truncateWords(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	cut := strings.LastIndexByte(s[:n], ' ')
+	if cut <= 0 {
+		cut = n
+		for cut > 0 && !isRuneStart(s[cut]) {
+			cut--
+		}
+	}
+	return strings.TrimSpace(s[:cut]) + " ..."
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
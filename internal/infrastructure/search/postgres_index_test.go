@@ -0,0 +1,24 @@
+package search
+
+import "testing"
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		name, prefix, want string
+	}{
+		{"Desk Lamp", "desk", "<mark>Desk</mark> Lamp"},
+		{"Desk Lamp", "la", "Desk <mark>La</mark>mp"},
+		{"Island Lamp", "la", "Island <mark>La</mark>mp"},
+		{"<b>Bold</b> & Co", "bo", "&lt;b&gt;Bold&lt;/b&gt; &amp; Co"},
+		{"Tom & Jerry <Mug>", "tom", "<mark>Tom</mark> &amp; Jerry &lt;Mug&gt;"},
+		{"Desk Lamp", "chair", "Desk Lamp"},
+	}
+	for _, tt := range tests {
+		if got := highlightPrefix(tt.name, tt.prefix); got != tt.want {
+			t.Errorf("highlightPrefix(%q, %q) = %q, want %q", tt.name, tt.prefix, got, tt.want)
+		}
+	}
+	if got, want := highlight("<i>"+markStart+"Lamp"+markEnd+"</i>"), "&lt;i&gt;<mark>Lamp</mark>&lt;/i&gt;"; got != want {
+		t.Errorf("highlight() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"mini-ecommerce/internal/infrastructure/database/repositories"
+	"mini-ecommerce/internal/usecases"
+
+	"gorm.io/gorm"
+)
+
+var ErrUnknownCommand = errors.New("unknown command, expected import-products or export-products")
+
+// Run executes a maintenance command instead of starting the server. args
+// are the command name followed by its flags and arguments:
+//
+//	import-products [-format csv|jsonl] [-dry-run] [-resume] [-start-row n] [-actor id] <file>
+//	export-products [-format csv|jsonl] [-o file]
+func Run(ctx context.Context, db *gorm.DB, args []string) error {
+	if len(args) == 0 {
+		return ErrUnknownCommand
+	}
+	switch args[0] {
+	case "import-products":
+		return importProducts(ctx, newProductFileUseCase(db), args[1:])
+	case "export-products":
+		return exportProducts(ctx, newProductFileUseCase(db), args[1:])
+	}
+	return ErrUnknownCommand
+}
+
+func newProductFileUseCase(db *gorm.DB) usecases.ProductFileUsecase {
+	productRepo := repositories.NewProductRepositoryImpl(db)
+	categoryRepo := repositories.NewCategoryRepositoryImpl(db)
+	productUseCase := usecases.NewProductUseCase(productRepo, repositories.NewProductImageRepositoryImpl(db), repositories.NewProductVariantRepositoryImpl(db), categoryRepo)
+	return usecases.NewProductFileUseCase(productUseCase, productRepo, categoryRepo)
+}
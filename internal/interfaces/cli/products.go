@@ -0,0 +1,156 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"mini-ecommerce/internal/interfaces/http/dto"
+	"mini-ecommerce/internal/usecases"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// checkpointSuffix names the file next to an import file that records the
+// row to resume from
+const checkpointSuffix = ".checkpoint"
+
+// importProducts imports a file and prints the row errors and a summary.
+// Unless it is a dry run, the next row is recorded in a checkpoint file after
+// every row, so -resume continues an interrupted import; the checkpoint is
+// removed once the whole file is imported.
+func importProducts(ctx context.Context, productFileUseCase usecases.ProductFileUsecase, args []string) error {
+	flags := flag.NewFlagSet("import-products", flag.ContinueOnError)
+	format := flags.String("format", "", "csv or jsonl, taken from the file extension when omitted")
+	dryRun := flags.Bool("dry-run", false, "check every row without saving anything")
+	resume := flags.Bool("resume", false, "continue from the checkpoint of an interrupted import")
+	startRow := flags.Int("start-row", 0, "skip the rows before this one")
+	actor := flags.Int("actor", 0, "id of the admin user the changes are logged for (required)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("import-products takes exactly one file")
+	}
+	if *actor < 1 {
+		return errors.New("import-products needs -actor, the id of the admin user making the changes")
+	}
+	path := flags.Arg(0)
+	req := dto.ProductImportReq{
+		Format:   *format,
+		DryRun:   *dryRun,
+		StartRow: *startRow,
+	}
+	if req.Format == "" {
+		req.Format = usecases.ProductFileFormat(path)
+	}
+	checkpoint := path + checkpointSuffix
+	if *resume {
+		row, err := readCheckpoint(checkpoint)
+		if err != nil {
+			return err
+		}
+		req.StartRow = row
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var progress func(int)
+	var checkpointErr error
+	if !req.DryRun {
+		progress = func(nextRow int) {
+			if checkpointErr == nil {
+				checkpointErr = os.WriteFile(checkpoint, []byte(strconv.Itoa(nextRow)), 0o644)
+			}
+		}
+	}
+	res, err := productFileUseCase.Import(ctx, *actor, file, &req, progress)
+	if res != nil {
+		printImportResult(os.Stdout, res)
+	}
+	if err != nil {
+		if res != nil && !req.DryRun {
+			return fmt.Errorf("import stopped at row %d, run again with -resume to continue: %w", *res.NextRow, err)
+		}
+		return err
+	}
+	if checkpointErr != nil {
+		return checkpointErr
+	}
+	if !req.DryRun {
+		if err := os.Remove(checkpoint); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// exportProducts writes the catalog to a file, or to standard output when no
+// file is given.
+func exportProducts(ctx context.Context, productFileUseCase usecases.ProductFileUsecase, args []string) error {
+	flags := flag.NewFlagSet("export-products", flag.ContinueOnError)
+	format := flags.String("format", "", "csv or jsonl, taken from the output file extension when omitted, csv otherwise")
+	output := flags.String("o", "", "file to write, standard output when omitted")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	req := dto.ProductExportReq{Format: *format}
+	if req.Format == "" {
+		req.Format = usecases.ProductFileFormat(*output)
+	}
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+	buffered := bufio.NewWriter(w)
+	if err := productFileUseCase.Export(ctx, &req, buffered); err != nil {
+		return err
+	}
+	return buffered.Flush()
+}
+
+func readCheckpoint(path string) (int, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, fmt.Errorf("no checkpoint found at %s", path)
+		}
+		return 0, err
+	}
+	row, err := strconv.Atoi(strings.TrimSpace(string(content)))
+	if err != nil {
+		return 0, fmt.Errorf("invalid checkpoint %s: %v", path, err)
+	}
+	return row, nil
+}
+
+func printImportResult(w io.Writer, res *dto.ProductImportRes) {
+	for _, rowError := range res.Errors {
+		columns := make([]string, 0, len(rowError.Errors))
+		for column := range rowError.Errors {
+			columns = append(columns, column)
+		}
+		sort.Strings(columns)
+		for _, column := range columns {
+			fmt.Fprintf(w, "row %d (sku %q) %s: %s\n", rowError.Row, rowError.SKU, column, rowError.Errors[column])
+		}
+	}
+	summary := "imported"
+	if res.DryRun {
+		summary = "dry run, would import"
+	}
+	fmt.Fprintf(w, "%s: %d created, %d updated, %d failed\n", summary, res.Created, res.Updated, res.Failed)
+}
@@ -0,0 +1,48 @@
+package dto
+
+type AddressReq struct {
+	Label         string `json:"label" validate:"required,max=50"`
+	RecipientName string `json:"recipient_name" validate:"required,max=255"`
+	Phone         string `json:"phone" validate:"required,min=10,max=20"`
+	AddressLine1  string `json:"address_line_1" validate:"required,max=255"`
+	AddressLine2  string `json:"address_line_2" validate:"max=255"`
+	City          string `json:"city" validate:"required,max=100"`
+	State         string `json:"state" validate:"required,max=100"`
+	PostalCode    string `json:"postal_code" validate:"required,max=20"`
+	// Country accepts an ISO 3166-1 alpha-2 or alpha-3 code or the English name
+	Country   string `json:"country" validate:"required,max=100"`
+	IsDefault bool   `json:"is_default"`
+	UserID    int    `json:"-"`
+}
+
+// UpdateAddressReq is a partial update; omitted fields are left unchanged.
+type UpdateAddressReq struct {
+	Label         *string `json:"label" validate:"omitempty,min=1,max=50"`
+	RecipientName *string `json:"recipient_name" validate:"omitempty,min=1,max=255"`
+	Phone         *string `json:"phone" validate:"omitempty,min=10,max=20"`
+	AddressLine1  *string `json:"address_line_1" validate:"omitempty,min=1,max=255"`
+	AddressLine2  *string `json:"address_line_2" validate:"omitempty,max=255"`
+	City          *string `json:"city" validate:"omitempty,min=1,max=100"`
+	State         *string `json:"state" validate:"omitempty,min=1,max=100"`
+	PostalCode    *string `json:"postal_code" validate:"omitempty,min=1,max=20"`
+	Country       *string `json:"country" validate:"omitempty,min=1,max=100"`
+	IsDefault     *bool   `json:"is_default"`
+	UserID        int     `json:"-"`
+	ID            int     `json:"-"`
+}
+
+type AddressRes struct {
+	ID            int    `json:"id"`
+	Label         string `json:"label"`
+	RecipientName string `json:"recipient_name"`
+	Phone         string `json:"phone"`
+	AddressLine1  string `json:"address_line_1"`
+	AddressLine2  string `json:"address_line_2"`
+	City          string `json:"city"`
+	State         string `json:"state"`
+	PostalCode    string `json:"postal_code"`
+	Country       string `json:"country"`
+	IsDefault     bool   `json:"is_default"`
+	CreatedAt     string `json:"created_at"`
+	UpdatedAt     string `json:"updated_at"`
+}
@@ -0,0 +1,25 @@
+package dto
+
+type CreateAPIKeyReq struct {
+	Name   string   `json:"name" validate:"required,max=100"`
+	Scopes []string `json:"scopes" validate:"max=20,dive,required,max=50"`
+	// ExpiresInDays is optional; keys without it never expire
+	ExpiresInDays int `json:"expires_in_days" validate:"omitempty,min=1,max=365"`
+}
+
+type APIKeyRes struct {
+	ID         int      `json:"id"`
+	Name       string   `json:"name"`
+	Prefix     string   `json:"prefix"`
+	Scopes     []string `json:"scopes"`
+	ExpiresAt  string   `json:"expires_at,omitempty"`
+	LastUsedAt string   `json:"last_used_at,omitempty"`
+	RevokedAt  string   `json:"revoked_at,omitempty"`
+	CreatedAt  string   `json:"created_at"`
+}
+
+// APIKeyCreatedRes carries the plaintext key, which is only returned once
+type APIKeyCreatedRes struct {
+	APIKeyRes
+	Key string `json:"key"`
+}
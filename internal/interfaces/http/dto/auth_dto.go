@@ -1 +1,64 @@
-package dto
\ No newline at end of file
+package dto
+
+import "time"
+
+type RefreshTokenReq struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+	DeviceID     string `json:"device_id" validate:"max=255"`
+	UserAgent    string `json:"-"`
+	IPAddress    string `json:"-"`
+}
+
+type LogoutReq struct {
+	RefreshToken string    `json:"refresh_token"`
+	UserID       int       `json:"-"`
+	TokenID      string    `json:"-"`
+	SessionID    string    `json:"-"`
+	ExpiresAt    time.Time `json:"-"`
+}
+
+type VerifyEmailReq struct {
+	Token string `query:"token" json:"token" validate:"required"`
+}
+
+type ResendVerificationReq struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type ForgotPasswordReq struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// ResetPasswordReq is sent as JSON, or as a form by the reset page.
+type ResetPasswordReq struct {
+	Token    string `json:"token" form:"token" validate:"required"`
+	Password string `json:"password" form:"password" validate:"required,password"`
+}
+
+type TwoFactorCodeReq struct {
+	// Code is either a 6 digit TOTP code or an unused recovery code
+	Code string `json:"code" validate:"required,max=32"`
+}
+
+type TwoFactorLoginReq struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	Code           string `json:"code" validate:"required,max=32"`
+	DeviceID       string `json:"device_id" validate:"max=255"`
+	UserAgent      string `json:"-"`
+	IPAddress      string `json:"-"`
+}
+
+type TwoFactorStatusRes struct {
+	Enabled                bool  `json:"enabled"`
+	Required               bool  `json:"required"`
+	RecoveryCodesRemaining int64 `json:"recovery_codes_remaining"`
+}
+
+type TwoFactorSetupRes struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type TwoFactorRecoveryCodesRes struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
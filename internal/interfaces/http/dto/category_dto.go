@@ -1 +1,74 @@
-package dto
\ No newline at end of file
+package dto
+
+import "mini-ecommerce/pkg/utils"
+
+type CategoryReq struct {
+	ParentID *int   `json:"parent_id" validate:"omitempty,min=1"`
+	Name     string `json:"name" validate:"required,min=2,max=255"`
+	// Slug is generated from the name when omitted
+	Slug        string `json:"slug" validate:"omitempty,max=100,slug"`
+	Description string `json:"description"`
+	ImageURL    string `json:"image_url" validate:"omitempty,url,max=500"`
+	IsActive    *bool  `json:"is_active"`
+	SortOrder   int    `json:"sort_order"`
+}
+
+// UpdateCategoryReq is a partial update; omitted fields are left unchanged.
+// A parent_id of 0 moves the category to the root. Renaming regenerates the
+// slug unless one is given; the previous slug keeps resolving.
+type UpdateCategoryReq struct {
+	ParentID    *int    `json:"parent_id" validate:"omitempty,min=0"`
+	Name        *string `json:"name" validate:"omitempty,min=2,max=255"`
+	Slug        *string `json:"slug" validate:"omitempty,max=100,slug"`
+	Description *string `json:"description"`
+	ImageURL    *string `json:"image_url" validate:"omitempty,url,max=500"`
+	IsActive    *bool   `json:"is_active"`
+	SortOrder   *int    `json:"sort_order"`
+	ID          int     `json:"-"`
+}
+
+// ReorderCategoriesReq lists the subcategories of parent_id, or the root
+// categories when it is omitted, in their new order.
+type ReorderCategoriesReq struct {
+	ParentID *int  `json:"parent_id" validate:"omitempty,min=1"`
+	IDs      []int `json:"ids" validate:"required,min=1,dive,min=1"`
+}
+
+type CategoryListReq struct {
+	Page     int   `query:"page"`
+	Limit    int   `query:"limit"`
+	ParentID *int  `query:"parent_id"`
+	RootOnly bool  `query:"root_only"`
+	IsActive *bool `query:"is_active"`
+}
+
+type CategoryRes struct {
+	ID           int    `json:"id"`
+	ParentID     *int   `json:"parent_id"`
+	Name         string `json:"name"`
+	Slug         string `json:"slug"`
+	Description  string `json:"description"`
+	ImageURL     string `json:"image_url"`
+	IsActive     bool   `json:"is_active"`
+	SortOrder    int    `json:"sort_order"`
+	ProductCount int64  `json:"product_count"`
+	CreatedAt    string `json:"created_at"`
+	UpdatedAt    string `json:"updated_at"`
+}
+
+type CategoryListRes struct {
+	Categories []*CategoryRes   `json:"categories"`
+	Pagination utils.Pagination `json:"pagination"`
+}
+
+// CategoryTreeRes is a category with its subcategories nested below it
+type CategoryTreeRes struct {
+	*CategoryRes
+	Children []*CategoryTreeRes `json:"children"`
+}
+
+type BreadcrumbRes struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
@@ -0,0 +1,63 @@
+package dto
+
+// EraseAccountReq confirms the erasure of the caller's own account. Code is
+// required when two-factor authentication is enabled.
+type EraseAccountReq struct {
+	Password string `json:"password" validate:"required"`
+	Code     string `json:"code" validate:"max=32"`
+	UserID   int    `json:"-"`
+}
+
+// PersonalDataExportRes is the downloadable archive of a user's data.
+// Payment details are masked and credentials are never included.
+type PersonalDataExportRes struct {
+	ExportedAt string                `json:"exported_at"`
+	Profile    *UserRes              `json:"profile"`
+	Addresses  []*AddressRes         `json:"addresses"`
+	Orders     []*ExportedOrderRes   `json:"orders"`
+	Payments   []*ExportedPaymentRes `json:"payments"`
+	Reviews    []*ExportedReviewRes  `json:"reviews"`
+}
+
+type ExportedOrderRes struct {
+	ID              string                  `json:"id"`
+	Status          string                  `json:"status"`
+	Subtotal        float64                 `json:"subtotal"`
+	ShippingCost    float64                 `json:"shipping_cost"`
+	TaxAmount       float64                 `json:"tax_amount"`
+	TotalAmount     float64                 `json:"total_amount"`
+	PaymentMethod   string                  `json:"payment_method"`
+	PaymentStatus   string                  `json:"payment_status"`
+	ShippingAddress map[string]interface{}  `json:"shipping_address"`
+	TrackingNumber  string                  `json:"tracking_number"`
+	Notes           string                  `json:"notes"`
+	Items           []*ExportedOrderItemRes `json:"items"`
+	CreatedAt       string                  `json:"created_at"`
+}
+
+type ExportedOrderItemRes struct {
+	ProductID   int     `json:"product_id"`
+	ProductName string  `json:"product_name"`
+	Quantity    int     `json:"quantity"`
+	UnitPrice   float64 `json:"unit_price"`
+	TotalPrice  float64 `json:"total_price"`
+}
+
+type ExportedPaymentRes struct {
+	ID             string                 `json:"id"`
+	OrderID        string                 `json:"order_id"`
+	Amount         float64                `json:"amount"`
+	PaymentMethod  string                 `json:"payment_method"`
+	Status         string                 `json:"status"`
+	TransactionID  string                 `json:"transaction_id"`
+	PaymentDetails map[string]interface{} `json:"payment_details"`
+	CreatedAt      string                 `json:"created_at"`
+}
+
+type ExportedReviewRes struct {
+	ProductID  int    `json:"product_id"`
+	Rating     int    `json:"rating"`
+	Title      string `json:"title"`
+	ReviewText string `json:"review_text"`
+	CreatedAt  string `json:"created_at"`
+}
@@ -1 +1,188 @@
-package dto
\ No newline at end of file
+package dto
+
+import "mini-ecommerce/pkg/utils"
+
+type ProductImageReq struct {
+	URL       string `json:"url" validate:"required,url,max=500"`
+	AltText   string `json:"alt_text" validate:"max=255"`
+	IsPrimary bool   `json:"is_primary"`
+	// VariantID shows the image for one variant; images of a new product
+	// cannot have one
+	VariantID *int `json:"variant_id" validate:"omitempty,min=1"`
+}
+
+// ProductVariantReq gives a value for each of the product's options. Price
+// and weight fall back to the product's when omitted. Updating a variant
+// replaces all of its fields.
+type ProductVariantReq struct {
+	SKU           string            `json:"sku" validate:"required,max=100"`
+	Options       map[string]string `json:"options" validate:"required,min=1,max=3,dive,keys,min=1,max=50,endkeys,required,max=100"`
+	Price         *float64          `json:"price" validate:"omitempty,gte=0"`
+	StockQuantity int               `json:"stock_quantity" validate:"gte=0"`
+	Weight        *float64          `json:"weight" validate:"omitempty,gte=0"`
+	IsActive      *bool             `json:"is_active"`
+}
+
+// ProductReq creates a product. At most one image may be primary; without
+// one the first image becomes primary. With variants the stock quantity is
+// the total of the active variants' stock.
+type ProductReq struct {
+	Name string `json:"name" validate:"required,min=2,max=255"`
+	// Slug is generated from the name when omitted
+	Slug           string                 `json:"slug" validate:"omitempty,max=100,slug"`
+	Description    string                 `json:"description"`
+	Price          float64                `json:"price" validate:"gte=0"`
+	StockQuantity  int                    `json:"stock_quantity" validate:"gte=0"`
+	CategoryID     int                    `json:"category_id" validate:"required,min=1"`
+	SKU            string                 `json:"sku" validate:"omitempty,max=100"`
+	Images         []ProductImageReq      `json:"images" validate:"omitempty,max=20,dive"`
+	Specifications map[string]interface{} `json:"specifications"`
+	IsActive       *bool                  `json:"is_active"`
+	Weight         float64                `json:"weight" validate:"gte=0"`
+	Dimensions     map[string]interface{} `json:"dimensions"`
+	// Options names the option axes of the variants, such as size and color
+	Options  []string            `json:"options" validate:"omitempty,max=3,dive,required,max=50"`
+	Variants []ProductVariantReq `json:"variants" validate:"omitempty,max=100,dive"`
+}
+
+// UpdateProductReq is a partial update; omitted fields are left unchanged.
+// Renaming regenerates the slug unless one is given; the previous slug keeps
+// resolving. Images and variants are managed through their own endpoints;
+// options can only be reordered while the product has variants.
+type UpdateProductReq struct {
+	Name           *string                 `json:"name" validate:"omitempty,min=2,max=255"`
+	Slug           *string                 `json:"slug" validate:"omitempty,max=100,slug"`
+	Description    *string                 `json:"description"`
+	Price          *float64                `json:"price" validate:"omitempty,gte=0"`
+	StockQuantity  *int                    `json:"stock_quantity" validate:"omitempty,gte=0"`
+	CategoryID     *int                    `json:"category_id" validate:"omitempty,min=1"`
+	SKU            *string                 `json:"sku" validate:"omitempty,max=100"`
+	Specifications *map[string]interface{} `json:"specifications"`
+	IsActive       *bool                   `json:"is_active"`
+	Weight         *float64                `json:"weight" validate:"omitempty,gte=0"`
+	Dimensions     *map[string]interface{} `json:"dimensions"`
+	Options        *[]string               `json:"options" validate:"omitempty,max=3,dive,required,max=50"`
+	ID             int                     `json:"-"`
+}
+
+type ProductListReq struct {
+	Page       int      `query:"page"`
+	Limit      int      `query:"limit"`
+	CategoryID *int     `query:"category_id"`
+	Search     string   `query:"search" validate:"max=100"`
+	MinPrice   *float64 `query:"min_price" validate:"omitempty,gte=0"`
+	MaxPrice   *float64 `query:"max_price" validate:"omitempty,gte=0"`
+	InStock    bool     `query:"in_stock"`
+	SortBy     string   `query:"sort_by"`
+	SortOrder  string   `query:"sort_order" validate:"omitempty,oneof=asc desc"`
+	IsActive   *bool    `query:"is_active"`
+	// Specs holds the spec.<key>=<value> parameters, filled by the handler
+	Specs map[string][]string `query:"-" validate:"max=10,dive,keys,min=1,max=50,endkeys,min=1,max=20,dive,min=1,max=100"`
+}
+
+// UpdateStockReq sets a stock quantity and nothing else.
+type UpdateStockReq struct {
+	StockQuantity *int `json:"stock_quantity" validate:"required,gte=0"`
+}
+
+// ReorderImagesReq lists every image of the product in its new order.
+type ReorderImagesReq struct {
+	IDs []int `json:"ids" validate:"required,min=1,dive,min=1"`
+}
+
+type ProductImageRes struct {
+	ID        int    `json:"id"`
+	VariantID *int   `json:"variant_id"`
+	URL       string `json:"url"`
+	AltText   string `json:"alt_text"`
+	IsPrimary bool   `json:"is_primary"`
+	SortOrder int    `json:"sort_order"`
+}
+
+// ProductVariantRes carries the price and weight that apply to the variant;
+// the overrides are null when it uses the product's.
+type ProductVariantRes struct {
+	ID             int                `json:"id"`
+	SKU            string             `json:"sku"`
+	Options        map[string]string  `json:"options"`
+	Price          float64            `json:"price"`
+	PriceOverride  *float64           `json:"price_override"`
+	StockQuantity  int                `json:"stock_quantity"`
+	Weight         float64            `json:"weight"`
+	WeightOverride *float64           `json:"weight_override"`
+	IsActive       bool               `json:"is_active"`
+	SortOrder      int                `json:"sort_order"`
+	Images         []*ProductImageRes `json:"images"`
+}
+
+// PriceRangeRes spans the prices of the active variants; both equal the
+// product price when it has none.
+type PriceRangeRes struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+}
+
+type ProductCategoryRes struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+type ProductRes struct {
+	ID             int                    `json:"id"`
+	Name           string                 `json:"name"`
+	Slug           string                 `json:"slug"`
+	Description    string                 `json:"description"`
+	Price          float64                `json:"price"`
+	PriceRange     PriceRangeRes          `json:"price_range"`
+	StockQuantity  int                    `json:"stock_quantity"`
+	CategoryID     int                    `json:"category_id"`
+	Category       *ProductCategoryRes    `json:"category"`
+	SKU            string                 `json:"sku"`
+	Images         []*ProductImageRes     `json:"images"`
+	Specifications map[string]interface{} `json:"specifications"`
+	IsActive       bool                   `json:"is_active"`
+	Weight         float64                `json:"weight"`
+	Dimensions     map[string]interface{} `json:"dimensions"`
+	// Options and Variants are left out of listings
+	Options     []string             `json:"options,omitempty"`
+	Variants    []*ProductVariantRes `json:"variants,omitempty"`
+	Rating      float64              `json:"rating"`
+	ReviewCount int64                `json:"review_count"`
+	CreatedAt   string               `json:"created_at"`
+	UpdatedAt   string               `json:"updated_at"`
+}
+
+type CategoryFacetRes struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Slug  string `json:"slug"`
+	Count int64  `json:"count"`
+}
+
+// PriceRangeFacetRes covers prices from min up to but excluding max; the
+// last range has no max.
+type PriceRangeFacetRes struct {
+	Min   float64  `json:"min"`
+	Max   *float64 `json:"max"`
+	Count int64    `json:"count"`
+}
+
+type SpecValueFacetRes struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// ProductFacetsRes counts the matching products per filter value for the
+// storefront's filter sidebar. Each dimension ignores its own filter.
+type ProductFacetsRes struct {
+	Categories     []*CategoryFacetRes             `json:"categories"`
+	PriceRanges    []*PriceRangeFacetRes           `json:"price_ranges"`
+	Specifications map[string][]*SpecValueFacetRes `json:"specifications"`
+}
+
+type ProductListRes struct {
+	Products   []*ProductRes     `json:"products"`
+	Facets     *ProductFacetsRes `json:"facets"`
+	Pagination utils.Pagination  `json:"pagination"`
+}
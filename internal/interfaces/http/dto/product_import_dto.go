@@ -0,0 +1,66 @@
+package dto
+
+// ProductImportReq controls an import. Rows are numbered as in the file: in
+// CSV the header is row 1, in JSON Lines each line is a row. An import that
+// stopped early continues from the next_row of its response.
+type ProductImportReq struct {
+	// Format is taken from the file extension when omitted
+	Format string `query:"format" validate:"omitempty,oneof=csv jsonl"`
+	// DryRun checks every row without saving anything
+	DryRun bool `query:"dry_run"`
+	// StartRow skips the rows before it
+	StartRow int `query:"start_row" validate:"omitempty,min=1"`
+	// Limit stops the import after that many rows
+	Limit int `query:"limit" validate:"omitempty,min=1,max=10000"`
+}
+
+type ProductExportReq struct {
+	Format string `query:"format" validate:"omitempty,oneof=csv jsonl"`
+}
+
+// ProductFileRow is one product or variant of an import or export file. Rows
+// are matched to products and variants by SKU; nil fields and blank values
+// leave an existing one unchanged. New products need a name and a category.
+// Category is the category's name, or its path like "Fashion > Shoes" when
+// the name is used more than once. Images are only added to new products.
+//
+// A row with a parent SKU is a variant of that product and may only give
+// the options, price, stock quantity, weight and active flag; the options
+// of a new variant name a value for each of the product's option names.
+// Variant rows follow their product in exports.
+type ProductFileRow struct {
+	SKU            string                  `json:"sku" validate:"required,max=100"`
+	ParentSKU      *string                 `json:"parent_sku" validate:"omitempty,max=100"`
+	Name           *string                 `json:"name" validate:"omitempty,min=2,max=255"`
+	Slug           *string                 `json:"slug" validate:"omitempty,max=100,slug"`
+	Description    *string                 `json:"description"`
+	Price          *float64                `json:"price" validate:"omitempty,gte=0"`
+	StockQuantity  *int                    `json:"stock_quantity" validate:"omitempty,gte=0"`
+	Category       *string                 `json:"category" validate:"omitempty,max=500"`
+	IsActive       *bool                   `json:"is_active"`
+	Weight         *float64                `json:"weight" validate:"omitempty,gte=0"`
+	Specifications *map[string]interface{} `json:"specifications"`
+	Dimensions     *map[string]interface{} `json:"dimensions"`
+	Images         []string                `json:"images" validate:"omitempty,max=20,dive,url,max=500"`
+	OptionNames    []string                `json:"option_names" validate:"omitempty,max=3,dive,required,max=50"`
+	Options        map[string]string       `json:"options" validate:"omitempty,max=3,dive,keys,min=1,max=50,endkeys,required,max=100"`
+}
+
+// ProductImportRowError lists the problems of one row by column.
+type ProductImportRowError struct {
+	Row    int               `json:"row"`
+	SKU    string            `json:"sku"`
+	Errors map[string]string `json:"errors"`
+}
+
+// ProductImportRes counts the rows by outcome; in a dry run created and
+// updated count the rows that would be. NextRow is null once the end of the
+// file was reached.
+type ProductImportRes struct {
+	DryRun  bool                     `json:"dry_run"`
+	Created int                      `json:"created"`
+	Updated int                      `json:"updated"`
+	Failed  int                      `json:"failed"`
+	NextRow *int                     `json:"next_row"`
+	Errors  []*ProductImportRowError `json:"errors"`
+}
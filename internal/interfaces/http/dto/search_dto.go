@@ -0,0 +1,43 @@
+package dto
+
+import "mini-ecommerce/pkg/utils"
+
+type ProductSearchReq struct {
+	Q          string `query:"q" validate:"required,max=100"`
+	CategoryID *int   `query:"category_id" validate:"omitempty,min=1"`
+	Page       int    `query:"page"`
+	Limit      int    `query:"limit"`
+}
+
+type ProductSuggestReq struct {
+	Q     string `query:"q" validate:"required,max=100"`
+	Limit int    `query:"limit" validate:"omitempty,min=1,max=20"`
+}
+
+// SearchHighlightRes holds HTML-escaped text with the matched words wrapped
+// in <mark> tags.
+type SearchHighlightRes struct {
+	Name    string `json:"name"`
+	Snippet string `json:"snippet"`
+}
+
+type ProductSearchHitRes struct {
+	ProductRes
+	Score     float64            `json:"score"`
+	Highlight SearchHighlightRes `json:"highlight"`
+}
+
+type ProductSearchRes struct {
+	Products []*ProductSearchHitRes `json:"products"`
+	// Fuzzy is set when nothing matched exactly and the results are
+	// products with a similarly spelled name
+	Fuzzy      bool             `json:"fuzzy"`
+	Pagination utils.Pagination `json:"pagination"`
+}
+
+type ProductSuggestionRes struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	Slug      string `json:"slug"`
+	Highlight string `json:"highlight"`
+}
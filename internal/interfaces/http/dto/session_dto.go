@@ -0,0 +1,12 @@
+package dto
+
+type SessionRes struct {
+	ID             string `json:"id"`
+	DeviceID       string `json:"device_id"`
+	UserAgent      string `json:"user_agent"`
+	IPAddress      string `json:"ip_address"`
+	Current        bool   `json:"current"`
+	LastActivityAt string `json:"last_activity_at"`
+	ExpiresAt      string `json:"expires_at"`
+	CreatedAt      string `json:"created_at"`
+}
@@ -1,31 +1,78 @@
 package dto
 
+import "mini-ecommerce/pkg/utils"
+
 type UserRes struct {
-	ID        int    `json:"id"`
-	Email     string `json:"email"`
-	Password  string `json:"-"`
-	Name      string `json:"name"`
-	Phone     string `json:"phone"`
-	Role      string `json:"role"`
-	CreatedAt string `json:"created_at"`
-	UpdatedAt string `json:"updated_at"`
+	ID            int    `json:"id"`
+	Email         string `json:"email"`
+	Password      string `json:"-"`
+	Name          string `json:"name"`
+	Phone         string `json:"phone"`
+	Role          string `json:"role"`
+	EmailVerified bool   `json:"email_verified"`
+	IsActive      bool   `json:"is_active"`
+	CreatedAt     string `json:"created_at"`
+	UpdatedAt     string `json:"updated_at"`
+}
+
+// ProfileRes is the current user together with their address book
+type ProfileRes struct {
+	*UserRes
+	Addresses []*AddressRes `json:"addresses"`
 }
 
 type UserReq struct {
 	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required,min=3,max=32"`
+	Password string `json:"password" validate:"required,password"`
 	Name     string `json:"name" validate:"required,min=3,max=50"`
 	Phone    string `json:"phone" validate:"required,min=10,max=14"`
-	Role     string `json:"role" validate:"required,oneof=admin customer"`
+}
+
+// UpdateProfileReq is a partial update; omitted fields are left unchanged.
+// Changing the email or password requires CurrentPassword.
+type UpdateProfileReq struct {
+	Name            *string `json:"name" validate:"omitempty,min=3,max=50"`
+	Phone           *string `json:"phone" validate:"omitempty,min=10,max=14"`
+	Email           *string `json:"email" validate:"omitempty,email"`
+	NewPassword     *string `json:"new_password" validate:"omitempty,password"`
+	CurrentPassword string  `json:"current_password"`
+	UserID          int     `json:"-"`
+	SessionID       string  `json:"-"`
 }
 
 type UserLoginReq struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email     string `json:"email" validate:"required,email"`
+	Password  string `json:"password" validate:"required"`
+	DeviceID  string `json:"device_id" validate:"max=255"`
+	UserAgent string `json:"-"`
+	IPAddress string `json:"-"`
 }
 
 type UserLoginRes struct {
-	Name        string `json:"name"`
-	Email       string `json:"email"`
-	AccessToken string `json:"access_token"`
+	Name         string `json:"name"`
+	Email        string `json:"email"`
+	AccessToken  string `json:"access_token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	// TwoFactorRequired means the login must be completed at /auth/login/2fa with ChallengeToken
+	TwoFactorRequired bool   `json:"two_factor_required,omitempty"`
+	ChallengeToken    string `json:"challenge_token,omitempty"`
+	// TwoFactorSetupRequired means the role requires 2FA but the user has not enrolled yet
+	TwoFactorSetupRequired bool `json:"two_factor_setup_required,omitempty"`
+}
+
+type UserListReq struct {
+	Page     int    `query:"page"`
+	Limit    int    `query:"limit"`
+	Search   string `query:"search"`
+	Role     string `query:"role"`
+	IsActive *bool  `query:"is_active"`
+}
+
+type UserListRes struct {
+	Users      []*UserRes       `json:"users"`
+	Pagination utils.Pagination `json:"pagination"`
+}
+
+type UpdateUserRoleReq struct {
+	Role string `json:"role" validate:"required"`
 }
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"errors"
+	"mini-ecommerce/internal/interfaces/http/dto"
+	"mini-ecommerce/internal/interfaces/http/middleware"
+	"mini-ecommerce/internal/usecases"
+	"mini-ecommerce/pkg/validation"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AddressHandler interface {
+	List(c *fiber.Ctx) error
+	Create(c *fiber.Ctx) error
+	Update(c *fiber.Ctx) error
+	Delete(c *fiber.Ctx) error
+}
+
+type addressHandler struct {
+	addressUseCase usecases.AddressUsecase
+}
+
+// List implements AddressHandler.
+func (a *addressHandler) List(c *fiber.Ctx) error {
+	principal, _ := middleware.CurrentPrincipal(c)
+	res, err := a.addressUseCase.List(c.Context(), principal.UserID)
+	if err != nil {
+		return addressError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "Success",
+		"data":    res,
+	})
+}
+
+// Create implements AddressHandler.
+func (a *addressHandler) Create(c *fiber.Ctx) error {
+	var req dto.AddressReq
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  false,
+			"message": "Invalid request body",
+		})
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"status":  false,
+			"message": "Unprocessable Entity",
+			"errors":  validationErrors(err),
+		})
+	}
+	principal, _ := middleware.CurrentPrincipal(c)
+	req.UserID = principal.UserID
+	res, err := a.addressUseCase.Create(c.Context(), &req)
+	if err != nil {
+		return addressError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  true,
+		"message": "Address added successfully",
+		"data":    res,
+	})
+}
+
+// Update implements AddressHandler.
+func (a *addressHandler) Update(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return invalidID(c)
+	}
+	var req dto.UpdateAddressReq
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  false,
+			"message": "Invalid request body",
+		})
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"status":  false,
+			"message": "Unprocessable Entity",
+			"errors":  validationErrors(err),
+		})
+	}
+	principal, _ := middleware.CurrentPrincipal(c)
+	req.UserID = principal.UserID
+	req.ID = id
+	res, err := a.addressUseCase.Update(c.Context(), &req)
+	if err != nil {
+		return addressError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "Address updated successfully",
+		"data":    res,
+	})
+}
+
+// Delete implements AddressHandler.
+func (a *addressHandler) Delete(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return invalidID(c)
+	}
+	principal, _ := middleware.CurrentPrincipal(c)
+	if err := a.addressUseCase.Delete(c.Context(), principal.UserID, id); err != nil {
+		return addressError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "Address deleted successfully",
+	})
+}
+
+func addressError(c *fiber.Ctx, err error) error {
+	var addressErr *validation.AddressError
+	switch {
+	case errors.As(err, &addressErr):
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"status":  false,
+			"message": "Unprocessable Entity",
+			"errors":  addressErr.Fields,
+		})
+	case errors.Is(err, usecases.ErrAddressNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  false,
+			"message": err.Error(),
+		})
+	case errors.Is(err, usecases.ErrDefaultAddressRequired):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"status":  false,
+			"message": err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"status":  false,
+		"message": "Internal Server Error",
+	})
+}
+
+func NewAddressHandler(addressUseCase usecases.AddressUsecase) AddressHandler {
+	return &addressHandler{
+		addressUseCase: addressUseCase,
+	}
+}
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"errors"
+	"mini-ecommerce/internal/interfaces/http/dto"
+	"mini-ecommerce/internal/interfaces/http/middleware"
+	"mini-ecommerce/internal/usecases"
+	"mini-ecommerce/pkg/validation"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// APIKeyHandler serves both the caller's own keys and, for admins, the keys
+// of the user given by the :id path parameter.
+type APIKeyHandler interface {
+	List(c *fiber.Ctx) error
+	Create(c *fiber.Ctx) error
+	Revoke(c *fiber.Ctx) error
+	ListForUser(c *fiber.Ctx) error
+	CreateForUser(c *fiber.Ctx) error
+	RevokeForUser(c *fiber.Ctx) error
+}
+
+type apiKeyHandler struct {
+	apiKeyUseCase usecases.APIKeyUsecase
+}
+
+// List implements APIKeyHandler.
+func (a *apiKeyHandler) List(c *fiber.Ctx) error {
+	principal, _ := middleware.CurrentPrincipal(c)
+	return a.list(c, principal.UserID)
+}
+
+// Create implements APIKeyHandler.
+func (a *apiKeyHandler) Create(c *fiber.Ctx) error {
+	principal, _ := middleware.CurrentPrincipal(c)
+	return a.create(c, principal.UserID)
+}
+
+// Revoke implements APIKeyHandler.
+func (a *apiKeyHandler) Revoke(c *fiber.Ctx) error {
+	principal, _ := middleware.CurrentPrincipal(c)
+	return a.revoke(c, principal.UserID)
+}
+
+// ListForUser implements APIKeyHandler.
+func (a *apiKeyHandler) ListForUser(c *fiber.Ctx) error {
+	userID, err := c.ParamsInt("id")
+	if err != nil {
+		return invalidID(c)
+	}
+	return a.list(c, userID)
+}
+
+// CreateForUser implements APIKeyHandler.
+func (a *apiKeyHandler) CreateForUser(c *fiber.Ctx) error {
+	userID, err := c.ParamsInt("id")
+	if err != nil {
+		return invalidID(c)
+	}
+	return a.create(c, userID)
+}
+
+// RevokeForUser implements APIKeyHandler.
+func (a *apiKeyHandler) RevokeForUser(c *fiber.Ctx) error {
+	userID, err := c.ParamsInt("id")
+	if err != nil {
+		return invalidID(c)
+	}
+	return a.revoke(c, userID)
+}
+
+func (a *apiKeyHandler) list(c *fiber.Ctx, userID int) error {
+	res, err := a.apiKeyUseCase.List(c.Context(), userID)
+	if err != nil {
+		return apiKeyError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "Success",
+		"data":    res,
+	})
+}
+
+func (a *apiKeyHandler) create(c *fiber.Ctx, userID int) error {
+	var req dto.CreateAPIKeyReq
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  false,
+			"message": "Invalid request body",
+		})
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"status":  false,
+			"message": "Unprocessable Entity",
+			"errors":  validationErrors(err),
+		})
+	}
+	res, err := a.apiKeyUseCase.Create(c.Context(), userID, &req)
+	if err != nil {
+		return apiKeyError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  true,
+		"message": "API key created. Store the key safely, it is only shown once",
+		"data":    res,
+	})
+}
+
+func (a *apiKeyHandler) revoke(c *fiber.Ctx, userID int) error {
+	id, err := c.ParamsInt("keyId")
+	if err != nil {
+		return invalidID(c)
+	}
+	if err := a.apiKeyUseCase.Revoke(c.Context(), userID, id); err != nil {
+		return apiKeyError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "API key revoked",
+	})
+}
+
+func apiKeyError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, usecases.ErrAPIKeyNotFound), errors.Is(err, usecases.ErrUserNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  false,
+			"message": err.Error(),
+		})
+	case errors.Is(err, usecases.ErrInvalidScope):
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"status":  false,
+			"message": err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"status":  false,
+		"message": "Internal Server Error",
+	})
+}
+
+func NewAPIKeyHandler(apiKeyUseCase usecases.APIKeyUsecase) APIKeyHandler {
+	return &apiKeyHandler{
+		apiKeyUseCase: apiKeyUseCase,
+	}
+}
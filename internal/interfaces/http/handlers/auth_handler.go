@@ -1,9 +1,14 @@
 package handlers
 
 import (
+	"errors"
+	"html/template"
+	"math"
 	"mini-ecommerce/internal/interfaces/http/dto"
+	"mini-ecommerce/internal/interfaces/http/middleware"
 	"mini-ecommerce/internal/usecases"
 	"mini-ecommerce/pkg/validation"
+	"strconv"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
@@ -12,6 +17,14 @@ import (
 type AuthHandler interface {
 	Register(c *fiber.Ctx) error
 	Login(c *fiber.Ctx) error
+	LoginTwoFactor(c *fiber.Ctx) error
+	Refresh(c *fiber.Ctx) error
+	Logout(c *fiber.Ctx) error
+	VerifyEmail(c *fiber.Ctx) error
+	ResendVerification(c *fiber.Ctx) error
+	ForgotPassword(c *fiber.Ctx) error
+	ResetPassword(c *fiber.Ctx) error
+	ResetPasswordForm(c *fiber.Ctx) error
 }
 
 type authHandler struct {
@@ -20,7 +33,346 @@ type authHandler struct {
 
 // Login implements AuthHandler.
 func (a *authHandler) Login(c *fiber.Ctx) error {
-	panic("unimplemented")
+	var req dto.UserLoginReq
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  false,
+			"message": "Invalid request body",
+		})
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"status":  false,
+			"message": "Unprocessable Entity",
+			"errors":  validationErrors(err),
+		})
+	}
+	req.UserAgent = c.Get(fiber.HeaderUserAgent)
+	req.IPAddress = c.IP()
+	res, err := a.authUseCase.Login(c.Context(), &req)
+	if err != nil {
+		var locked *usecases.LoginLockedError
+		if errors.As(err, &locked) {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds()))))
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"status":  false,
+				"message": err.Error(),
+			})
+		}
+		switch {
+		case errors.Is(err, usecases.ErrInvalidCredentials):
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"status":  false,
+				"message": err.Error(),
+			})
+		case errors.Is(err, usecases.ErrUserInactive), errors.Is(err, usecases.ErrEmailNotVerified):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"status":  false,
+				"message": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  false,
+			"message": "Internal Server Error",
+		})
+	}
+	if res.TwoFactorRequired {
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"status":  true,
+			"message": "Two-factor authentication required",
+			"data":    res,
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "Login successful",
+		"data":    res,
+	})
+}
+
+// LoginTwoFactor implements AuthHandler.
+func (a *authHandler) LoginTwoFactor(c *fiber.Ctx) error {
+	var req dto.TwoFactorLoginReq
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  false,
+			"message": "Invalid request body",
+		})
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"status":  false,
+			"message": "Unprocessable Entity",
+			"errors":  validationErrors(err),
+		})
+	}
+	req.UserAgent = c.Get(fiber.HeaderUserAgent)
+	req.IPAddress = c.IP()
+	res, err := a.authUseCase.CompleteTwoFactorLogin(c.Context(), &req)
+	if err != nil {
+		var locked *usecases.LoginLockedError
+		if errors.As(err, &locked) {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds()))))
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"status":  false,
+				"message": err.Error(),
+			})
+		}
+		switch {
+		case errors.Is(err, usecases.ErrInvalidChallenge), errors.Is(err, usecases.ErrInvalidTwoFactorCode):
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"status":  false,
+				"message": err.Error(),
+			})
+		case errors.Is(err, usecases.ErrUserInactive):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"status":  false,
+				"message": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  false,
+			"message": "Internal Server Error",
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "Login successful",
+		"data":    res,
+	})
+}
+
+// Refresh implements AuthHandler.
+func (a *authHandler) Refresh(c *fiber.Ctx) error {
+	var req dto.RefreshTokenReq
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  false,
+			"message": "Invalid request body",
+		})
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"status":  false,
+			"message": "Unprocessable Entity",
+			"errors":  validationErrors(err),
+		})
+	}
+	req.UserAgent = c.Get(fiber.HeaderUserAgent)
+	req.IPAddress = c.IP()
+	res, err := a.authUseCase.Refresh(c.Context(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecases.ErrInvalidRefresh), errors.Is(err, usecases.ErrRefreshReused):
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"status":  false,
+				"message": err.Error(),
+			})
+		case errors.Is(err, usecases.ErrUserInactive):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"status":  false,
+				"message": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  false,
+			"message": "Internal Server Error",
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "Token refreshed",
+		"data":    res,
+	})
+}
+
+// Logout implements AuthHandler.
+func (a *authHandler) Logout(c *fiber.Ctx) error {
+	var req dto.LogoutReq
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status":  false,
+				"message": "Invalid request body",
+			})
+		}
+	}
+	principal, ok := middleware.CurrentPrincipal(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"status":  false,
+			"message": "Unauthorized",
+		})
+	}
+	req.UserID = principal.UserID
+	req.TokenID = principal.TokenID
+	req.SessionID = principal.SessionID
+	req.ExpiresAt = principal.ExpiresAt
+	if err := a.authUseCase.Logout(c.Context(), &req); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  false,
+			"message": "Internal Server Error",
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "Logout successful",
+	})
+}
+
+// VerifyEmail implements AuthHandler.
+func (a *authHandler) VerifyEmail(c *fiber.Ctx) error {
+	var req dto.VerifyEmailReq
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  false,
+			"message": "Invalid query parameters",
+		})
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"status":  false,
+			"message": "Unprocessable Entity",
+			"errors":  validationErrors(err),
+		})
+	}
+	if err := a.authUseCase.VerifyEmail(c.Context(), &req); err != nil {
+		if errors.Is(err, usecases.ErrInvalidVerifyToken) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status":  false,
+				"message": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  false,
+			"message": "Internal Server Error",
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "Email verified successfully",
+	})
+}
+
+// ResendVerification implements AuthHandler.
+func (a *authHandler) ResendVerification(c *fiber.Ctx) error {
+	var req dto.ResendVerificationReq
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  false,
+			"message": "Invalid request body",
+		})
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"status":  false,
+			"message": "Unprocessable Entity",
+			"errors":  validationErrors(err),
+		})
+	}
+	if err := a.authUseCase.ResendVerification(c.Context(), &req); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  false,
+			"message": "Internal Server Error",
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "If the account exists and is unverified, a verification email has been sent",
+	})
+}
+
+// ForgotPassword implements AuthHandler.
+func (a *authHandler) ForgotPassword(c *fiber.Ctx) error {
+	var req dto.ForgotPasswordReq
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  false,
+			"message": "Invalid request body",
+		})
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"status":  false,
+			"message": "Unprocessable Entity",
+			"errors":  validationErrors(err),
+		})
+	}
+	if err := a.authUseCase.ForgotPassword(c.Context(), &req); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  false,
+			"message": "Internal Server Error",
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "If an account exists for this email, a password reset link has been sent",
+	})
+}
+
+// ResetPassword implements AuthHandler.
+func (a *authHandler) ResetPassword(c *fiber.Ctx) error {
+	var req dto.ResetPasswordReq
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  false,
+			"message": "Invalid request body",
+		})
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"status":  false,
+			"message": "Unprocessable Entity",
+			"errors":  validationErrors(err),
+		})
+	}
+	if err := a.authUseCase.ResetPassword(c.Context(), &req); err != nil {
+		if errors.Is(err, usecases.ErrInvalidResetToken) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status":  false,
+				"message": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  false,
+			"message": "Internal Server Error",
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "Password has been reset",
+	})
+}
+
+// resetPasswordPage is the form password reset emails link to when no
+// reset page of a frontend is configured. It posts back to the same path.
+var resetPasswordPage = template.Must(template.New("reset-password").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Reset your password</title>
+</head>
+<body>
+<h1>Reset your password</h1>
+<form method="post" action="reset-password">
+<input type="hidden" name="token" value="{{.}}">
+<label for="password">New password</label>
+<input type="password" id="password" name="password" autocomplete="new-password" required>
+<button type="submit">Reset password</button>
+</form>
+</body>
+</html>
+`))
+
+// ResetPasswordForm implements AuthHandler.
+// It serves the page of the emailed reset link, which submits the token and
+// the new password to ResetPassword.
+func (a *authHandler) ResetPasswordForm(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	c.Set("Referrer-Policy", "no-referrer")
+	c.Type("html", "utf-8")
+	return resetPasswordPage.Execute(c, c.Query("token"))
 }
 
 // Register implements AuthHandler.
@@ -33,23 +385,23 @@ func (a *authHandler) Register(c *fiber.Ctx) error {
 		})
 	}
 	if err := validation.Validate.Struct(req); err != nil {
-		errs := err.(validator.ValidationErrors)
-		errMsg := make(map[string]string)
-		for _, e := range errs {
-			field := e.Field()
-			errMsg[field] = e.Translate(validation.Trans)
-		}
 		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
 			"status":  false,
 			"message": "Unprocessable Entity",
-			"errors":  errMsg,
+			"errors":  validationErrors(err),
 		})
 	}
 	res, err := a.authUseCase.Register(c.Context(), &req)
 	if err != nil {
+		if errors.Is(err, usecases.ErrEmailExists) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"status":  false,
+				"message": err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"status":  false,
-			"message": err.Error() + "asds",
+			"message": "Internal Server Error",
 		})
 	}
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
@@ -67,6 +419,20 @@ func (a *authHandler) Register(c *fiber.Ctx) error {
 	})
 }
 
+// validationErrors maps validator errors to translated messages keyed by field name.
+func validationErrors(err error) map[string]string {
+	errMsg := make(map[string]string)
+	errs, ok := err.(validator.ValidationErrors)
+	if !ok {
+		errMsg["request"] = err.Error()
+		return errMsg
+	}
+	for _, e := range errs {
+		errMsg[e.Field()] = e.Translate(validation.Trans)
+	}
+	return errMsg
+}
+
 func NewAuthHandler(authUseCase usecases.AuthUsecase) AuthHandler {
 	return &authHandler{
 		authUseCase: authUseCase,
@@ -1 +1,289 @@
-package handlers
\ No newline at end of file
+package handlers
+
+import (
+	"errors"
+	"mini-ecommerce/internal/domain/entities"
+	"mini-ecommerce/internal/interfaces/http/dto"
+	"mini-ecommerce/internal/interfaces/http/middleware"
+	"mini-ecommerce/internal/usecases"
+	"mini-ecommerce/pkg/validation"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type CategoryHandler interface {
+	List(c *fiber.Ctx) error
+	GetById(c *fiber.Ctx) error
+	GetBySlug(c *fiber.Ctx) error
+	Tree(c *fiber.Ctx) error
+	Subtree(c *fiber.Ctx) error
+	Breadcrumbs(c *fiber.Ctx) error
+	Create(c *fiber.Ctx) error
+	Update(c *fiber.Ctx) error
+	Reorder(c *fiber.Ctx) error
+	Delete(c *fiber.Ctx) error
+}
+
+type categoryHandler struct {
+	categoryUseCase usecases.CategoryUsecase
+}
+
+// List implements CategoryHandler.
+func (h *categoryHandler) List(c *fiber.Ctx) error {
+	var req dto.CategoryListReq
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  false,
+			"message": "Invalid query parameters",
+		})
+	}
+	// Hidden categories are listed to catalog managers only.
+	if !canSeeInactiveCategories(c) {
+		active := true
+		req.IsActive = &active
+	}
+	res, err := h.categoryUseCase.List(c.Context(), &req)
+	if err != nil {
+		return categoryError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "Success",
+		"data":    res,
+	})
+}
+
+// GetById implements CategoryHandler.
+func (h *categoryHandler) GetById(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return invalidID(c)
+	}
+	res, err := h.categoryUseCase.GetById(c.Context(), id, !canSeeInactiveCategories(c))
+	if err != nil {
+		return categoryError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "Success",
+		"data":    res,
+	})
+}
+
+// GetBySlug implements CategoryHandler.
+// A previous slug answers with a permanent redirect to the current one.
+func (h *categoryHandler) GetBySlug(c *fiber.Ctx) error {
+	slug := c.Params("slug")
+	res, err := h.categoryUseCase.GetBySlug(c.Context(), slug, !canSeeInactiveCategories(c))
+	if err != nil {
+		return categoryError(c, err)
+	}
+	if res.Slug != slug {
+		return slugMoved(c, "/categories/slug/"+res.Slug, res)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "Success",
+		"data":    res,
+	})
+}
+
+// Tree implements CategoryHandler.
+func (h *categoryHandler) Tree(c *fiber.Ctx) error {
+	res, err := h.categoryUseCase.Tree(c.Context(), !canSeeInactiveCategories(c))
+	if err != nil {
+		return categoryError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "Success",
+		"data":    res,
+	})
+}
+
+// Subtree implements CategoryHandler.
+func (h *categoryHandler) Subtree(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return invalidID(c)
+	}
+	res, err := h.categoryUseCase.Subtree(c.Context(), id, !canSeeInactiveCategories(c))
+	if err != nil {
+		return categoryError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "Success",
+		"data":    res,
+	})
+}
+
+// Breadcrumbs implements CategoryHandler.
+func (h *categoryHandler) Breadcrumbs(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return invalidID(c)
+	}
+	res, err := h.categoryUseCase.Breadcrumbs(c.Context(), id, !canSeeInactiveCategories(c))
+	if err != nil {
+		return categoryError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "Success",
+		"data":    res,
+	})
+}
+
+// Create implements CategoryHandler.
+func (h *categoryHandler) Create(c *fiber.Ctx) error {
+	var req dto.CategoryReq
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  false,
+			"message": "Invalid request body",
+		})
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"status":  false,
+			"message": "Unprocessable Entity",
+			"errors":  validationErrors(err),
+		})
+	}
+	res, err := h.categoryUseCase.Create(c.Context(), &req)
+	if err != nil {
+		return categoryError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  true,
+		"message": "Category created successfully",
+		"data":    res,
+	})
+}
+
+// Update implements CategoryHandler.
+func (h *categoryHandler) Update(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return invalidID(c)
+	}
+	var req dto.UpdateCategoryReq
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  false,
+			"message": "Invalid request body",
+		})
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"status":  false,
+			"message": "Unprocessable Entity",
+			"errors":  validationErrors(err),
+		})
+	}
+	req.ID = id
+	principal, _ := middleware.CurrentPrincipal(c)
+	res, err := h.categoryUseCase.Update(c.Context(), principal.UserID, &req)
+	if err != nil {
+		return categoryError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "Category updated successfully",
+		"data":    res,
+	})
+}
+
+// Reorder implements CategoryHandler.
+func (h *categoryHandler) Reorder(c *fiber.Ctx) error {
+	var req dto.ReorderCategoriesReq
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  false,
+			"message": "Invalid request body",
+		})
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"status":  false,
+			"message": "Unprocessable Entity",
+			"errors":  validationErrors(err),
+		})
+	}
+	principal, _ := middleware.CurrentPrincipal(c)
+	if err := h.categoryUseCase.Reorder(c.Context(), principal.UserID, &req); err != nil {
+		return categoryError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "Categories reordered successfully",
+	})
+}
+
+// Delete implements CategoryHandler.
+func (h *categoryHandler) Delete(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return invalidID(c)
+	}
+	principal, _ := middleware.CurrentPrincipal(c)
+	if err := h.categoryUseCase.Delete(c.Context(), principal.UserID, id); err != nil {
+		return categoryError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "Category deleted successfully",
+	})
+}
+
+// canSeeInactiveCategories reports whether the caller manages categories;
+// everyone else only finds the categories shown in the tree.
+func canSeeInactiveCategories(c *fiber.Ctx) bool {
+	return middleware.CallerCan(c, entities.PermissionCategoryWrite)
+}
+
+func categoryError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, usecases.ErrCategoryNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  false,
+			"message": err.Error(),
+		})
+	case errors.Is(err, usecases.ErrParentCategoryNotFound),
+		errors.Is(err, usecases.ErrCategoryCycle):
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"status":  false,
+			"message": err.Error(),
+		})
+	case errors.Is(err, usecases.ErrCategoryExists),
+		errors.Is(err, usecases.ErrCategoryInUse),
+		errors.Is(err, usecases.ErrCategoryOrderMismatch),
+		errors.Is(err, usecases.ErrSlugTaken):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"status":  false,
+			"message": err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"status":  false,
+		"message": "Internal Server Error",
+	})
+}
+
+// slugMoved answers a request for a previous slug with a permanent redirect.
+// The body carries the resource as well for clients that do not follow it.
+func slugMoved(c *fiber.Ctx, location string, data interface{}) error {
+	c.Set(fiber.HeaderLocation, location)
+	return c.Status(fiber.StatusMovedPermanently).JSON(fiber.Map{
+		"status":  true,
+		"message": "Moved Permanently",
+		"data":    data,
+	})
+}
+
+func NewCategoryHandler(categoryUseCase usecases.CategoryUsecase) CategoryHandler {
+	return &categoryHandler{
+		categoryUseCase: categoryUseCase,
+	}
+}
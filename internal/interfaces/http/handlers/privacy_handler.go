@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"mini-ecommerce/internal/interfaces/http/dto"
+	"mini-ecommerce/internal/interfaces/http/middleware"
+	"mini-ecommerce/internal/usecases"
+	"mini-ecommerce/pkg/validation"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type PrivacyHandler interface {
+	Export(c *fiber.Ctx) error
+	Erase(c *fiber.Ctx) error
+}
+
+type privacyHandler struct {
+	privacyUseCase usecases.PrivacyUsecase
+}
+
+// Export implements PrivacyHandler.
+// The archive is sent as a JSON file download without the response envelope.
+func (p *privacyHandler) Export(c *fiber.Ctx) error {
+	principal, _ := middleware.CurrentPrincipal(c)
+	res, err := p.privacyUseCase.Export(c.Context(), principal.UserID)
+	if err != nil {
+		return privacyError(c, err)
+	}
+	c.Attachment(fmt.Sprintf("personal-data-%d-%s.json", principal.UserID, time.Now().Format("20060102")))
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.Status(fiber.StatusOK).JSON(res)
+}
+
+// Erase implements PrivacyHandler.
+func (p *privacyHandler) Erase(c *fiber.Ctx) error {
+	var req dto.EraseAccountReq
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  false,
+			"message": "Invalid request body",
+		})
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"status":  false,
+			"message": "Unprocessable Entity",
+			"errors":  validationErrors(err),
+		})
+	}
+	principal, _ := middleware.CurrentPrincipal(c)
+	req.UserID = principal.UserID
+	if err := p.privacyUseCase.Erase(c.Context(), &req); err != nil {
+		return privacyError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "Your account and personal data have been deleted",
+	})
+}
+
+func privacyError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, usecases.ErrUserNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  false,
+			"message": err.Error(),
+		})
+	case errors.Is(err, usecases.ErrInvalidCurrentPassword),
+		errors.Is(err, usecases.ErrInvalidTwoFactorCode):
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"status":  false,
+			"message": err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"status":  false,
+		"message": "Internal Server Error",
+	})
+}
+
+func NewPrivacyHandler(privacyUseCase usecases.PrivacyUsecase) PrivacyHandler {
+	return &privacyHandler{
+		privacyUseCase: privacyUseCase,
+	}
+}
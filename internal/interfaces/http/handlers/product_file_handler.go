@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"bufio"
+	"errors"
+	"mini-ecommerce/internal/interfaces/http/dto"
+	"mini-ecommerce/internal/interfaces/http/middleware"
+	"mini-ecommerce/internal/usecases"
+	"mini-ecommerce/pkg/logger"
+	"mini-ecommerce/pkg/validation"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ProductFileHandler interface {
+	Import(c *fiber.Ctx) error
+	Export(c *fiber.Ctx) error
+}
+
+type productFileHandler struct {
+	productFileUseCase usecases.ProductFileUsecase
+}
+
+// Import implements ProductFileHandler.
+// The file is uploaded as the "file" field of a multipart form.
+func (h *productFileHandler) Import(c *fiber.Ctx) error {
+	var req dto.ProductImportReq
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  false,
+			"message": "Invalid query parameters",
+		})
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"status":  false,
+			"message": "Unprocessable Entity",
+			"errors":  validationErrors(err),
+		})
+	}
+	header, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  false,
+			"message": "file is required",
+		})
+	}
+	if req.Format == "" {
+		req.Format = usecases.ProductFileFormat(header.Filename)
+	}
+	file, err := header.Open()
+	if err != nil {
+		return productFileError(c, err)
+	}
+	defer file.Close()
+
+	principal, _ := middleware.CurrentPrincipal(c)
+	res, err := h.productFileUseCase.Import(c.Context(), principal.UserID, file, &req, nil)
+	if err != nil {
+		if res != nil && !errors.Is(err, usecases.ErrInvalidProductFile) {
+			// Rows before next_row were saved; the import can continue there.
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"status":  false,
+				"message": "Import stopped by an internal error",
+				"data":    res,
+			})
+		}
+		return productFileError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "Success",
+		"data":    res,
+	})
+}
+
+// Export implements ProductFileHandler.
+// The catalog is streamed as it is read, so errors after the first batch
+// can only end the response early.
+func (h *productFileHandler) Export(c *fiber.Ctx) error {
+	var req dto.ProductExportReq
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  false,
+			"message": "Invalid query parameters",
+		})
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"status":  false,
+			"message": "Unprocessable Entity",
+			"errors":  validationErrors(err),
+		})
+	}
+	if req.Format == "" {
+		req.Format = usecases.ProductFileCSV
+	}
+	contentType := "text/csv; charset=utf-8"
+	if req.Format == usecases.ProductFileJSONL {
+		contentType = "application/x-ndjson"
+	}
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="products.`+req.Format+`"`)
+	ctx := c.Context()
+	ctx.SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := h.productFileUseCase.Export(ctx, &req, w); err != nil {
+			logger.Error(err, "[ProductFileHandler] product export failed")
+		}
+	})
+	return nil
+}
+
+func productFileError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, usecases.ErrInvalidProductFile) || errors.Is(err, usecases.ErrUnsupportedProductFormat) {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"status":  false,
+			"message": err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"status":  false,
+		"message": "Internal Server Error",
+	})
+}
+
+func NewProductFileHandler(productFileUseCase usecases.ProductFileUsecase) ProductFileHandler {
+	return &productFileHandler{
+		productFileUseCase: productFileUseCase,
+	}
+}
@@ -1 +1,478 @@
-package handlers
\ No newline at end of file
+package handlers
+
+import (
+	"errors"
+	"mini-ecommerce/internal/domain/entities"
+	"mini-ecommerce/internal/interfaces/http/dto"
+	"mini-ecommerce/internal/interfaces/http/middleware"
+	"mini-ecommerce/internal/usecases"
+	"mini-ecommerce/pkg/validation"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ProductHandler interface {
+	List(c *fiber.Ctx) error
+	GetById(c *fiber.Ctx) error
+	GetBySlug(c *fiber.Ctx) error
+	Create(c *fiber.Ctx) error
+	Update(c *fiber.Ctx) error
+	Delete(c *fiber.Ctx) error
+	AddImage(c *fiber.Ctx) error
+	SetPrimaryImage(c *fiber.Ctx) error
+	DeleteImage(c *fiber.Ctx) error
+	ReorderImages(c *fiber.Ctx) error
+	AddVariant(c *fiber.Ctx) error
+	UpdateVariant(c *fiber.Ctx) error
+	UpdateVariantStock(c *fiber.Ctx) error
+	DeleteVariant(c *fiber.Ctx) error
+}
+
+type productHandler struct {
+	productUseCase usecases.ProductUsecase
+}
+
+// List implements ProductHandler.
+func (h *productHandler) List(c *fiber.Ctx) error {
+	var req dto.ProductListReq
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  false,
+			"message": "Invalid query parameters",
+		})
+	}
+	req.Specs = specFilters(c)
+	if err := validation.Validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"status":  false,
+			"message": "Unprocessable Entity",
+			"errors":  validationErrors(err),
+		})
+	}
+	// Inactive products are listed to catalog managers only.
+	if !canSeeInactiveProducts(c) {
+		active := true
+		req.IsActive = &active
+	}
+	res, err := h.productUseCase.List(c.Context(), &req)
+	if err != nil {
+		return productError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "Success",
+		"data":    res,
+	})
+}
+
+// GetById implements ProductHandler.
+func (h *productHandler) GetById(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return invalidID(c)
+	}
+	res, err := h.productUseCase.GetById(c.Context(), id, !canSeeInactiveProducts(c))
+	if err != nil {
+		return productError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "Success",
+		"data":    res,
+	})
+}
+
+// GetBySlug implements ProductHandler.
+// A previous slug answers with a permanent redirect to the current one.
+func (h *productHandler) GetBySlug(c *fiber.Ctx) error {
+	slug := c.Params("slug")
+	res, err := h.productUseCase.GetBySlug(c.Context(), slug, !canSeeInactiveProducts(c))
+	if err != nil {
+		return productError(c, err)
+	}
+	if res.Slug != slug {
+		return slugMoved(c, "/products/slug/"+res.Slug, res)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "Success",
+		"data":    res,
+	})
+}
+
+// Create implements ProductHandler.
+func (h *productHandler) Create(c *fiber.Ctx) error {
+	var req dto.ProductReq
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  false,
+			"message": "Invalid request body",
+		})
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"status":  false,
+			"message": "Unprocessable Entity",
+			"errors":  validationErrors(err),
+		})
+	}
+	principal, _ := middleware.CurrentPrincipal(c)
+	res, err := h.productUseCase.Create(c.Context(), principal.UserID, &req)
+	if err != nil {
+		return productError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  true,
+		"message": "Product created successfully",
+		"data":    res,
+	})
+}
+
+// Update implements ProductHandler.
+func (h *productHandler) Update(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return invalidID(c)
+	}
+	var req dto.UpdateProductReq
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  false,
+			"message": "Invalid request body",
+		})
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"status":  false,
+			"message": "Unprocessable Entity",
+			"errors":  validationErrors(err),
+		})
+	}
+	req.ID = id
+	principal, _ := middleware.CurrentPrincipal(c)
+	res, err := h.productUseCase.Update(c.Context(), principal.UserID, &req)
+	if err != nil {
+		return productError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "Product updated successfully",
+		"data":    res,
+	})
+}
+
+// Delete implements ProductHandler.
+func (h *productHandler) Delete(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return invalidID(c)
+	}
+	principal, _ := middleware.CurrentPrincipal(c)
+	if err := h.productUseCase.Delete(c.Context(), principal.UserID, id); err != nil {
+		return productError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "Product deleted successfully",
+	})
+}
+
+// AddImage implements ProductHandler.
+func (h *productHandler) AddImage(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return invalidID(c)
+	}
+	var req dto.ProductImageReq
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  false,
+			"message": "Invalid request body",
+		})
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"status":  false,
+			"message": "Unprocessable Entity",
+			"errors":  validationErrors(err),
+		})
+	}
+	principal, _ := middleware.CurrentPrincipal(c)
+	res, err := h.productUseCase.AddImage(c.Context(), principal.UserID, id, &req)
+	if err != nil {
+		return productError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  true,
+		"message": "Image added successfully",
+		"data":    res,
+	})
+}
+
+// SetPrimaryImage implements ProductHandler.
+func (h *productHandler) SetPrimaryImage(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return invalidID(c)
+	}
+	imageID, err := c.ParamsInt("image_id")
+	if err != nil {
+		return invalidID(c)
+	}
+	principal, _ := middleware.CurrentPrincipal(c)
+	res, err := h.productUseCase.SetPrimaryImage(c.Context(), principal.UserID, id, imageID)
+	if err != nil {
+		return productError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "Primary image updated successfully",
+		"data":    res,
+	})
+}
+
+// DeleteImage implements ProductHandler.
+func (h *productHandler) DeleteImage(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return invalidID(c)
+	}
+	imageID, err := c.ParamsInt("image_id")
+	if err != nil {
+		return invalidID(c)
+	}
+	principal, _ := middleware.CurrentPrincipal(c)
+	if err := h.productUseCase.DeleteImage(c.Context(), principal.UserID, id, imageID); err != nil {
+		return productError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "Image deleted successfully",
+	})
+}
+
+// ReorderImages implements ProductHandler.
+func (h *productHandler) ReorderImages(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return invalidID(c)
+	}
+	var req dto.ReorderImagesReq
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  false,
+			"message": "Invalid request body",
+		})
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"status":  false,
+			"message": "Unprocessable Entity",
+			"errors":  validationErrors(err),
+		})
+	}
+	principal, _ := middleware.CurrentPrincipal(c)
+	res, err := h.productUseCase.ReorderImages(c.Context(), principal.UserID, id, &req)
+	if err != nil {
+		return productError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "Images reordered successfully",
+		"data":    res,
+	})
+}
+
+// AddVariant implements ProductHandler.
+func (h *productHandler) AddVariant(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return invalidID(c)
+	}
+	var req dto.ProductVariantReq
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  false,
+			"message": "Invalid request body",
+		})
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"status":  false,
+			"message": "Unprocessable Entity",
+			"errors":  validationErrors(err),
+		})
+	}
+	principal, _ := middleware.CurrentPrincipal(c)
+	res, err := h.productUseCase.AddVariant(c.Context(), principal.UserID, id, &req)
+	if err != nil {
+		return productError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status":  true,
+		"message": "Variant added successfully",
+		"data":    res,
+	})
+}
+
+// UpdateVariant implements ProductHandler.
+func (h *productHandler) UpdateVariant(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return invalidID(c)
+	}
+	variantID, err := c.ParamsInt("variant_id")
+	if err != nil {
+		return invalidID(c)
+	}
+	var req dto.ProductVariantReq
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  false,
+			"message": "Invalid request body",
+		})
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"status":  false,
+			"message": "Unprocessable Entity",
+			"errors":  validationErrors(err),
+		})
+	}
+	principal, _ := middleware.CurrentPrincipal(c)
+	res, err := h.productUseCase.UpdateVariant(c.Context(), principal.UserID, id, variantID, &req)
+	if err != nil {
+		return productError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "Variant updated successfully",
+		"data":    res,
+	})
+}
+
+// UpdateVariantStock implements ProductHandler.
+func (h *productHandler) UpdateVariantStock(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return invalidID(c)
+	}
+	variantID, err := c.ParamsInt("variant_id")
+	if err != nil {
+		return invalidID(c)
+	}
+	var req dto.UpdateStockReq
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  false,
+			"message": "Invalid request body",
+		})
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"status":  false,
+			"message": "Unprocessable Entity",
+			"errors":  validationErrors(err),
+		})
+	}
+	principal, _ := middleware.CurrentPrincipal(c)
+	res, err := h.productUseCase.UpdateVariantStock(c.Context(), principal.UserID, id, variantID, *req.StockQuantity)
+	if err != nil {
+		return productError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "Stock updated successfully",
+		"data":    res,
+	})
+}
+
+// DeleteVariant implements ProductHandler.
+func (h *productHandler) DeleteVariant(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return invalidID(c)
+	}
+	variantID, err := c.ParamsInt("variant_id")
+	if err != nil {
+		return invalidID(c)
+	}
+	principal, _ := middleware.CurrentPrincipal(c)
+	if err := h.productUseCase.DeleteVariant(c.Context(), principal.UserID, id, variantID); err != nil {
+		return productError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "Variant deleted successfully",
+	})
+}
+
+// specFilters collects the spec.<key>=<value> query parameters. Repeating a
+// parameter or separating values with commas selects several values.
+func specFilters(c *fiber.Ctx) map[string][]string {
+	specs := make(map[string][]string)
+	c.Context().QueryArgs().VisitAll(func(key, value []byte) {
+		name, ok := strings.CutPrefix(string(key), "spec.")
+		if !ok {
+			return
+		}
+		for _, v := range strings.Split(string(value), ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				specs[name] = append(specs[name], v)
+			}
+		}
+	})
+	return specs
+}
+
+// canSeeInactiveProducts reports whether the caller manages products;
+// everyone else only finds active products and variants.
+func canSeeInactiveProducts(c *fiber.Ctx) bool {
+	return middleware.CallerCan(c, entities.PermissionProductWrite)
+}
+
+func productError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, usecases.ErrProductNotFound),
+		errors.Is(err, usecases.ErrProductImageNotFound),
+		errors.Is(err, usecases.ErrVariantNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  false,
+			"message": err.Error(),
+		})
+	case errors.Is(err, usecases.ErrProductCategoryNotFound),
+		errors.Is(err, usecases.ErrMultiplePrimaryImages),
+		errors.Is(err, usecases.ErrInvalidPriceRange),
+		errors.Is(err, usecases.ErrInvalidSortField),
+		errors.Is(err, usecases.ErrInvalidVariantOptions),
+		errors.Is(err, usecases.ErrInvalidOptionNames),
+		errors.Is(err, usecases.ErrStockManagedByVariants),
+		errors.Is(err, usecases.ErrVariantImageOnCreate):
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"status":  false,
+			"message": err.Error(),
+		})
+	case errors.Is(err, usecases.ErrSlugTaken),
+		errors.Is(err, usecases.ErrSKUExists),
+		errors.Is(err, usecases.ErrProductInUse),
+		errors.Is(err, usecases.ErrImageOrderMismatch),
+		errors.Is(err, usecases.ErrDuplicateVariant),
+		errors.Is(err, usecases.ErrVariantInUse),
+		errors.Is(err, usecases.ErrOptionsInUse):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"status":  false,
+			"message": err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"status":  false,
+		"message": "Internal Server Error",
+	})
+}
+
+func NewProductHandler(productUseCase usecases.ProductUsecase) ProductHandler {
+	return &productHandler{
+		productUseCase: productUseCase,
+	}
+}
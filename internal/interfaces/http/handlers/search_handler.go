@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"mini-ecommerce/internal/interfaces/http/dto"
+	"mini-ecommerce/internal/usecases"
+	"mini-ecommerce/pkg/validation"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type SearchHandler interface {
+	Search(c *fiber.Ctx) error
+	Suggest(c *fiber.Ctx) error
+}
+
+type searchHandler struct {
+	searchUseCase usecases.SearchUsecase
+}
+
+// Search implements SearchHandler.
+func (h *searchHandler) Search(c *fiber.Ctx) error {
+	var req dto.ProductSearchReq
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  false,
+			"message": "Invalid query parameters",
+		})
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"status":  false,
+			"message": "Unprocessable Entity",
+			"errors":  validationErrors(err),
+		})
+	}
+	res, err := h.searchUseCase.Search(c.Context(), &req)
+	if err != nil {
+		return searchError(c)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "Success",
+		"data":    res,
+	})
+}
+
+// Suggest implements SearchHandler.
+func (h *searchHandler) Suggest(c *fiber.Ctx) error {
+	var req dto.ProductSuggestReq
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  false,
+			"message": "Invalid query parameters",
+		})
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"status":  false,
+			"message": "Unprocessable Entity",
+			"errors":  validationErrors(err),
+		})
+	}
+	res, err := h.searchUseCase.Suggest(c.Context(), &req)
+	if err != nil {
+		return searchError(c)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "Success",
+		"data":    res,
+	})
+}
+
+// searchError answers failures of the search index, which have no client
+// side cause once the query passed validation.
+func searchError(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"status":  false,
+		"message": "Internal Server Error",
+	})
+}
+
+func NewSearchHandler(searchUseCase usecases.SearchUsecase) SearchHandler {
+	return &searchHandler{
+		searchUseCase: searchUseCase,
+	}
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"errors"
+	"mini-ecommerce/internal/interfaces/http/middleware"
+	"mini-ecommerce/internal/usecases"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type SessionHandler interface {
+	List(c *fiber.Ctx) error
+	Revoke(c *fiber.Ctx) error
+	RevokeOthers(c *fiber.Ctx) error
+	RevokeAllForUser(c *fiber.Ctx) error
+}
+
+type sessionHandler struct {
+	sessionUseCase usecases.SessionUsecase
+}
+
+// List implements SessionHandler.
+func (s *sessionHandler) List(c *fiber.Ctx) error {
+	principal, _ := middleware.CurrentPrincipal(c)
+	res, err := s.sessionUseCase.List(c.Context(), principal.UserID, principal.SessionID)
+	if err != nil {
+		return sessionError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "Success",
+		"data":    res,
+	})
+}
+
+// Revoke implements SessionHandler.
+func (s *sessionHandler) Revoke(c *fiber.Ctx) error {
+	principal, _ := middleware.CurrentPrincipal(c)
+	if err := s.sessionUseCase.Revoke(c.Context(), principal.UserID, c.Params("sessionId")); err != nil {
+		return sessionError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "Session revoked",
+	})
+}
+
+// RevokeOthers implements SessionHandler.
+func (s *sessionHandler) RevokeOthers(c *fiber.Ctx) error {
+	principal, _ := middleware.CurrentPrincipal(c)
+	if err := s.sessionUseCase.RevokeOthers(c.Context(), principal.UserID, principal.SessionID); err != nil {
+		return sessionError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "Signed out of all other sessions",
+	})
+}
+
+// RevokeAllForUser implements SessionHandler.
+func (s *sessionHandler) RevokeAllForUser(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return invalidID(c)
+	}
+	if err := s.sessionUseCase.RevokeAll(c.Context(), id); err != nil {
+		return sessionError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "User signed out of all sessions",
+	})
+}
+
+func sessionError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, usecases.ErrSessionNotFound) || errors.Is(err, usecases.ErrUserNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  false,
+			"message": err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"status":  false,
+		"message": "Internal Server Error",
+	})
+}
+
+func NewSessionHandler(sessionUseCase usecases.SessionUsecase) SessionHandler {
+	return &sessionHandler{
+		sessionUseCase: sessionUseCase,
+	}
+}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"errors"
+	"mini-ecommerce/internal/interfaces/http/dto"
+	"mini-ecommerce/internal/interfaces/http/middleware"
+	"mini-ecommerce/internal/usecases"
+	"mini-ecommerce/pkg/validation"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type TwoFactorHandler interface {
+	Status(c *fiber.Ctx) error
+	Setup(c *fiber.Ctx) error
+	Enable(c *fiber.Ctx) error
+	Disable(c *fiber.Ctx) error
+	RegenerateRecoveryCodes(c *fiber.Ctx) error
+}
+
+type twoFactorHandler struct {
+	twoFactorUseCase usecases.TwoFactorUsecase
+}
+
+// Status implements TwoFactorHandler.
+func (t *twoFactorHandler) Status(c *fiber.Ctx) error {
+	principal, _ := middleware.CurrentPrincipal(c)
+	res, err := t.twoFactorUseCase.Status(c.Context(), principal.UserID, principal.Role)
+	if err != nil {
+		return twoFactorError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "Success",
+		"data":    res,
+	})
+}
+
+// Setup implements TwoFactorHandler.
+func (t *twoFactorHandler) Setup(c *fiber.Ctx) error {
+	principal, _ := middleware.CurrentPrincipal(c)
+	res, err := t.twoFactorUseCase.Setup(c.Context(), principal.UserID, principal.Email)
+	if err != nil {
+		return twoFactorError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "Scan the provisioning URI with an authenticator app and confirm with a code",
+		"data":    res,
+	})
+}
+
+// Enable implements TwoFactorHandler.
+func (t *twoFactorHandler) Enable(c *fiber.Ctx) error {
+	req, err := parseTwoFactorCode(c)
+	if req == nil {
+		return err
+	}
+	principal, _ := middleware.CurrentPrincipal(c)
+	res, err := t.twoFactorUseCase.Enable(c.Context(), principal.UserID, req)
+	if err != nil {
+		return twoFactorError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "Two-factor authentication enabled. Store the recovery codes safely, they are only shown once",
+		"data":    res,
+	})
+}
+
+// Disable implements TwoFactorHandler.
+func (t *twoFactorHandler) Disable(c *fiber.Ctx) error {
+	req, err := parseTwoFactorCode(c)
+	if req == nil {
+		return err
+	}
+	principal, _ := middleware.CurrentPrincipal(c)
+	if err := t.twoFactorUseCase.Disable(c.Context(), principal.UserID, req); err != nil {
+		return twoFactorError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "Two-factor authentication disabled",
+	})
+}
+
+// RegenerateRecoveryCodes implements TwoFactorHandler.
+func (t *twoFactorHandler) RegenerateRecoveryCodes(c *fiber.Ctx) error {
+	req, err := parseTwoFactorCode(c)
+	if req == nil {
+		return err
+	}
+	principal, _ := middleware.CurrentPrincipal(c)
+	res, err := t.twoFactorUseCase.RegenerateRecoveryCodes(c.Context(), principal.UserID, req)
+	if err != nil {
+		return twoFactorError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "Recovery codes regenerated",
+		"data":    res,
+	})
+}
+
+// parseTwoFactorCode parses and validates the request body. When the request
+// is nil the error response has already been written and err must be returned.
+func parseTwoFactorCode(c *fiber.Ctx) (*dto.TwoFactorCodeReq, error) {
+	var req dto.TwoFactorCodeReq
+	if err := c.BodyParser(&req); err != nil {
+		return nil, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  false,
+			"message": "Invalid request body",
+		})
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		return nil, c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"status":  false,
+			"message": "Unprocessable Entity",
+			"errors":  validationErrors(err),
+		})
+	}
+	return &req, nil
+}
+
+func twoFactorError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, usecases.ErrInvalidTwoFactorCode):
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"status":  false,
+			"message": err.Error(),
+		})
+	case errors.Is(err, usecases.ErrTwoFactorAlreadyEnabled),
+		errors.Is(err, usecases.ErrTwoFactorNotEnabled),
+		errors.Is(err, usecases.ErrTwoFactorNotSetUp):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"status":  false,
+			"message": err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"status":  false,
+		"message": "Internal Server Error",
+	})
+}
+
+func NewTwoFactorHandler(twoFactorUseCase usecases.TwoFactorUsecase) TwoFactorHandler {
+	return &twoFactorHandler{
+		twoFactorUseCase: twoFactorUseCase,
+	}
+}
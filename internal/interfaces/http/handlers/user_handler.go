@@ -1 +1,229 @@
-package handlers
\ No newline at end of file
+package handlers
+
+import (
+	"errors"
+	"mini-ecommerce/internal/interfaces/http/dto"
+	"mini-ecommerce/internal/interfaces/http/middleware"
+	"mini-ecommerce/internal/usecases"
+	"mini-ecommerce/pkg/validation"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type UserHandler interface {
+	GetProfile(c *fiber.Ctx) error
+	UpdateProfile(c *fiber.Ctx) error
+	List(c *fiber.Ctx) error
+	GetById(c *fiber.Ctx) error
+	UpdateRole(c *fiber.Ctx) error
+	Deactivate(c *fiber.Ctx) error
+	Reactivate(c *fiber.Ctx) error
+	Delete(c *fiber.Ctx) error
+}
+
+type userHandler struct {
+	userUseCase usecases.UserUsecase
+}
+
+// GetProfile implements UserHandler.
+func (u *userHandler) GetProfile(c *fiber.Ctx) error {
+	principal, _ := middleware.CurrentPrincipal(c)
+	res, err := u.userUseCase.GetProfile(c.Context(), principal.UserID)
+	if err != nil {
+		return userError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "Success",
+		"data":    res,
+	})
+}
+
+// UpdateProfile implements UserHandler.
+func (u *userHandler) UpdateProfile(c *fiber.Ctx) error {
+	var req dto.UpdateProfileReq
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  false,
+			"message": "Invalid request body",
+		})
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"status":  false,
+			"message": "Unprocessable Entity",
+			"errors":  validationErrors(err),
+		})
+	}
+	principal, _ := middleware.CurrentPrincipal(c)
+	req.UserID = principal.UserID
+	req.SessionID = principal.SessionID
+	res, err := u.userUseCase.Update(c.Context(), &req)
+	if err != nil {
+		return userError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "Profile updated successfully",
+		"data":    res,
+	})
+}
+
+// List implements UserHandler.
+func (u *userHandler) List(c *fiber.Ctx) error {
+	var req dto.UserListReq
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  false,
+			"message": "Invalid query parameters",
+		})
+	}
+	res, err := u.userUseCase.List(c.Context(), &req)
+	if err != nil {
+		return userError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "Success",
+		"data":    res,
+	})
+}
+
+// GetById implements UserHandler.
+func (u *userHandler) GetById(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return invalidID(c)
+	}
+	res, err := u.userUseCase.GetById(c.Context(), id)
+	if err != nil {
+		return userError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "Success",
+		"data":    res,
+	})
+}
+
+// UpdateRole implements UserHandler.
+func (u *userHandler) UpdateRole(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return invalidID(c)
+	}
+	var req dto.UpdateUserRoleReq
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  false,
+			"message": "Invalid request body",
+		})
+	}
+	if err := validation.Validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"status":  false,
+			"message": "Unprocessable Entity",
+			"errors":  validationErrors(err),
+		})
+	}
+	principal, _ := middleware.CurrentPrincipal(c)
+	res, err := u.userUseCase.UpdateRole(c.Context(), principal.UserID, id, &req)
+	if err != nil {
+		return userError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "User role updated successfully",
+		"data":    res,
+	})
+}
+
+// Deactivate implements UserHandler.
+func (u *userHandler) Deactivate(c *fiber.Ctx) error {
+	return u.setActive(c, false, "User deactivated successfully")
+}
+
+// Reactivate implements UserHandler.
+func (u *userHandler) Reactivate(c *fiber.Ctx) error {
+	return u.setActive(c, true, "User reactivated successfully")
+}
+
+// Delete implements UserHandler.
+func (u *userHandler) Delete(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return invalidID(c)
+	}
+	principal, _ := middleware.CurrentPrincipal(c)
+	if err := u.userUseCase.Delete(c.Context(), principal.UserID, id); err != nil {
+		return userError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": "User deleted successfully",
+	})
+}
+
+func (u *userHandler) setActive(c *fiber.Ctx, active bool, message string) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return invalidID(c)
+	}
+	principal, _ := middleware.CurrentPrincipal(c)
+	res, err := u.userUseCase.SetActive(c.Context(), principal.UserID, id, active)
+	if err != nil {
+		return userError(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":  true,
+		"message": message,
+		"data":    res,
+	})
+}
+
+func userError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, usecases.ErrUserNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"status":  false,
+			"message": err.Error(),
+		})
+	case errors.Is(err, usecases.ErrInvalidRole):
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"status":  false,
+			"message": err.Error(),
+		})
+	case errors.Is(err, usecases.ErrCannotModifySelf):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status":  false,
+			"message": err.Error(),
+		})
+	case errors.Is(err, usecases.ErrInvalidCurrentPassword):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"status":  false,
+			"message": err.Error(),
+		})
+	case errors.Is(err, usecases.ErrEmailExists):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"status":  false,
+			"message": err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"status":  false,
+		"message": "Internal Server Error",
+	})
+}
+
+func invalidID(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		"status":  false,
+		"message": "Invalid id",
+	})
+}
+
+func NewUserHandler(userUseCase usecases.UserUsecase) UserHandler {
+	return &userHandler{
+		userUseCase: userUseCase,
+	}
+}
@@ -1 +1,65 @@
-package middleware
\ No newline at end of file
+package middleware
+
+import (
+	"mini-ecommerce/internal/domain/entities"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequirePermission allows the request only when the caller's role is granted
+// every listed permission, and for API keys only when the key's scopes cover it
+// too. It must run after the Auth middleware. Callers that still have to enroll
+// in two-factor authentication are refused.
+func RequirePermission(permissions ...entities.Permission) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		principal, ok := CurrentPrincipal(c)
+		if !ok {
+			return unauthorized(c, "Unauthorized")
+		}
+		if principal.TwoFactorPending {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"status":  false,
+				"message": "Two-factor authentication must be enabled for this account",
+			})
+		}
+		for _, permission := range permissions {
+			if !principal.Can(permission) {
+				return forbidden(c)
+			}
+		}
+		return c.Next()
+	}
+}
+
+// Can reports whether the caller's role grants the permission and, for API
+// keys, whether the key's scopes cover it.
+func (p *Principal) Can(permission entities.Permission) bool {
+	if !entities.HasPermission(p.Role, permission) {
+		return false
+	}
+	return p.APIKeyID == 0 || hasScope(p.Scopes, permission)
+}
+
+// CallerCan reports whether the request was authenticated by a caller holding
+// the permission. Callers that still have to enroll in two-factor
+// authentication hold none, as with RequirePermission.
+func CallerCan(c *fiber.Ctx, permission entities.Permission) bool {
+	principal, ok := CurrentPrincipal(c)
+	return ok && !principal.TwoFactorPending && principal.Can(permission)
+}
+
+func hasScope(scopes []entities.Permission, permission entities.Permission) bool {
+	for _, scope := range scopes {
+		if scope == entities.PermissionAll || scope == permission {
+			return true
+		}
+	}
+	return false
+}
+
+func forbidden(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+		"status":  false,
+		"message": "Forbidden",
+	})
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"mini-ecommerce/internal/domain/entities"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestRequirePermission(t *testing.T) {
+	tests := []struct {
+		name       string
+		principal  *Principal
+		permission entities.Permission
+		want       int
+	}{
+		{"anonymous", nil, entities.PermissionProductWrite, fiber.StatusUnauthorized},
+		{"admin session", &Principal{UserID: 1, Role: entities.RoleAdmin}, entities.PermissionProductWrite, fiber.StatusOK},
+		{"customer session", &Principal{UserID: 2, Role: entities.RoleCustomer}, entities.PermissionProductWrite, fiber.StatusForbidden},
+		{"warehouse session", &Principal{UserID: 3, Role: entities.RoleWarehouse}, entities.PermissionProductStockWrite, fiber.StatusOK},
+		{"two-factor pending", &Principal{UserID: 1, Role: entities.RoleAdmin, TwoFactorPending: true}, entities.PermissionProductWrite, fiber.StatusForbidden},
+		{
+			"api key with scope",
+			&Principal{UserID: 1, Role: entities.RoleAdmin, APIKeyID: 7, Scopes: []entities.Permission{entities.PermissionProductWrite}},
+			entities.PermissionProductWrite, fiber.StatusOK,
+		},
+		{
+			"api key without scope",
+			&Principal{UserID: 1, Role: entities.RoleAdmin, APIKeyID: 7, Scopes: []entities.Permission{entities.PermissionCategoryWrite}},
+			entities.PermissionProductWrite, fiber.StatusForbidden,
+		},
+		{
+			"api key with empty scopes",
+			&Principal{UserID: 1, Role: entities.RoleAdmin, APIKeyID: 7, Scopes: []entities.Permission{}},
+			entities.PermissionProductWrite, fiber.StatusForbidden,
+		},
+		{
+			"api key scope beyond role",
+			&Principal{UserID: 2, Role: entities.RoleCustomer, APIKeyID: 8, Scopes: []entities.Permission{entities.PermissionAll}},
+			entities.PermissionProductWrite, fiber.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Get("/", func(c *fiber.Ctx) error {
+				if tt.principal != nil {
+					c.Locals(principalKey{}, tt.principal)
+				}
+				return c.Next()
+			}, RequirePermission(tt.permission), func(c *fiber.Ctx) error {
+				return c.SendStatus(fiber.StatusOK)
+			})
+			res, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.StatusCode != tt.want {
+				t.Errorf("status = %d, want %d", res.StatusCode, tt.want)
+			}
+		})
+	}
+}
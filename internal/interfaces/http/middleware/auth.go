@@ -1 +1,215 @@
-package middleware
\ No newline at end of file
+package middleware
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"mini-ecommerce/internal/domain/entities"
+	"mini-ecommerce/internal/domain/repositories"
+	"mini-ecommerce/internal/infrastructure/auth"
+	"mini-ecommerce/pkg/logger"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// HeaderAPIKey carries a personal API key as an alternative to a Bearer token
+const HeaderAPIKey = "X-API-Key"
+
+// touchInterval limits how often usage timestamps are written for a busy
+// API key or session
+const touchInterval = time.Minute
+
+// Principal is the authenticated caller attached to the request by the Auth middleware
+type Principal struct {
+	UserID    int
+	Email     string
+	Name      string
+	Role      string
+	TokenID   string
+	SessionID string
+	ExpiresAt time.Time
+	// TwoFactorPending is set when the caller's role requires two-factor
+	// authentication but the account has not enrolled yet
+	TwoFactorPending bool
+	// APIKeyID is set when the caller authenticated with an API key; Scopes
+	// then limits the permissions of the owner's role
+	APIKeyID int
+	Scopes   []entities.Permission
+}
+
+type principalKey struct{}
+
+// Auth validates the Bearer access token, rejects revoked tokens and tokens of
+// ended sessions, and loads the caller from the user repository. Inactive users are rejected even if their
+// token is still valid. An X-API-Key header is accepted instead of a token.
+// Callers whose role is listed in twoFactorRoles but who have not enrolled are
+// marked TwoFactorPending.
+func Auth(jwtService auth.JWTService, revocationStore auth.TokenRevocationStore, userRepo repositories.UserRepository, apiKeyRepo repositories.APIKeyRepository, sessionRepo repositories.SessionRepository, twoFactorRoles []string) fiber.Handler {
+	requiresTwoFactor := make(map[string]bool, len(twoFactorRoles))
+	for _, role := range twoFactorRoles {
+		requiresTwoFactor[role] = true
+	}
+	return func(c *fiber.Ctx) error {
+		if rawKey := strings.TrimSpace(c.Get(HeaderAPIKey)); rawKey != "" {
+			key, err := apiKeyRepo.GetByHash(c.Context(), auth.HashToken(rawKey))
+			if err != nil {
+				if errors.Is(err, repositories.ErrAPIKeyNotFound) {
+					return unauthorized(c, "Invalid API key")
+				}
+				logger.Error(err, "[ErrAuthMiddleware-3] failed to load api key")
+				return internalError(c)
+			}
+			now := time.Now()
+			if key.RevokedAt != nil {
+				return unauthorized(c, "API key has been revoked")
+			}
+			if key.ExpiresAt != nil && now.After(*key.ExpiresAt) {
+				return unauthorized(c, "API key has expired")
+			}
+			user, err := userRepo.GetById(c.Context(), key.UserID)
+			if err != nil {
+				if err.Error() == "user not found" {
+					return unauthorized(c, "Invalid API key")
+				}
+				logger.Error(err, "[ErrAuthMiddleware-2] failed to load user")
+				return internalError(c)
+			}
+			if !user.IsActive {
+				return inactive(c)
+			}
+			if err := apiKeyRepo.TouchLastUsed(c.Context(), key.ID, now, touchInterval); err != nil {
+				logger.Error(err, "[ErrAuthMiddleware-4] failed to record api key usage")
+			}
+			c.Locals(principalKey{}, &Principal{
+				UserID:           user.ID,
+				Email:            user.Email,
+				Name:             user.Name,
+				Role:             user.Role,
+				TwoFactorPending: requiresTwoFactor[user.Role] && !user.TwoFactorEnabled,
+				APIKeyID:         key.ID,
+				Scopes:           key.Scopes,
+			})
+			return c.Next()
+		}
+		header := c.Get(fiber.HeaderAuthorization)
+		tokenString, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok || strings.TrimSpace(tokenString) == "" {
+			return unauthorized(c, "Missing or malformed bearer token")
+		}
+		claims, err := jwtService.ValidateToken(strings.TrimSpace(tokenString))
+		if err != nil {
+			if errors.Is(err, auth.ErrExpiredToken) {
+				return unauthorized(c, "Token has expired")
+			}
+			return unauthorized(c, "Invalid token")
+		}
+		revoked, err := revocationStore.IsRevoked(c.Context(), claims.ID)
+		if err != nil {
+			logger.Error(err, "[ErrAuthMiddleware-1] failed to check token revocation")
+			return internalError(c)
+		}
+		if revoked {
+			return unauthorized(c, "Token has been revoked")
+		}
+		session, err := sessionRepo.GetById(c.Context(), claims.SessionID)
+		if err != nil {
+			if errors.Is(err, repositories.ErrSessionNotFound) {
+				return unauthorized(c, "Invalid token")
+			}
+			logger.Error(err, "[ErrAuthMiddleware-5] failed to load session")
+			return internalError(c)
+		}
+		now := time.Now()
+		if session.UserID != claims.UserID || session.RevokedAt != nil || now.After(session.ExpiresAt) {
+			return unauthorized(c, "Session has ended")
+		}
+		user, err := userRepo.GetById(c.Context(), claims.UserID)
+		if err != nil {
+			if err.Error() == "user not found" {
+				return unauthorized(c, "Invalid token")
+			}
+			logger.Error(err, "[ErrAuthMiddleware-2] failed to load user")
+			return internalError(c)
+		}
+		if !user.IsActive {
+			return inactive(c)
+		}
+		// JWT timestamps have second precision, so compare at that granularity.
+		if user.PasswordChangedAt != nil && claims.IssuedAt != nil &&
+			claims.IssuedAt.Time.Before(user.PasswordChangedAt.Truncate(time.Second)) {
+			return unauthorized(c, "Token has been revoked")
+		}
+		if err := sessionRepo.TouchLastActivity(c.Context(), session.ID, now, touchInterval); err != nil {
+			logger.Error(err, "[ErrAuthMiddleware-6] failed to record session activity")
+		}
+		c.Locals(principalKey{}, &Principal{
+			UserID:           user.ID,
+			Email:            user.Email,
+			Name:             user.Name,
+			Role:             user.Role,
+			TokenID:          claims.ID,
+			SessionID:        session.ID,
+			ExpiresAt:        claims.ExpiresAt.Time,
+			TwoFactorPending: requiresTwoFactor[user.Role] && !user.TwoFactorEnabled,
+		})
+		return c.Next()
+	}
+}
+
+// SessionOnly rejects callers authenticated with an API key. It guards
+// account management endpoints that must not be reachable by integrations.
+func SessionOnly() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		principal, ok := CurrentPrincipal(c)
+		if !ok {
+			return unauthorized(c, "Unauthorized")
+		}
+		if principal.APIKeyID != 0 {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"status":  false,
+				"message": "This endpoint cannot be used with an API key",
+			})
+		}
+		return c.Next()
+	}
+}
+
+// OptionalAuth runs authMiddleware only for requests that carry credentials,
+// so public endpoints can tailor their response to privileged callers.
+// Invalid credentials are still rejected.
+func OptionalAuth(authMiddleware fiber.Handler) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if c.Get(HeaderAPIKey) == "" && c.Get(fiber.HeaderAuthorization) == "" {
+			return c.Next()
+		}
+		return authMiddleware(c)
+	}
+}
+
+// CurrentPrincipal returns the caller stored by the Auth middleware.
+func CurrentPrincipal(c *fiber.Ctx) (*Principal, bool) {
+	principal, ok := c.Locals(principalKey{}).(*Principal)
+	return principal, ok
+}
+
+func unauthorized(c *fiber.Ctx, message string) error {
+	return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+		"status":  false,
+		"message": message,
+	})
+}
+
+func inactive(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+		"status":  false,
+		"message": "User account is inactive",
+	})
+}
+
+func internalError(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"status":  false,
+		"message": "Internal Server Error",
+	})
+}
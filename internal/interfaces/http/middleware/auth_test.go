@@ -0,0 +1,146 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"mini-ecommerce/internal/domain/entities"
+	"mini-ecommerce/internal/domain/repositories"
+	"mini-ecommerce/internal/infrastructure/auth"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// fakeAPIKeyRepository finds keys by the hash of the raw key.
+type fakeAPIKeyRepository struct {
+	repositories.APIKeyRepository
+	keys map[string]*entities.APIKey
+}
+
+func (f *fakeAPIKeyRepository) GetByHash(ctx context.Context, keyHash string) (*entities.APIKey, error) {
+	for rawKey, key := range f.keys {
+		if auth.HashToken(rawKey) == keyHash {
+			return key, nil
+		}
+	}
+	return nil, repositories.ErrAPIKeyNotFound
+}
+
+func (f *fakeAPIKeyRepository) TouchLastUsed(ctx context.Context, id int, usedAt time.Time, threshold time.Duration) error {
+	return nil
+}
+
+// fakeUserRepository keeps users in memory by ID.
+type fakeUserRepository struct {
+	repositories.UserRepository
+	users map[int]*entities.User
+}
+
+func (f *fakeUserRepository) GetById(ctx context.Context, id int) (*entities.User, error) {
+	user, ok := f.users[id]
+	if !ok {
+		return nil, errors.New("user not found")
+	}
+	return user, nil
+}
+
+func TestAuthAPIKey(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+	keys := &fakeAPIKeyRepository{keys: map[string]*entities.APIKey{
+		"mek_valid":   {ID: 1, UserID: 1, Scopes: []entities.Permission{entities.PermissionUserRead}, ExpiresAt: &future},
+		"mek_revoked": {ID: 2, UserID: 1, RevokedAt: &past},
+		"mek_expired": {ID: 3, UserID: 1, ExpiresAt: &past},
+	}}
+	users := &fakeUserRepository{users: map[int]*entities.User{
+		1: {ID: 1, Role: entities.RoleSupport, IsActive: true},
+	}}
+	authMiddleware := Auth(nil, nil, users, keys, nil, nil)
+
+	tests := []struct {
+		key         string
+		wantStatus  int
+		wantMessage string
+	}{
+		{key: "mek_valid", wantStatus: fiber.StatusOK},
+		{key: "mek_revoked", wantStatus: fiber.StatusUnauthorized, wantMessage: "API key has been revoked"},
+		{key: "mek_expired", wantStatus: fiber.StatusUnauthorized, wantMessage: "API key has expired"},
+		{key: "mek_unknown", wantStatus: fiber.StatusUnauthorized, wantMessage: "Invalid API key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			app := fiber.New()
+			app.Get("/", authMiddleware, func(c *fiber.Ctx) error {
+				principal, _ := CurrentPrincipal(c)
+				if principal.APIKeyID != 1 || !CallerCan(c, entities.PermissionUserRead) {
+					t.Errorf("principal = %+v, want key 1 with user:read", principal)
+				}
+				return c.SendStatus(fiber.StatusOK)
+			})
+			req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+			req.Header.Set(HeaderAPIKey, tt.key)
+			res, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.StatusCode != tt.wantStatus {
+				t.Fatalf("status %d, want %d", res.StatusCode, tt.wantStatus)
+			}
+			if tt.wantMessage == "" {
+				return
+			}
+			var body struct {
+				Message string `json:"message"`
+			}
+			if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			if body.Message != tt.wantMessage {
+				t.Errorf("message %q, want %q", body.Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestOptionalAuth(t *testing.T) {
+	// stubAuth stands in for Auth: it authenticates every request as an admin.
+	stubAuth := func(c *fiber.Ctx) error {
+		c.Locals(principalKey{}, &Principal{UserID: 1, Role: entities.RoleAdmin})
+		return c.Next()
+	}
+	tests := []struct {
+		name    string
+		header  string
+		value   string
+		wantCan bool
+	}{
+		{name: "anonymous"},
+		{name: "bearer token", header: fiber.HeaderAuthorization, value: "Bearer token", wantCan: true},
+		{name: "api key", header: HeaderAPIKey, value: "key", wantCan: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			var can bool
+			app.Get("/", OptionalAuth(stubAuth), func(c *fiber.Ctx) error {
+				can = CallerCan(c, entities.PermissionCategoryWrite)
+				return c.SendStatus(fiber.StatusOK)
+			})
+			req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			res, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.StatusCode != fiber.StatusOK || can != tt.wantCan {
+				t.Errorf("status %d, CallerCan %v, want 200 and %v", res.StatusCode, can, tt.wantCan)
+			}
+		})
+	}
+}
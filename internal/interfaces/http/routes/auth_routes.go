@@ -2,12 +2,29 @@ package routes
 
 import (
 	"mini-ecommerce/internal/interfaces/http/handlers"
+	"mini-ecommerce/internal/interfaces/http/middleware"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-func SetupAuthRoutes(app *fiber.App, authHandler handlers.AuthHandler) {
+func SetupAuthRoutes(app *fiber.App, authHandler handlers.AuthHandler, twoFactorHandler handlers.TwoFactorHandler, authMiddleware, rateLimit fiber.Handler) {
+	// rateLimit guards only the endpoints that take credentials or send mail.
 	auth := app.Group("/auth")
-	auth.Post("/register", authHandler.Register)
-	auth.Post("/login", authHandler.Login)
+	auth.Post("/register", rateLimit, authHandler.Register)
+	auth.Post("/login", rateLimit, authHandler.Login)
+	auth.Post("/login/2fa", rateLimit, authHandler.LoginTwoFactor)
+	auth.Post("/refresh", authHandler.Refresh)
+	auth.Post("/logout", authMiddleware, middleware.SessionOnly(), authHandler.Logout)
+	auth.Get("/verify-email", authHandler.VerifyEmail)
+	auth.Post("/resend-verification", rateLimit, authHandler.ResendVerification)
+	auth.Post("/forgot-password", rateLimit, authHandler.ForgotPassword)
+	auth.Get("/reset-password", authHandler.ResetPasswordForm)
+	auth.Post("/reset-password", rateLimit, authHandler.ResetPassword)
+
+	twoFactor := auth.Group("/2fa", authMiddleware, middleware.SessionOnly())
+	twoFactor.Get("/", twoFactorHandler.Status)
+	twoFactor.Post("/setup", twoFactorHandler.Setup)
+	twoFactor.Post("/enable", twoFactorHandler.Enable)
+	twoFactor.Post("/disable", twoFactorHandler.Disable)
+	twoFactor.Post("/recovery-codes", twoFactorHandler.RegenerateRecoveryCodes)
 }
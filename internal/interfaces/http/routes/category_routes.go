@@ -1 +1,25 @@
-package routes
\ No newline at end of file
+package routes
+
+import (
+	"mini-ecommerce/internal/domain/entities"
+	"mini-ecommerce/internal/interfaces/http/handlers"
+	"mini-ecommerce/internal/interfaces/http/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetupCategoryRoutes(app *fiber.App, categoryHandler handlers.CategoryHandler, authMiddleware fiber.Handler) {
+	// Inactive categories are only found by callers with category:write.
+	optionalAuth := middleware.OptionalAuth(authMiddleware)
+	categories := app.Group("/categories")
+	categories.Get("/", optionalAuth, categoryHandler.List)
+	categories.Get("/tree", optionalAuth, categoryHandler.Tree)
+	categories.Get("/slug/:slug", optionalAuth, categoryHandler.GetBySlug)
+	categories.Get("/:id", optionalAuth, categoryHandler.GetById)
+	categories.Get("/:id/subtree", optionalAuth, categoryHandler.Subtree)
+	categories.Get("/:id/breadcrumbs", optionalAuth, categoryHandler.Breadcrumbs)
+	categories.Post("/", authMiddleware, middleware.RequirePermission(entities.PermissionCategoryWrite), categoryHandler.Create)
+	categories.Put("/reorder", authMiddleware, middleware.RequirePermission(entities.PermissionCategoryWrite), categoryHandler.Reorder)
+	categories.Put("/:id", authMiddleware, middleware.RequirePermission(entities.PermissionCategoryWrite), categoryHandler.Update)
+	categories.Delete("/:id", authMiddleware, middleware.RequirePermission(entities.PermissionCategoryWrite), categoryHandler.Delete)
+}
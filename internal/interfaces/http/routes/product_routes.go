@@ -1 +1,33 @@
-package routes
\ No newline at end of file
+package routes
+
+import (
+	"mini-ecommerce/internal/domain/entities"
+	"mini-ecommerce/internal/interfaces/http/handlers"
+	"mini-ecommerce/internal/interfaces/http/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetupProductRoutes(app *fiber.App, productHandler handlers.ProductHandler, searchHandler handlers.SearchHandler, productFileHandler handlers.ProductFileHandler, authMiddleware fiber.Handler) {
+	// Inactive products and variants are only found by callers with product:write.
+	optionalAuth := middleware.OptionalAuth(authMiddleware)
+	products := app.Group("/products")
+	products.Get("/", optionalAuth, productHandler.List)
+	products.Get("/search", searchHandler.Search)
+	products.Get("/suggest", searchHandler.Suggest)
+	products.Get("/slug/:slug", optionalAuth, productHandler.GetBySlug)
+	products.Get("/export", authMiddleware, middleware.RequirePermission(entities.PermissionProductWrite), productFileHandler.Export)
+	products.Post("/import", authMiddleware, middleware.RequirePermission(entities.PermissionProductWrite), productFileHandler.Import)
+	products.Get("/:id", optionalAuth, productHandler.GetById)
+	products.Post("/", authMiddleware, middleware.RequirePermission(entities.PermissionProductWrite), productHandler.Create)
+	products.Put("/:id", authMiddleware, middleware.RequirePermission(entities.PermissionProductWrite), productHandler.Update)
+	products.Delete("/:id", authMiddleware, middleware.RequirePermission(entities.PermissionProductWrite), productHandler.Delete)
+	products.Post("/:id/images", authMiddleware, middleware.RequirePermission(entities.PermissionProductWrite), productHandler.AddImage)
+	products.Put("/:id/images/reorder", authMiddleware, middleware.RequirePermission(entities.PermissionProductWrite), productHandler.ReorderImages)
+	products.Put("/:id/images/:image_id/primary", authMiddleware, middleware.RequirePermission(entities.PermissionProductWrite), productHandler.SetPrimaryImage)
+	products.Delete("/:id/images/:image_id", authMiddleware, middleware.RequirePermission(entities.PermissionProductWrite), productHandler.DeleteImage)
+	products.Post("/:id/variants", authMiddleware, middleware.RequirePermission(entities.PermissionProductWrite), productHandler.AddVariant)
+	products.Put("/:id/variants/:variant_id", authMiddleware, middleware.RequirePermission(entities.PermissionProductWrite), productHandler.UpdateVariant)
+	products.Put("/:id/variants/:variant_id/stock", authMiddleware, middleware.RequirePermission(entities.PermissionProductStockWrite), productHandler.UpdateVariantStock)
+	products.Delete("/:id/variants/:variant_id", authMiddleware, middleware.RequirePermission(entities.PermissionProductWrite), productHandler.DeleteVariant)
+}
@@ -1,17 +1,118 @@
 package routes
 
 import (
+	"mini-ecommerce/config"
+	"mini-ecommerce/internal/infrastructure/audit"
+	"mini-ecommerce/internal/infrastructure/auth"
 	"mini-ecommerce/internal/infrastructure/database/repositories"
+	"mini-ecommerce/internal/infrastructure/mail"
+	"mini-ecommerce/internal/infrastructure/search"
 	"mini-ecommerce/internal/interfaces/http/handlers"
+	"mini-ecommerce/internal/interfaces/http/middleware"
 	"mini-ecommerce/internal/usecases"
+	"mini-ecommerce/pkg/logger"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
 )
 
-func SetupRoutes(app *fiber.App, db *gorm.DB) {
+// SetupRoutes wires repositories, usecases and handlers. rdb may be nil when Redis is disabled.
+func SetupRoutes(app *fiber.App, db *gorm.DB, rdb *redis.Client, cfg *config.Config) {
+	rateWindow := time.Duration(cfg.Rate.Minutes) * time.Minute
+	app.Use(middleware.RateLimit(cfg.Rate.RequestPerMinute*cfg.Rate.Minutes, rateWindow))
+
+	jwtService := auth.NewJWTService(cfg.JWT.SecretKey, cfg.JWT.Expire)
+	passwordHasher, err := auth.NewPasswordHasher(cfg.Password.HashAlgorithm, auth.Argon2Params{
+		Memory:      uint32(cfg.Password.Argon2Memory),
+		Iterations:  uint32(cfg.Password.Argon2Iterations),
+		Parallelism: uint8(cfg.Password.Argon2Parallelism),
+		SaltLength:  auth.DefaultArgon2Params.SaltLength,
+		KeyLength:   auth.DefaultArgon2Params.KeyLength,
+	}, cfg.Password.BcryptCost)
+	if err != nil {
+		logger.Fatal(err, "failed to initialize password hasher")
+	}
+	revocationStore := newRevocationStore(rdb, cfg)
+
 	userRepo := repositories.NewUserRepositoryImpl(db)
-	authUseCase := usecases.NewAuthUsecase(userRepo)
+	apiKeyRepo := repositories.NewAPIKeyRepositoryImpl(db)
+	sessionRepo := repositories.NewSessionRepositoryImpl(db)
+	authMiddleware := middleware.Auth(jwtService, revocationStore, userRepo, apiKeyRepo, sessionRepo, cfg.Auth.TOTPRequiredRoles)
+
+	refreshTokenRepo := repositories.NewRefreshTokenRepositoryImpl(db)
+	resetRepo := repositories.NewPasswordResetRepositoryImpl(db)
+	twoFactorRepo := repositories.NewTwoFactorRepositoryImpl(db)
+	totpCipher, err := auth.NewSecretCipher(cfg.Auth.TOTPEncryptionKey)
+	if err != nil {
+		logger.Fatal(err, "failed to initialize TOTP secret cipher")
+	}
+	twoFactorUseCase := usecases.NewTwoFactorUseCase(twoFactorRepo, totpCipher, cfg.Auth.TOTPIssuer, cfg.Auth.TOTPRequiredRoles)
+	auditor := audit.NewLogRecorder()
+	authUseCase := usecases.NewAuthUsecase(userRepo, refreshTokenRepo, sessionRepo, resetRepo, twoFactorUseCase, jwtService, passwordHasher, revocationStore, newLoginThrottler(rdb, cfg), auditor, newMailer(cfg), usecases.AuthSettings{
+		RefreshExpire:            cfg.JWT.RefreshExpire,
+		RequireEmailVerification: cfg.Auth.RequireEmailVerification,
+		EmailVerificationExpire:  cfg.Auth.EmailVerificationExpire,
+		PasswordResetExpire:      cfg.Auth.PasswordResetExpire,
+		PasswordResetURL:         cfg.Auth.PasswordResetURL,
+		BaseURL:                  cfg.App.BaseURL,
+	})
 	authHandler := handlers.NewAuthHandler(authUseCase)
-	SetupAuthRoutes(app, authHandler)
+	twoFactorHandler := handlers.NewTwoFactorHandler(twoFactorUseCase)
+	SetupAuthRoutes(app, authHandler, twoFactorHandler, authMiddleware, middleware.RateLimit(cfg.Rate.AuthRequestPerMinute, time.Minute))
+
+	addressRepo := repositories.NewAddressRepositoryImpl(db)
+	userUseCase := usecases.NewUserUseCase(userRepo, sessionRepo, addressRepo, passwordHasher, authUseCase)
+	userHandler := handlers.NewUserHandler(userUseCase)
+	apiKeyHandler := handlers.NewAPIKeyHandler(usecases.NewAPIKeyUseCase(apiKeyRepo, userRepo))
+	sessionHandler := handlers.NewSessionHandler(usecases.NewSessionUseCase(sessionRepo, userRepo))
+	addressHandler := handlers.NewAddressHandler(usecases.NewAddressUseCase(addressRepo))
+	privacyHandler := handlers.NewPrivacyHandler(usecases.NewPrivacyUseCase(userRepo, addressRepo, repositories.NewPersonalDataRepositoryImpl(db), twoFactorUseCase, passwordHasher, auditor))
+	SetupUserRoutes(app, userHandler, apiKeyHandler, sessionHandler, addressHandler, privacyHandler, authMiddleware)
+
+	categoryRepo := repositories.NewCategoryRepositoryImpl(db)
+	categoryHandler := handlers.NewCategoryHandler(usecases.NewCategoryUseCase(categoryRepo))
+	SetupCategoryRoutes(app, categoryHandler, authMiddleware)
+
+	productRepo := repositories.NewProductRepositoryImpl(db)
+	productVariantRepo := repositories.NewProductVariantRepositoryImpl(db)
+	productUseCase := usecases.NewProductUseCase(productRepo, repositories.NewProductImageRepositoryImpl(db), productVariantRepo, categoryRepo)
+	productHandler := handlers.NewProductHandler(productUseCase)
+	searchHandler := handlers.NewSearchHandler(usecases.NewSearchUseCase(search.NewPostgresIndex(db), productRepo))
+	productFileHandler := handlers.NewProductFileHandler(usecases.NewProductFileUseCase(productUseCase, productRepo, categoryRepo))
+	SetupProductRoutes(app, productHandler, searchHandler, productFileHandler, authMiddleware)
+}
+
+func newMailer(cfg *config.Config) mail.Mailer {
+	if cfg.Mail.Driver == "smtp" {
+		return mail.NewSMTPMailer(cfg.Mail.Host, cfg.Mail.Port, cfg.Mail.Username, cfg.Mail.Password, cfg.Mail.From)
+	}
+	return mail.NewLogMailer(cfg.Mail.From, cfg.Mail.OutputDir)
+}
+
+func newLoginThrottler(rdb *redis.Client, cfg *config.Config) auth.LoginThrottler {
+	policy := auth.ThrottlePolicy{
+		MaxAttempts: cfg.Auth.LoginMaxAttempts,
+		Window:      cfg.Auth.LoginAttemptWindow,
+		BaseLockout: cfg.Auth.LoginLockoutBase,
+		MaxLockout:  cfg.Auth.LoginLockoutMax,
+	}
+	if cfg.Auth.ThrottleStore == "redis" {
+		if rdb != nil {
+			return auth.NewRedisLoginThrottler(rdb, policy)
+		}
+		logger.Warn("AUTH_THROTTLE_STORE=redis but Redis is disabled, falling back to memory store")
+	}
+	return auth.NewMemoryLoginThrottler(policy)
+}
+
+func newRevocationStore(rdb *redis.Client, cfg *config.Config) auth.TokenRevocationStore {
+	if cfg.Auth.RevocationStore == "redis" {
+		if rdb != nil {
+			return auth.NewRedisRevocationStore(rdb)
+		}
+		logger.Warn("AUTH_REVOCATION_STORE=redis but Redis is disabled, falling back to memory store")
+	}
+	return auth.NewMemoryRevocationStore()
 }
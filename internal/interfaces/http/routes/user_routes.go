@@ -1 +1,46 @@
-package routes
\ No newline at end of file
+package routes
+
+import (
+	"mini-ecommerce/internal/domain/entities"
+	"mini-ecommerce/internal/interfaces/http/handlers"
+	"mini-ecommerce/internal/interfaces/http/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetupUserRoutes(app *fiber.App, userHandler handlers.UserHandler, apiKeyHandler handlers.APIKeyHandler, sessionHandler handlers.SessionHandler, addressHandler handlers.AddressHandler, privacyHandler handlers.PrivacyHandler, authMiddleware fiber.Handler) {
+	users := app.Group("/users", authMiddleware)
+	// Keys act for their owner only within their scopes, and no scope covers
+	// the owner's own account.
+	users.Get("/profile", middleware.SessionOnly(), userHandler.GetProfile)
+	users.Put("/profile", middleware.SessionOnly(), userHandler.UpdateProfile)
+	me := users.Group("/me", middleware.SessionOnly())
+	me.Get("/export", privacyHandler.Export)
+	me.Post("/delete", privacyHandler.Erase)
+	addresses := users.Group("/addresses", middleware.SessionOnly())
+	addresses.Get("/", addressHandler.List)
+	addresses.Post("/", addressHandler.Create)
+	addresses.Put("/:id", addressHandler.Update)
+	addresses.Delete("/:id", addressHandler.Delete)
+	apiKeys := users.Group("/api-keys", middleware.SessionOnly())
+	apiKeys.Get("/", apiKeyHandler.List)
+	apiKeys.Post("/", apiKeyHandler.Create)
+	apiKeys.Delete("/:keyId", apiKeyHandler.Revoke)
+	sessions := users.Group("/sessions", middleware.SessionOnly())
+	sessions.Get("/", sessionHandler.List)
+	// Deleting the collection signs out every session except the current one.
+	sessions.Delete("/", sessionHandler.RevokeOthers)
+	sessions.Delete("/:sessionId", sessionHandler.Revoke)
+
+	adminUsers := app.Group("/admin/users", authMiddleware)
+	adminUsers.Get("/", middleware.RequirePermission(entities.PermissionUserRead), userHandler.List)
+	adminUsers.Get("/:id", middleware.RequirePermission(entities.PermissionUserRead), userHandler.GetById)
+	adminUsers.Put("/:id/role", middleware.RequirePermission(entities.PermissionUserManage), userHandler.UpdateRole)
+	adminUsers.Put("/:id/deactivate", middleware.RequirePermission(entities.PermissionUserManage), userHandler.Deactivate)
+	adminUsers.Put("/:id/reactivate", middleware.RequirePermission(entities.PermissionUserManage), userHandler.Reactivate)
+	adminUsers.Delete("/:id", middleware.RequirePermission(entities.PermissionUserManage), userHandler.Delete)
+	adminUsers.Post("/:id/sign-out", middleware.RequirePermission(entities.PermissionUserManage), sessionHandler.RevokeAllForUser)
+	adminUsers.Get("/:id/api-keys", middleware.RequirePermission(entities.PermissionUserManage), apiKeyHandler.ListForUser)
+	adminUsers.Post("/:id/api-keys", middleware.SessionOnly(), middleware.RequirePermission(entities.PermissionUserManage), apiKeyHandler.CreateForUser)
+	adminUsers.Delete("/:id/api-keys/:keyId", middleware.RequirePermission(entities.PermissionUserManage), apiKeyHandler.RevokeForUser)
+}
@@ -0,0 +1,215 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"mini-ecommerce/internal/domain/entities"
+	"mini-ecommerce/internal/domain/repositories"
+	"mini-ecommerce/internal/interfaces/http/dto"
+	"mini-ecommerce/pkg/validation"
+	"time"
+)
+
+var (
+	ErrAddressNotFound        = errors.New("address not found")
+	ErrDefaultAddressRequired = errors.New("the default address cannot be unset, set another address as default instead")
+)
+
+type AddressUsecase interface {
+	List(ctx context.Context, userID int) ([]*dto.AddressRes, error)
+	Create(ctx context.Context, req *dto.AddressReq) (*dto.AddressRes, error)
+	Update(ctx context.Context, req *dto.UpdateAddressReq) (*dto.AddressRes, error)
+	Delete(ctx context.Context, userID, id int) error
+	// ShippingSnapshot returns the address as stored on an order, so later
+	// edits of the address book do not change placed orders.
+	ShippingSnapshot(ctx context.Context, userID, id int) (map[string]interface{}, error)
+}
+
+type addressUseCaseImpl struct {
+	addressRepo repositories.AddressRepository
+}
+
+// List implements AddressUsecase.
+// The default address comes first.
+func (a *addressUseCaseImpl) List(ctx context.Context, userID int) ([]*dto.AddressRes, error) {
+	addresses, err := a.addressRepo.ListByUserId(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return toAddressResList(addresses), nil
+}
+
+// Create implements AddressUsecase.
+// The first address of a user always becomes the default one.
+func (a *addressUseCaseImpl) Create(ctx context.Context, req *dto.AddressReq) (*dto.AddressRes, error) {
+	address := &entities.UserAddress{
+		UserID:        req.UserID,
+		Label:         req.Label,
+		RecipientName: req.RecipientName,
+		Phone:         req.Phone,
+		AddressLine1:  req.AddressLine1,
+		AddressLine2:  req.AddressLine2,
+		City:          req.City,
+		State:         req.State,
+		PostalCode:    req.PostalCode,
+		Country:       req.Country,
+		IsDefault:     req.IsDefault,
+	}
+	if err := normalizeAddress(address); err != nil {
+		return nil, err
+	}
+	if err := a.addressRepo.Create(ctx, address); err != nil {
+		return nil, err
+	}
+	return toAddressRes(address), nil
+}
+
+// Update implements AddressUsecase.
+// Setting is_default clears the previous default. Unsetting it on the
+// current default is rejected so the user never ends up without one.
+func (a *addressUseCaseImpl) Update(ctx context.Context, req *dto.UpdateAddressReq) (*dto.AddressRes, error) {
+	address, err := a.getAddress(ctx, req.UserID, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if req.IsDefault != nil {
+		if address.IsDefault && !*req.IsDefault {
+			return nil, ErrDefaultAddressRequired
+		}
+		address.IsDefault = *req.IsDefault
+	}
+	if req.Label != nil {
+		address.Label = *req.Label
+	}
+	if req.RecipientName != nil {
+		address.RecipientName = *req.RecipientName
+	}
+	if req.Phone != nil {
+		address.Phone = *req.Phone
+	}
+	if req.AddressLine1 != nil {
+		address.AddressLine1 = *req.AddressLine1
+	}
+	if req.AddressLine2 != nil {
+		address.AddressLine2 = *req.AddressLine2
+	}
+	if req.City != nil {
+		address.City = *req.City
+	}
+	if req.State != nil {
+		address.State = *req.State
+	}
+	if req.PostalCode != nil {
+		address.PostalCode = *req.PostalCode
+	}
+	if req.Country != nil {
+		address.Country = *req.Country
+	}
+	if err := normalizeAddress(address); err != nil {
+		return nil, err
+	}
+	if err := a.addressRepo.Update(ctx, address); err != nil {
+		if errors.Is(err, repositories.ErrAddressNotFound) {
+			return nil, ErrAddressNotFound
+		}
+		return nil, err
+	}
+	return toAddressRes(address), nil
+}
+
+// Delete implements AddressUsecase.
+func (a *addressUseCaseImpl) Delete(ctx context.Context, userID, id int) error {
+	if err := a.addressRepo.Delete(ctx, userID, id); err != nil {
+		if errors.Is(err, repositories.ErrAddressNotFound) {
+			return ErrAddressNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// ShippingSnapshot implements AddressUsecase.
+// The address is validated again because the rules may have changed since it
+// was saved.
+func (a *addressUseCaseImpl) ShippingSnapshot(ctx context.Context, userID, id int) (map[string]interface{}, error) {
+	address, err := a.getAddress(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := normalizeAddress(address); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"label":          address.Label,
+		"recipient_name": address.RecipientName,
+		"phone":          address.Phone,
+		"address_line_1": address.AddressLine1,
+		"address_line_2": address.AddressLine2,
+		"city":           address.City,
+		"state":          address.State,
+		"postal_code":    address.PostalCode,
+		"country":        address.Country,
+	}, nil
+}
+
+func (a *addressUseCaseImpl) getAddress(ctx context.Context, userID, id int) (*entities.UserAddress, error) {
+	address, err := a.addressRepo.GetById(ctx, userID, id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrAddressNotFound) {
+			return nil, ErrAddressNotFound
+		}
+		return nil, err
+	}
+	return address, nil
+}
+
+// normalizeAddress applies the country rules of pkg/validation and stores the
+// canonical country code, postal code and E.164 phone number.
+func normalizeAddress(address *entities.UserAddress) error {
+	normalized := validation.Address{
+		Phone:      address.Phone,
+		State:      address.State,
+		PostalCode: address.PostalCode,
+		Country:    address.Country,
+	}
+	if err := validation.NormalizeAddress(&normalized); err != nil {
+		return err
+	}
+	address.Phone = normalized.Phone
+	address.State = normalized.State
+	address.PostalCode = normalized.PostalCode
+	address.Country = normalized.Country
+	return nil
+}
+
+func toAddressResList(addresses []*entities.UserAddress) []*dto.AddressRes {
+	res := make([]*dto.AddressRes, 0, len(addresses))
+	for _, address := range addresses {
+		res = append(res, toAddressRes(address))
+	}
+	return res
+}
+
+func toAddressRes(address *entities.UserAddress) *dto.AddressRes {
+	return &dto.AddressRes{
+		ID:            address.ID,
+		Label:         address.Label,
+		RecipientName: address.RecipientName,
+		Phone:         address.Phone,
+		AddressLine1:  address.AddressLine1,
+		AddressLine2:  address.AddressLine2,
+		City:          address.City,
+		State:         address.State,
+		PostalCode:    address.PostalCode,
+		Country:       address.Country,
+		IsDefault:     address.IsDefault,
+		CreatedAt:     address.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:     address.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func NewAddressUseCase(addressRepo repositories.AddressRepository) AddressUsecase {
+	return &addressUseCaseImpl{
+		addressRepo: addressRepo,
+	}
+}
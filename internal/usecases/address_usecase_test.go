@@ -0,0 +1,179 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"mini-ecommerce/internal/domain/entities"
+	"mini-ecommerce/internal/domain/repositories"
+	"mini-ecommerce/internal/interfaces/http/dto"
+	"slices"
+	"testing"
+	"time"
+)
+
+// fakeAddressRepository keeps addresses in memory and follows the default
+// address rules of AddressRepository. Every write advances its clock, so the
+// most recently updated address is well defined.
+type fakeAddressRepository struct {
+	repositories.AddressRepository
+	addresses []*entities.UserAddress
+	clock     time.Time
+}
+
+func (f *fakeAddressRepository) tick() time.Time {
+	f.clock = f.clock.Add(time.Second)
+	return f.clock
+}
+
+func (f *fakeAddressRepository) GetById(ctx context.Context, userID, id int) (*entities.UserAddress, error) {
+	for _, address := range f.addresses {
+		if address.ID == id && address.UserID == userID {
+			copied := *address
+			return &copied, nil
+		}
+	}
+	return nil, repositories.ErrAddressNotFound
+}
+
+func (f *fakeAddressRepository) Create(ctx context.Context, address *entities.UserAddress) error {
+	if !slices.ContainsFunc(f.addresses, func(a *entities.UserAddress) bool { return a.UserID == address.UserID }) {
+		address.IsDefault = true
+	}
+	if address.IsDefault {
+		f.clearDefault(address.UserID, 0)
+	}
+	address.ID = len(f.addresses) + 1
+	address.CreatedAt = f.tick()
+	address.UpdatedAt = address.CreatedAt
+	copied := *address
+	f.addresses = append(f.addresses, &copied)
+	return nil
+}
+
+func (f *fakeAddressRepository) Update(ctx context.Context, address *entities.UserAddress) error {
+	for i, stored := range f.addresses {
+		if stored.ID == address.ID && stored.UserID == address.UserID {
+			if address.IsDefault {
+				f.clearDefault(address.UserID, address.ID)
+			}
+			address.UpdatedAt = f.tick()
+			copied := *address
+			f.addresses[i] = &copied
+			return nil
+		}
+	}
+	return repositories.ErrAddressNotFound
+}
+
+func (f *fakeAddressRepository) Delete(ctx context.Context, userID, id int) error {
+	i := slices.IndexFunc(f.addresses, func(a *entities.UserAddress) bool { return a.ID == id && a.UserID == userID })
+	if i < 0 {
+		return repositories.ErrAddressNotFound
+	}
+	deleted := f.addresses[i]
+	f.addresses = slices.Delete(f.addresses, i, i+1)
+	if !deleted.IsDefault {
+		return nil
+	}
+	var next *entities.UserAddress
+	for _, address := range f.addresses {
+		if address.UserID == userID && (next == nil || !address.UpdatedAt.Before(next.UpdatedAt)) {
+			next = address
+		}
+	}
+	if next != nil {
+		next.IsDefault = true
+		next.UpdatedAt = f.tick()
+	}
+	return nil
+}
+
+func (f *fakeAddressRepository) clearDefault(userID, exceptID int) {
+	for _, address := range f.addresses {
+		if address.UserID == userID && address.IsDefault && address.ID != exceptID {
+			address.IsDefault = false
+			address.UpdatedAt = f.tick()
+		}
+	}
+}
+
+// defaults returns the IDs of the user's default addresses.
+func (f *fakeAddressRepository) defaults(userID int) []int {
+	var ids []int
+	for _, address := range f.addresses {
+		if address.UserID == userID && address.IsDefault {
+			ids = append(ids, address.ID)
+		}
+	}
+	return ids
+}
+
+func TestAddressSingleDefault(t *testing.T) {
+	addresses := &fakeAddressRepository{}
+	addressUseCase := NewAddressUseCase(addresses)
+	ctx := context.Background()
+	create := func(userID int, isDefault bool) int {
+		t.Helper()
+		res, err := addressUseCase.Create(ctx, &dto.AddressReq{
+			UserID: userID, Label: "Home", RecipientName: "Jane Doe", Phone: "+14155552671",
+			AddressLine1: "1 Market St", City: "San Francisco", State: "CA", PostalCode: "94103",
+			Country: "US", IsDefault: isDefault,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res.ID
+	}
+	setDefault := func(userID, id int, isDefault bool) error {
+		_, err := addressUseCase.Update(ctx, &dto.UpdateAddressReq{UserID: userID, ID: id, IsDefault: &isDefault})
+		return err
+	}
+	wantDefault := func(step string, userID, want int) {
+		t.Helper()
+		if got := addresses.defaults(userID); !slices.Equal(got, []int{want}) {
+			t.Errorf("%s: default addresses %v, want [%d]", step, got, want)
+		}
+	}
+
+	first := create(1, false)
+	wantDefault("first address", 1, first)
+	second := create(1, false)
+	wantDefault("address that is not the default", 1, first)
+	third := create(1, true)
+	wantDefault("new default address", 1, third)
+	other := create(2, false)
+	wantDefault("first address of another user", 2, other)
+	wantDefault("first address of another user", 1, third)
+
+	if err := setDefault(1, second, true); err != nil {
+		t.Fatal(err)
+	}
+	wantDefault("address made the default", 1, second)
+	if err := setDefault(1, second, false); !errors.Is(err, ErrDefaultAddressRequired) {
+		t.Errorf("unsetting the default = %v, want ErrDefaultAddressRequired", err)
+	}
+	wantDefault("unsetting the default", 1, second)
+	if err := setDefault(1, first, false); err != nil {
+		t.Errorf("unsetting a non-default address = %v", err)
+	}
+
+	// first was updated last, so it takes over from the deleted default.
+	if err := addressUseCase.Delete(ctx, 1, second); err != nil {
+		t.Fatal(err)
+	}
+	wantDefault("deleting the default", 1, first)
+	if err := addressUseCase.Delete(ctx, 1, third); err != nil {
+		t.Fatal(err)
+	}
+	wantDefault("deleting another address", 1, first)
+	if err := addressUseCase.Delete(ctx, 1, first); err != nil {
+		t.Fatal(err)
+	}
+	if got := addresses.defaults(1); len(got) != 0 {
+		t.Errorf("default addresses %v after deleting every address", got)
+	}
+	wantDefault("deleting another user's addresses", 2, other)
+	if err := addressUseCase.Delete(ctx, 1, other); !errors.Is(err, ErrAddressNotFound) {
+		t.Errorf("deleting another user's address = %v, want ErrAddressNotFound", err)
+	}
+}
@@ -0,0 +1,149 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"mini-ecommerce/internal/domain/entities"
+	"mini-ecommerce/internal/domain/repositories"
+	"mini-ecommerce/internal/infrastructure/auth"
+	"mini-ecommerce/internal/interfaces/http/dto"
+	"mini-ecommerce/pkg/logger"
+	"time"
+)
+
+// APIKeyPrefix marks personal API keys so they are easy to recognise in
+// configuration files and secret scanners.
+const APIKeyPrefix = "mek_"
+
+var (
+	ErrAPIKeyNotFound = errors.New("api key not found")
+	ErrInvalidScope   = errors.New("invalid api key scope")
+)
+
+type APIKeyUsecase interface {
+	Create(ctx context.Context, userID int, req *dto.CreateAPIKeyReq) (*dto.APIKeyCreatedRes, error)
+	List(ctx context.Context, userID int) ([]dto.APIKeyRes, error)
+	Revoke(ctx context.Context, userID, id int) error
+}
+
+type apiKeyUseCaseImpl struct {
+	apiKeyRepo repositories.APIKeyRepository
+	userRepo   repositories.UserRepository
+}
+
+// Create implements APIKeyUsecase.
+// Scopes are limited to permissions the owner's role already holds, so a key
+// can never do more than its owner.
+func (a *apiKeyUseCaseImpl) Create(ctx context.Context, userID int, req *dto.CreateAPIKeyReq) (*dto.APIKeyCreatedRes, error) {
+	user, err := a.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	scopes := make([]entities.Permission, 0, len(req.Scopes))
+	seen := make(map[entities.Permission]bool, len(req.Scopes))
+	for _, s := range req.Scopes {
+		scope := entities.Permission(s)
+		if !entities.IsValidPermission(scope) || !entities.HasPermission(user.Role, scope) {
+			return nil, ErrInvalidScope
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+	secret, err := auth.GenerateRandomToken(32)
+	if err != nil {
+		logger.Error(err, "[ErrAPIKeyUsecase-1] failed to generate api key")
+		return nil, err
+	}
+	rawKey := APIKeyPrefix + secret
+	key := &entities.APIKey{
+		UserID:  user.ID,
+		Name:    req.Name,
+		Prefix:  rawKey[:len(APIKeyPrefix)+8],
+		KeyHash: auth.HashToken(rawKey),
+		Scopes:  scopes,
+	}
+	if req.ExpiresInDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, req.ExpiresInDays)
+		key.ExpiresAt = &expiresAt
+	}
+	if err := a.apiKeyRepo.Create(ctx, key); err != nil {
+		return nil, err
+	}
+	logger.Infof("[APIKeyUsecase] api key %d created for user %d", key.ID, user.ID)
+	return &dto.APIKeyCreatedRes{
+		APIKeyRes: toAPIKeyRes(key),
+		Key:       rawKey,
+	}, nil
+}
+
+// List implements APIKeyUsecase.
+func (a *apiKeyUseCaseImpl) List(ctx context.Context, userID int) ([]dto.APIKeyRes, error) {
+	if _, err := a.getUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	keys, err := a.apiKeyRepo.ListByUserId(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]dto.APIKeyRes, 0, len(keys))
+	for _, key := range keys {
+		res = append(res, toAPIKeyRes(key))
+	}
+	return res, nil
+}
+
+// Revoke implements APIKeyUsecase.
+func (a *apiKeyUseCaseImpl) Revoke(ctx context.Context, userID, id int) error {
+	if err := a.apiKeyRepo.Revoke(ctx, userID, id); err != nil {
+		if errors.Is(err, repositories.ErrAPIKeyNotFound) {
+			return ErrAPIKeyNotFound
+		}
+		return err
+	}
+	logger.Infof("[APIKeyUsecase] api key %d revoked for user %d", id, userID)
+	return nil
+}
+
+func (a *apiKeyUseCaseImpl) getUser(ctx context.Context, userID int) (*entities.User, error) {
+	user, err := a.userRepo.GetById(ctx, userID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+func toAPIKeyRes(key *entities.APIKey) dto.APIKeyRes {
+	scopes := make([]string, 0, len(key.Scopes))
+	for _, scope := range key.Scopes {
+		scopes = append(scopes, string(scope))
+	}
+	return dto.APIKeyRes{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     scopes,
+		ExpiresAt:  formatOptionalTime(key.ExpiresAt),
+		LastUsedAt: formatOptionalTime(key.LastUsedAt),
+		RevokedAt:  formatOptionalTime(key.RevokedAt),
+		CreatedAt:  key.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func NewAPIKeyUseCase(apiKeyRepo repositories.APIKeyRepository, userRepo repositories.UserRepository) APIKeyUsecase {
+	return &apiKeyUseCaseImpl{
+		apiKeyRepo: apiKeyRepo,
+		userRepo:   userRepo,
+	}
+}
@@ -0,0 +1,68 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"mini-ecommerce/internal/domain/entities"
+	"mini-ecommerce/internal/domain/repositories"
+	"mini-ecommerce/internal/interfaces/http/dto"
+	"slices"
+	"testing"
+)
+
+// fakeAPIKeyRepository keeps created keys in memory.
+type fakeAPIKeyRepository struct {
+	repositories.APIKeyRepository
+	keys []*entities.APIKey
+}
+
+func (f *fakeAPIKeyRepository) Create(ctx context.Context, key *entities.APIKey) error {
+	key.ID = len(f.keys) + 1
+	f.keys = append(f.keys, key)
+	return nil
+}
+
+func TestAPIKeyCreateScopes(t *testing.T) {
+	users := &fakeUserRepository{users: map[int]*entities.User{
+		1: {ID: 1, Role: entities.RoleSupport},
+		2: {ID: 2, Role: entities.RoleAdmin},
+	}}
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		userID     int
+		scopes     []string
+		wantScopes []string
+		wantErr    error
+	}{
+		{name: "scope of the role", userID: 1, scopes: []string{"user:read"}, wantScopes: []string{"user:read"}},
+		{name: "duplicates removed", userID: 1, scopes: []string{"user:read", "user:read"}, wantScopes: []string{"user:read"}},
+		{name: "scope outside the role", userID: 1, scopes: []string{"user:read", "user:manage"}, wantErr: ErrInvalidScope},
+		{name: "unknown scope", userID: 2, scopes: []string{"product:delete"}, wantErr: ErrInvalidScope},
+		{name: "admin", userID: 2, scopes: []string{"*", "product:write", "*"}, wantScopes: []string{"*", "product:write"}},
+		{name: "no scopes", userID: 1, wantScopes: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys := &fakeAPIKeyRepository{}
+			apiKeyUseCase := NewAPIKeyUseCase(keys, users)
+			res, err := apiKeyUseCase.Create(ctx, tt.userID, &dto.CreateAPIKeyReq{Name: "ci", Scopes: tt.scopes})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Create() = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if len(keys.keys) != 0 {
+					t.Error("a key was stored although its scopes were rejected")
+				}
+				return
+			}
+			if !slices.Equal(res.Scopes, tt.wantScopes) {
+				t.Errorf("scopes = %v, want %v", res.Scopes, tt.wantScopes)
+			}
+			if len(keys.keys) != 1 || len(keys.keys[0].Scopes) != len(tt.wantScopes) {
+				t.Errorf("stored keys = %+v, want one key with scopes %v", keys.keys, tt.wantScopes)
+			}
+		})
+	}
+}
@@ -3,24 +3,382 @@ package usecases
 import (
 	"context"
 	"errors"
+	"fmt"
 	"mini-ecommerce/internal/domain/entities"
 	"mini-ecommerce/internal/domain/repositories"
+	"mini-ecommerce/internal/infrastructure/audit"
 	"mini-ecommerce/internal/infrastructure/auth"
+	"mini-ecommerce/internal/infrastructure/mail"
 	"mini-ecommerce/internal/interfaces/http/dto"
+	"mini-ecommerce/pkg/logger"
+	"net/url"
+	"strings"
 	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	ErrUserInactive       = errors.New("user account is inactive")
+	ErrInvalidRefresh     = errors.New("invalid or expired refresh token")
+	ErrRefreshReused      = errors.New("refresh token reuse detected")
+	ErrEmailExists        = errors.New("email already exists")
+	ErrEmailNotVerified   = errors.New("email address is not verified")
+	ErrInvalidVerifyToken = errors.New("invalid or expired verification token")
+	ErrInvalidResetToken  = errors.New("invalid or expired password reset token")
+	ErrInvalidChallenge   = errors.New("invalid or expired two-factor challenge")
 )
 
+const twoFactorChallengeExpire = 5 * time.Minute
+
+// LoginLockedError is returned while an email or client IP is locked out after
+// too many failed login attempts.
+type LoginLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginLockedError) Error() string {
+	return "too many failed login attempts, try again later"
+}
+
+// AuthSettings holds the tunables of the authentication flows
+type AuthSettings struct {
+	RefreshExpire            time.Duration
+	RequireEmailVerification bool
+	EmailVerificationExpire  time.Duration
+	PasswordResetExpire      time.Duration
+	// PasswordResetURL is the page password reset links open, by default
+	// the form at /auth/reset-password
+	PasswordResetURL string
+	// BaseURL is the public URL used to build links sent by email
+	BaseURL string
+}
+
 type AuthUsecase interface {
 	Login(ctx context.Context, req *dto.UserLoginReq) (*dto.UserLoginRes, error)
 	Register(ctx context.Context, req *dto.UserReq) (*dto.UserRes, error)
+	Refresh(ctx context.Context, req *dto.RefreshTokenReq) (*dto.UserLoginRes, error)
+	Logout(ctx context.Context, req *dto.LogoutReq) error
+	VerifyEmail(ctx context.Context, req *dto.VerifyEmailReq) error
+	ResendVerification(ctx context.Context, req *dto.ResendVerificationReq) error
+	ForgotPassword(ctx context.Context, req *dto.ForgotPasswordReq) error
+	ResetPassword(ctx context.Context, req *dto.ResetPasswordReq) error
+	CompleteTwoFactorLogin(ctx context.Context, req *dto.TwoFactorLoginReq) (*dto.UserLoginRes, error)
 }
 type authUseCaseImpl struct {
-	userRepo repositories.UserRepository
+	userRepo         repositories.UserRepository
+	refreshTokenRepo repositories.RefreshTokenRepository
+	sessionRepo      repositories.SessionRepository
+	resetRepo        repositories.PasswordResetRepository
+	twoFactor        TwoFactorUsecase
+	jwtService       auth.JWTService
+	hasher           auth.PasswordHasher
+	revocationStore  auth.TokenRevocationStore
+	throttler        auth.LoginThrottler
+	auditor          audit.Recorder
+	mailer           mail.Mailer
+	settings         AuthSettings
 }
 
 // Login implements AuthUsecase.
 func (a *authUseCaseImpl) Login(ctx context.Context, req *dto.UserLoginReq) (*dto.UserLoginRes, error) {
-	panic("unimplemented")
+	emailKey := "email:" + strings.ToLower(req.Email)
+	ipKey := "ip:" + req.IPAddress
+	for _, key := range []string{emailKey, ipKey} {
+		lockedFor, err := a.throttler.LockedFor(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		if lockedFor > 0 {
+			return nil, &LoginLockedError{RetryAfter: lockedFor}
+		}
+	}
+	user, err := a.userRepo.GetByEmail(ctx, req.Email)
+	if err != nil {
+		if err.Error() == "user not found" {
+			return nil, a.loginFailed(ctx, req.IPAddress, 0, emailKey, ipKey)
+		}
+		return nil, err
+	}
+	if !a.hasher.Verify(req.Password, user.Password) {
+		return nil, a.loginFailed(ctx, req.IPAddress, user.ID, emailKey, ipKey)
+	}
+	// Only the account counter is cleared; the IP keeps its history so one
+	// valid login cannot be used to reset a credential-stuffing run.
+	if err := a.throttler.Reset(ctx, emailKey); err != nil {
+		logger.Error(err, "[ErrAuthUsecase-8] failed to reset login throttle")
+	}
+	a.upgradePasswordHash(ctx, user, req.Password)
+	if !user.IsActive {
+		return nil, ErrUserInactive
+	}
+	if a.settings.RequireEmailVerification && !user.EmailVerified {
+		return nil, ErrEmailNotVerified
+	}
+	// Enrolled users only get a short-lived challenge token here; the session
+	// is issued by CompleteTwoFactorLogin once the second factor checks out.
+	if user.TwoFactorEnabled {
+		challenge, err := a.jwtService.GenerateActionToken(auth.PurposeTwoFactorChallenge, user.ID, user.Email, twoFactorChallengeExpire)
+		if err != nil {
+			return nil, err
+		}
+		return &dto.UserLoginRes{
+			Name:              user.Name,
+			Email:             user.Email,
+			TwoFactorRequired: true,
+			ChallengeToken:    challenge,
+		}, nil
+	}
+	res, err := a.issueTokens(ctx, user, a.newRefreshToken(user.ID, req.DeviceID, req.UserAgent, req.IPAddress), 0)
+	if err != nil {
+		return nil, err
+	}
+	res.TwoFactorSetupRequired = a.twoFactor.IsRequired(user.Role)
+	return res, nil
+}
+
+// CompleteTwoFactorLogin implements AuthUsecase.
+// Failed codes are throttled per user so a stolen password cannot be used to
+// brute-force the six digit code within the challenge lifetime.
+func (a *authUseCaseImpl) CompleteTwoFactorLogin(ctx context.Context, req *dto.TwoFactorLoginReq) (*dto.UserLoginRes, error) {
+	claims, err := a.jwtService.ValidateActionToken(auth.PurposeTwoFactorChallenge, req.ChallengeToken)
+	if err != nil {
+		return nil, ErrInvalidChallenge
+	}
+	key := fmt.Sprintf("2fa:%d", claims.UserID)
+	lockedFor, err := a.throttler.LockedFor(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if lockedFor > 0 {
+		return nil, &LoginLockedError{RetryAfter: lockedFor}
+	}
+	user, err := a.userRepo.GetById(ctx, claims.UserID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			return nil, ErrInvalidChallenge
+		}
+		return nil, err
+	}
+	if !user.IsActive {
+		return nil, ErrUserInactive
+	}
+	if err := a.twoFactor.Verify(ctx, user.ID, req.Code); err != nil {
+		if errors.Is(err, ErrInvalidTwoFactorCode) {
+			a.loginFailed(ctx, req.IPAddress, user.ID, key)
+			return nil, ErrInvalidTwoFactorCode
+		}
+		if errors.Is(err, ErrTwoFactorNotEnabled) {
+			return nil, ErrInvalidChallenge
+		}
+		return nil, err
+	}
+	if err := a.throttler.Reset(ctx, key); err != nil {
+		logger.Error(err, "[ErrAuthUsecase-8] failed to reset login throttle")
+	}
+	return a.issueTokens(ctx, user, a.newRefreshToken(user.ID, req.DeviceID, req.UserAgent, req.IPAddress), 0)
+}
+
+// newRefreshToken starts a new refresh token family for a fresh sign-in.
+func (a *authUseCaseImpl) newRefreshToken(userID int, deviceID, userAgent, ipAddress string) *entities.RefreshToken {
+	return &entities.RefreshToken{
+		UserID:    userID,
+		FamilyID:  uuid.NewString(),
+		DeviceID:  deviceID,
+		UserAgent: userAgent,
+		IPAddress: ipAddress,
+	}
+}
+
+// upgradePasswordHash rehashes the password with the current algorithm and
+// parameters. The plaintext is only available here, at a successful login.
+func (a *authUseCaseImpl) upgradePasswordHash(ctx context.Context, user *entities.User, password string) {
+	if !a.hasher.NeedsRehash(user.Password) {
+		return
+	}
+	hashedPassword, err := a.hasher.Hash(password)
+	if err != nil {
+		logger.Error(err, "[ErrAuthUsecase-10] failed to rehash password")
+		return
+	}
+	if err := a.userRepo.UpgradePasswordHash(ctx, user.ID, user.Password, hashedPassword); err != nil {
+		logger.Error(err, "[ErrAuthUsecase-11] failed to store upgraded password hash")
+		return
+	}
+	user.Password = hashedPassword
+}
+
+// loginFailed records a failed attempt for every key and reports a lockout
+// as an audit event the moment one is triggered.
+func (a *authUseCaseImpl) loginFailed(ctx context.Context, ipAddress string, userID int, keys ...string) error {
+	for _, key := range keys {
+		lockout, err := a.throttler.RegisterFailure(ctx, key)
+		if err != nil {
+			logger.Error(err, "[ErrAuthUsecase-9] failed to register login failure")
+			continue
+		}
+		if lockout > 0 {
+			a.auditor.Record(ctx, audit.Event{
+				Type:    audit.EventLoginLockout,
+				UserID:  userID,
+				Subject: key,
+				IP:      ipAddress,
+				Details: map[string]interface{}{"lockout_seconds": int(lockout.Seconds())},
+			})
+		}
+	}
+	return ErrInvalidCredentials
+}
+
+// Refresh implements AuthUsecase.
+// Every call rotates the refresh token. Presenting a token that was already
+// rotated is treated as theft and ends its session with the whole token family.
+func (a *authUseCaseImpl) Refresh(ctx context.Context, req *dto.RefreshTokenReq) (*dto.UserLoginRes, error) {
+	current, err := a.refreshTokenRepo.GetByHash(ctx, auth.HashToken(req.RefreshToken))
+	if err != nil {
+		if errors.Is(err, repositories.ErrRefreshTokenNotFound) {
+			return nil, ErrInvalidRefresh
+		}
+		return nil, err
+	}
+	// A signed-out session also revoked its tokens; that is not a reuse.
+	session, err := a.sessionRepo.GetById(ctx, current.FamilyID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrSessionNotFound) {
+			return nil, ErrInvalidRefresh
+		}
+		return nil, err
+	}
+	if session.RevokedAt != nil {
+		return nil, ErrInvalidRefresh
+	}
+	if current.RevokedAt != nil {
+		return nil, a.revokeFamily(ctx, current)
+	}
+	if time.Now().After(current.ExpiresAt) {
+		return nil, ErrInvalidRefresh
+	}
+	user, err := a.userRepo.GetById(ctx, current.UserID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			return nil, ErrInvalidRefresh
+		}
+		return nil, err
+	}
+	if !user.IsActive {
+		return nil, ErrUserInactive
+	}
+	deviceID := current.DeviceID
+	if req.DeviceID != "" {
+		deviceID = req.DeviceID
+	}
+	next := &entities.RefreshToken{
+		UserID:    user.ID,
+		FamilyID:  current.FamilyID,
+		DeviceID:  deviceID,
+		UserAgent: req.UserAgent,
+		IPAddress: req.IPAddress,
+	}
+	res, err := a.issueTokens(ctx, user, next, current.ID)
+	if errors.Is(err, repositories.ErrRefreshTokenRevoked) {
+		return nil, a.revokeFamily(ctx, current)
+	}
+	return res, err
+}
+
+// Logout implements AuthUsecase.
+// The presented access token is revoked by its ID and its session is ended;
+// when a refresh token is supplied its whole family is revoked as well.
+func (a *authUseCaseImpl) Logout(ctx context.Context, req *dto.LogoutReq) error {
+	if err := a.revocationStore.Revoke(ctx, req.TokenID, req.ExpiresAt); err != nil {
+		logger.Error(err, "[ErrAuthUsecase-4] failed to revoke access token")
+		return err
+	}
+	if err := a.sessionRepo.Revoke(ctx, req.UserID, req.SessionID); err != nil && !errors.Is(err, repositories.ErrSessionNotFound) {
+		return err
+	}
+	if req.RefreshToken == "" {
+		return nil
+	}
+	token, err := a.refreshTokenRepo.GetByHash(ctx, auth.HashToken(req.RefreshToken))
+	if err != nil {
+		if errors.Is(err, repositories.ErrRefreshTokenNotFound) {
+			return nil
+		}
+		return err
+	}
+	if token.UserID != req.UserID {
+		return nil
+	}
+	return a.refreshTokenRepo.RevokeFamily(ctx, token.FamilyID)
+}
+
+// revokeFamily ends the session of a reused refresh token. That revokes the
+// whole token family and, as the family is the session, every access token
+// issued to it.
+func (a *authUseCaseImpl) revokeFamily(ctx context.Context, token *entities.RefreshToken) error {
+	logger.Warnf("[AuthUsecase] refresh token reuse detected for user %d, revoking family %s", token.UserID, token.FamilyID)
+	err := a.sessionRepo.Revoke(ctx, token.UserID, token.FamilyID)
+	if errors.Is(err, repositories.ErrSessionNotFound) {
+		// The session ended meanwhile; its tokens may still need revoking.
+		err = a.refreshTokenRepo.RevokeFamily(ctx, token.FamilyID)
+	}
+	if err != nil {
+		logger.Error(err, "[ErrAuthUsecase-3] failed to revoke refresh token family")
+		return err
+	}
+	return ErrRefreshReused
+}
+
+// issueTokens signs a new access token and persists the given refresh token.
+// When rotatedFromID is set the previous refresh token is revoked atomically
+// and the session is extended, otherwise a new session is started.
+func (a *authUseCaseImpl) issueTokens(ctx context.Context, user *entities.User, refreshToken *entities.RefreshToken, rotatedFromID int) (*dto.UserLoginRes, error) {
+	accessToken, _, err := a.jwtService.GenerateToken(user.ID, user.Email, user.Role, refreshToken.FamilyID)
+	if err != nil {
+		logger.Error(err, "[ErrAuthUsecase-1] failed to generate access token")
+		return nil, err
+	}
+	rawRefreshToken, err := auth.GenerateRandomToken(32)
+	if err != nil {
+		logger.Error(err, "[ErrAuthUsecase-2] failed to generate refresh token")
+		return nil, err
+	}
+	now := time.Now()
+	refreshToken.TokenHash = auth.HashToken(rawRefreshToken)
+	refreshToken.ExpiresAt = now.Add(a.settings.RefreshExpire)
+	if rotatedFromID != 0 {
+		err = a.refreshTokenRepo.Rotate(ctx, rotatedFromID, refreshToken)
+		if err == nil {
+			err = a.sessionRepo.Extend(ctx, refreshToken.FamilyID, refreshToken.IPAddress, refreshToken.UserAgent, refreshToken.ExpiresAt)
+		}
+	} else {
+		err = a.sessionRepo.Create(ctx, &entities.Session{
+			ID:             refreshToken.FamilyID,
+			UserID:         user.ID,
+			DeviceID:       refreshToken.DeviceID,
+			UserAgent:      refreshToken.UserAgent,
+			IPAddress:      refreshToken.IPAddress,
+			LastActivityAt: now,
+			ExpiresAt:      refreshToken.ExpiresAt,
+		})
+		if err == nil {
+			err = a.refreshTokenRepo.Create(ctx, refreshToken)
+		}
+	}
+	if err != nil {
+		if errors.Is(err, repositories.ErrSessionNotFound) {
+			return nil, ErrInvalidRefresh
+		}
+		return nil, err
+	}
+	return &dto.UserLoginRes{
+		Name:         user.Name,
+		Email:        user.Email,
+		AccessToken:  accessToken,
+		RefreshToken: rawRefreshToken,
+	}, nil
 }
 
 // Register implements AuthUsecase.
@@ -31,9 +389,9 @@ func (a *authUseCaseImpl) Register(ctx context.Context, req *dto.UserReq) (*dto.
 			return nil, err
 		}
 	} else if exists != nil {
-		return nil, errors.New("email already exists")
+		return nil, ErrEmailExists
 	}
-	hashedPassword, err := auth.HashPassword(req.Password)
+	hashedPassword, err := a.hasher.Hash(req.Password)
 	if err != nil {
 		return nil, err
 	}
@@ -42,25 +400,190 @@ func (a *authUseCaseImpl) Register(ctx context.Context, req *dto.UserReq) (*dto.
 		Name:     req.Name,
 		Password: hashedPassword,
 		Phone:    req.Phone,
-		Role:     req.Role,
+		Role:     entities.RoleCustomer,
+		IsActive: true,
 	}
 	err = a.userRepo.Create(ctx, user)
 	if err != nil {
 		return nil, err
 	}
-	return &dto.UserRes{
-		ID:        user.ID,
-		Email:     user.Email,
-		Name:      user.Name,
-		Phone:     user.Phone,
-		Role:      user.Role,
-		CreatedAt: time.Now().Format(time.RFC3339),
-		UpdatedAt: time.Now().Format(time.RFC3339),
-	}, nil
+	if err := a.sendVerificationEmail(ctx, user); err != nil {
+		// The account exists at this point; the user can ask for a new link.
+		logger.Error(err, "[ErrAuthUsecase-5] failed to send verification email")
+	}
+	return toUserRes(user), nil
+}
+
+// VerifyEmail implements AuthUsecase.
+func (a *authUseCaseImpl) VerifyEmail(ctx context.Context, req *dto.VerifyEmailReq) error {
+	claims, err := a.jwtService.ValidateActionToken(auth.PurposeEmailVerification, req.Token)
+	if err != nil {
+		return ErrInvalidVerifyToken
+	}
+	user, err := a.userRepo.GetById(ctx, claims.UserID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			return ErrInvalidVerifyToken
+		}
+		return err
+	}
+	// A link sent to a previous address must not verify the current one.
+	if !strings.EqualFold(user.Email, claims.Email) {
+		return ErrInvalidVerifyToken
+	}
+	if user.EmailVerified {
+		return nil
+	}
+	if err := a.userRepo.MarkEmailVerified(ctx, user.ID, user.Email); err != nil {
+		if err.Error() == "user not found" {
+			return ErrInvalidVerifyToken
+		}
+		return err
+	}
+	return nil
+}
+
+// ResendVerification implements AuthUsecase.
+// It succeeds silently for unknown or already verified addresses.
+func (a *authUseCaseImpl) ResendVerification(ctx context.Context, req *dto.ResendVerificationReq) error {
+	user, err := a.userRepo.GetByEmail(ctx, req.Email)
+	if err != nil {
+		if err.Error() == "user not found" {
+			return nil
+		}
+		return err
+	}
+	if user.EmailVerified || !user.IsActive {
+		return nil
+	}
+	return a.sendVerificationEmail(ctx, user)
+}
+
+// ForgotPassword implements AuthUsecase.
+// The outcome is never reported to the caller so the endpoint cannot be used
+// to discover registered addresses.
+func (a *authUseCaseImpl) ForgotPassword(ctx context.Context, req *dto.ForgotPasswordReq) error {
+	user, err := a.userRepo.GetByEmail(ctx, req.Email)
+	if err != nil {
+		if err.Error() == "user not found" {
+			return nil
+		}
+		return err
+	}
+	if !user.IsActive {
+		return nil
+	}
+	if err := a.resetRepo.InvalidateByUserId(ctx, user.ID); err != nil {
+		return err
+	}
+	rawToken, err := auth.GenerateRandomToken(32)
+	if err != nil {
+		return err
+	}
+	token := &entities.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: auth.HashToken(rawToken),
+		ExpiresAt: time.Now().Add(a.settings.PasswordResetExpire),
+	}
+	if err := a.resetRepo.Create(ctx, token); err != nil {
+		return err
+	}
+	link := a.passwordResetLink(rawToken)
+	if err := a.mailer.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nWe received a request to reset your password. Use the link below to choose a new one:\n\n%s\n\nThe link expires in %s and can only be used once. If you did not request this, you can ignore this email.\n",
+			user.Name, link, a.settings.PasswordResetExpire),
+	}); err != nil {
+		logger.Error(err, "[ErrAuthUsecase-6] failed to send password reset email")
+	}
+	return nil
+}
+
+// ResetPassword implements AuthUsecase.
+// A successful reset signs the user out everywhere: access tokens issued
+// before the change are rejected and all refresh tokens are revoked.
+func (a *authUseCaseImpl) ResetPassword(ctx context.Context, req *dto.ResetPasswordReq) error {
+	token, err := a.resetRepo.GetByHash(ctx, auth.HashToken(req.Token))
+	if err != nil {
+		if errors.Is(err, repositories.ErrPasswordResetTokenNotFound) {
+			return ErrInvalidResetToken
+		}
+		return err
+	}
+	if token.UsedAt != nil || time.Now().After(token.ExpiresAt) {
+		return ErrInvalidResetToken
+	}
+	if err := a.resetRepo.MarkUsed(ctx, token.ID); err != nil {
+		if errors.Is(err, repositories.ErrPasswordResetTokenUsed) {
+			return ErrInvalidResetToken
+		}
+		return err
+	}
+	user, err := a.userRepo.GetById(ctx, token.UserID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			return ErrInvalidResetToken
+		}
+		return err
+	}
+	hashedPassword, err := a.hasher.Hash(req.Password)
+	if err != nil {
+		return err
+	}
+	if err := a.userRepo.UpdatePassword(ctx, user.ID, hashedPassword); err != nil {
+		if err.Error() == "user not found" {
+			return ErrInvalidResetToken
+		}
+		return err
+	}
+	if err := a.sessionRepo.RevokeAllByUserId(ctx, user.ID, ""); err != nil {
+		logger.Error(err, "[ErrAuthUsecase-7] failed to revoke sessions after password reset")
+		return err
+	}
+	return nil
+}
+
+// passwordResetLink returns the link of a password reset email.
+func (a *authUseCaseImpl) passwordResetLink(rawToken string) string {
+	page := a.settings.PasswordResetURL
+	if page == "" {
+		page = strings.TrimRight(a.settings.BaseURL, "/") + "/auth/reset-password"
+	}
+	separator := "?"
+	if strings.Contains(page, "?") {
+		separator = "&"
+	}
+	return page + separator + "token=" + url.QueryEscape(rawToken)
+}
+
+func (a *authUseCaseImpl) sendVerificationEmail(ctx context.Context, user *entities.User) error {
+	token, err := a.jwtService.GenerateActionToken(auth.PurposeEmailVerification, user.ID, user.Email, a.settings.EmailVerificationExpire)
+	if err != nil {
+		return err
+	}
+	link := strings.TrimRight(a.settings.BaseURL, "/") + "/auth/verify-email?token=" + url.QueryEscape(token)
+	return a.mailer.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Hi %s,\n\nPlease confirm your email address by opening the link below:\n\n%s\n\nThe link expires in %s.\n",
+			user.Name, link, a.settings.EmailVerificationExpire),
+	})
 }
 
-func NewAuthUsecase(userRepo repositories.UserRepository) AuthUsecase {
+func NewAuthUsecase(userRepo repositories.UserRepository, refreshTokenRepo repositories.RefreshTokenRepository, sessionRepo repositories.SessionRepository, resetRepo repositories.PasswordResetRepository, twoFactor TwoFactorUsecase, jwtService auth.JWTService, hasher auth.PasswordHasher, revocationStore auth.TokenRevocationStore, throttler auth.LoginThrottler, auditor audit.Recorder, mailer mail.Mailer, settings AuthSettings) AuthUsecase {
 	return &authUseCaseImpl{
-		userRepo: userRepo,
+		userRepo:         userRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		sessionRepo:      sessionRepo,
+		resetRepo:        resetRepo,
+		twoFactor:        twoFactor,
+		jwtService:       jwtService,
+		hasher:           hasher,
+		revocationStore:  revocationStore,
+		throttler:        throttler,
+		auditor:          auditor,
+		mailer:           mailer,
+		settings:         settings,
 	}
 }
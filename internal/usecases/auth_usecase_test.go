@@ -0,0 +1,360 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"mini-ecommerce/internal/domain/entities"
+	"mini-ecommerce/internal/domain/repositories"
+	"mini-ecommerce/internal/infrastructure/auth"
+	"mini-ecommerce/internal/infrastructure/mail"
+	"mini-ecommerce/internal/interfaces/http/dto"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeUserRepository keeps users in memory by ID.
+type fakeUserRepository struct {
+	repositories.UserRepository
+	users map[int]*entities.User
+}
+
+func (f *fakeUserRepository) GetById(ctx context.Context, id int) (*entities.User, error) {
+	user, ok := f.users[id]
+	if !ok {
+		return nil, errors.New("user not found")
+	}
+	copied := *user
+	return &copied, nil
+}
+
+func (f *fakeUserRepository) GetByEmail(ctx context.Context, email string) (*entities.User, error) {
+	for _, user := range f.users {
+		if user.Email == email {
+			copied := *user
+			return &copied, nil
+		}
+	}
+	return nil, errors.New("user not found")
+}
+
+func (f *fakeUserRepository) UpdatePassword(ctx context.Context, id int, hash string) error {
+	user, ok := f.users[id]
+	if !ok {
+		return errors.New("user not found")
+	}
+	user.Password = hash
+	return nil
+}
+
+// fakeRefreshTokenRepository keeps refresh tokens in memory.
+type fakeRefreshTokenRepository struct {
+	repositories.RefreshTokenRepository
+	tokens []*entities.RefreshToken
+}
+
+func (f *fakeRefreshTokenRepository) Create(ctx context.Context, token *entities.RefreshToken) error {
+	token.ID = len(f.tokens) + 1
+	copied := *token
+	f.tokens = append(f.tokens, &copied)
+	return nil
+}
+
+func (f *fakeRefreshTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*entities.RefreshToken, error) {
+	for _, token := range f.tokens {
+		if token.TokenHash == tokenHash {
+			copied := *token
+			return &copied, nil
+		}
+	}
+	return nil, repositories.ErrRefreshTokenNotFound
+}
+
+func (f *fakeRefreshTokenRepository) Rotate(ctx context.Context, oldID int, newToken *entities.RefreshToken) error {
+	old := f.tokens[oldID-1]
+	if old.RevokedAt != nil {
+		return repositories.ErrRefreshTokenRevoked
+	}
+	now := time.Now()
+	old.RevokedAt = &now
+	return f.Create(ctx, newToken)
+}
+
+func (f *fakeRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	f.revoke(func(token *entities.RefreshToken) bool { return token.FamilyID == familyID })
+	return nil
+}
+
+func (f *fakeRefreshTokenRepository) RevokeAllByUserId(ctx context.Context, userID int) error {
+	f.revoke(func(token *entities.RefreshToken) bool { return token.UserID == userID })
+	return nil
+}
+
+func (f *fakeRefreshTokenRepository) revoke(match func(token *entities.RefreshToken) bool) {
+	now := time.Now()
+	for _, token := range f.tokens {
+		if token.RevokedAt == nil && match(token) {
+			token.RevokedAt = &now
+		}
+	}
+}
+
+// active returns the unrevoked tokens of a family.
+func (f *fakeRefreshTokenRepository) active(familyID string) []*entities.RefreshToken {
+	var active []*entities.RefreshToken
+	for _, token := range f.tokens {
+		if token.FamilyID == familyID && token.RevokedAt == nil {
+			active = append(active, token)
+		}
+	}
+	return active
+}
+
+// fakeSessionRepository keeps sessions in memory. Like the real one it
+// revokes the refresh tokens of the sessions it ends.
+type fakeSessionRepository struct {
+	repositories.SessionRepository
+	sessions map[string]*entities.Session
+	tokens   *fakeRefreshTokenRepository
+}
+
+func (f *fakeSessionRepository) Create(ctx context.Context, session *entities.Session) error {
+	copied := *session
+	f.sessions[session.ID] = &copied
+	return nil
+}
+
+func (f *fakeSessionRepository) GetById(ctx context.Context, id string) (*entities.Session, error) {
+	session, ok := f.sessions[id]
+	if !ok {
+		return nil, repositories.ErrSessionNotFound
+	}
+	copied := *session
+	return &copied, nil
+}
+
+func (f *fakeSessionRepository) ListActiveByUserId(ctx context.Context, userID int) ([]*entities.Session, error) {
+	var sessions []*entities.Session
+	for _, session := range f.sessions {
+		if session.UserID == userID && session.RevokedAt == nil {
+			copied := *session
+			sessions = append(sessions, &copied)
+		}
+	}
+	return sessions, nil
+}
+
+func (f *fakeSessionRepository) Extend(ctx context.Context, id, ipAddress, userAgent string, expiresAt time.Time) error {
+	session, ok := f.sessions[id]
+	if !ok || session.RevokedAt != nil {
+		return repositories.ErrSessionNotFound
+	}
+	session.ExpiresAt = expiresAt
+	return nil
+}
+
+func (f *fakeSessionRepository) Revoke(ctx context.Context, userID int, id string) error {
+	session, ok := f.sessions[id]
+	if !ok || session.UserID != userID || session.RevokedAt != nil {
+		return repositories.ErrSessionNotFound
+	}
+	now := time.Now()
+	session.RevokedAt = &now
+	return f.tokens.RevokeFamily(ctx, id)
+}
+
+func (f *fakeSessionRepository) RevokeAllByUserId(ctx context.Context, userID int, exceptID string) error {
+	now := time.Now()
+	for id, session := range f.sessions {
+		if session.UserID == userID && session.RevokedAt == nil && id != exceptID {
+			session.RevokedAt = &now
+			f.tokens.RevokeFamily(ctx, id)
+		}
+	}
+	return nil
+}
+
+// newTestSessions returns session and refresh token stores holding one
+// signed-in session of user 1 for each ID, with the refresh token "<id>-token".
+func newTestSessions(ids ...string) (*fakeSessionRepository, *fakeRefreshTokenRepository) {
+	tokens := &fakeRefreshTokenRepository{}
+	sessions := &fakeSessionRepository{sessions: map[string]*entities.Session{}, tokens: tokens}
+	for _, id := range ids {
+		sessions.sessions[id] = &entities.Session{ID: id, UserID: 1, ExpiresAt: time.Now().Add(time.Hour)}
+		tokens.Create(context.Background(), &entities.RefreshToken{
+			UserID:    1,
+			FamilyID:  id,
+			TokenHash: auth.HashToken(id + "-token"),
+			ExpiresAt: time.Now().Add(time.Hour),
+		})
+	}
+	return sessions, tokens
+}
+
+// fakePasswordResetRepository keeps reset tokens in memory.
+type fakePasswordResetRepository struct {
+	repositories.PasswordResetRepository
+	tokens []*entities.PasswordResetToken
+}
+
+func (f *fakePasswordResetRepository) Create(ctx context.Context, token *entities.PasswordResetToken) error {
+	token.ID = len(f.tokens) + 1
+	copied := *token
+	f.tokens = append(f.tokens, &copied)
+	return nil
+}
+
+func (f *fakePasswordResetRepository) GetByHash(ctx context.Context, tokenHash string) (*entities.PasswordResetToken, error) {
+	for _, token := range f.tokens {
+		if token.TokenHash == tokenHash {
+			copied := *token
+			return &copied, nil
+		}
+	}
+	return nil, repositories.ErrPasswordResetTokenNotFound
+}
+
+func (f *fakePasswordResetRepository) MarkUsed(ctx context.Context, id int) error {
+	token := f.tokens[id-1]
+	if token.UsedAt != nil {
+		return repositories.ErrPasswordResetTokenUsed
+	}
+	now := time.Now()
+	token.UsedAt = &now
+	return nil
+}
+
+func (f *fakePasswordResetRepository) InvalidateByUserId(ctx context.Context, userID int) error {
+	now := time.Now()
+	for _, token := range f.tokens {
+		if token.UserID == userID && token.UsedAt == nil {
+			token.UsedAt = &now
+		}
+	}
+	return nil
+}
+
+// fakeMailer keeps the messages it is asked to send.
+type fakeMailer struct {
+	sent []mail.Message
+}
+
+func (f *fakeMailer) Send(ctx context.Context, msg mail.Message) error {
+	f.sent = append(f.sent, msg)
+	return nil
+}
+
+func newTestAuthUsecase(userRepo *fakeUserRepository, sessions *fakeSessionRepository, tokens *fakeRefreshTokenRepository, resets repositories.PasswordResetRepository, mailer mail.Mailer, settings AuthSettings) AuthUsecase {
+	hasher, err := auth.NewPasswordHasher(auth.AlgorithmBcrypt, auth.DefaultArgon2Params, 4)
+	if err != nil {
+		panic(err)
+	}
+	settings.RefreshExpire = time.Hour
+	settings.PasswordResetExpire = time.Hour
+	return NewAuthUsecase(userRepo, tokens, sessions, resets, nil, auth.NewJWTService("test secret", time.Hour), hasher, nil, nil, nil, mailer, settings)
+}
+
+func TestRefreshReuseRevokesSession(t *testing.T) {
+	userRepo := &fakeUserRepository{users: map[int]*entities.User{
+		1: {ID: 1, Email: "user@example.com", Role: entities.RoleCustomer, IsActive: true},
+	}}
+	sessions, tokens := newTestSessions("phone", "laptop")
+	authUseCase := newTestAuthUsecase(userRepo, sessions, tokens, nil, nil, AuthSettings{})
+	ctx := context.Background()
+
+	rotated, err := authUseCase.Refresh(ctx, &dto.RefreshTokenReq{RefreshToken: "phone-token"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := authUseCase.Refresh(ctx, &dto.RefreshTokenReq{RefreshToken: "phone-token"}); !errors.Is(err, ErrRefreshReused) {
+		t.Fatalf("replaying a rotated token: got %v, want ErrRefreshReused", err)
+	}
+	if sessions.sessions["phone"].RevokedAt == nil {
+		t.Error("the session of the reused token was not revoked")
+	}
+	if active := tokens.active("phone"); len(active) != 0 {
+		t.Errorf("%d refresh tokens of the family are still active", len(active))
+	}
+	if _, err := authUseCase.Refresh(ctx, &dto.RefreshTokenReq{RefreshToken: rotated.RefreshToken}); !errors.Is(err, ErrInvalidRefresh) {
+		t.Errorf("the rotated token: got %v, want ErrInvalidRefresh", err)
+	}
+	if sessions.sessions["laptop"].RevokedAt != nil || len(tokens.active("laptop")) != 1 {
+		t.Error("other sessions of the user were revoked")
+	}
+}
+
+// sentResetToken returns the token of the password reset link mailed last.
+func sentResetToken(t *testing.T, mailer *fakeMailer, page string) string {
+	t.Helper()
+	if len(mailer.sent) == 0 {
+		t.Fatal("no email was sent")
+	}
+	body := mailer.sent[len(mailer.sent)-1].Body
+	start := strings.Index(body, page+"token=")
+	if start < 0 {
+		t.Fatalf("the email does not link to %s:\n%s", page, body)
+	}
+	link := body[start+len(page+"token="):]
+	return link[:strings.IndexByte(link, '\n')]
+}
+
+func TestPasswordResetLink(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings AuthSettings
+		wantPage string
+	}{
+		{name: "served form", settings: AuthSettings{BaseURL: "https://shop.test/"}, wantPage: "https://shop.test/auth/reset-password?"},
+		{name: "reset page", settings: AuthSettings{BaseURL: "https://shop.test", PasswordResetURL: "https://app.shop.test/reset"}, wantPage: "https://app.shop.test/reset?"},
+		{name: "reset page with query", settings: AuthSettings{PasswordResetURL: "https://app.shop.test/account?view=reset"}, wantPage: "https://app.shop.test/account?view=reset&"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := &fakeUserRepository{users: map[int]*entities.User{1: {ID: 1, Email: "user@example.com", IsActive: true}}}
+			mailer := &fakeMailer{}
+			authUseCase := newTestAuthUsecase(userRepo, nil, nil, &fakePasswordResetRepository{}, mailer, tt.settings)
+			if err := authUseCase.ForgotPassword(context.Background(), &dto.ForgotPasswordReq{Email: "user@example.com"}); err != nil {
+				t.Fatal(err)
+			}
+			if token := sentResetToken(t, mailer, tt.wantPage); token == "" {
+				t.Error("the link has no token")
+			}
+		})
+	}
+}
+
+func TestResetPasswordIsSingleUseAndSignsOut(t *testing.T) {
+	userRepo := &fakeUserRepository{users: map[int]*entities.User{1: {ID: 1, Email: "user@example.com", IsActive: true}}}
+	sessions, tokens := newTestSessions("phone", "laptop")
+	mailer := &fakeMailer{}
+	authUseCase := newTestAuthUsecase(userRepo, sessions, tokens, &fakePasswordResetRepository{}, mailer, AuthSettings{BaseURL: "https://shop.test"})
+	ctx := context.Background()
+	if err := authUseCase.ForgotPassword(ctx, &dto.ForgotPasswordReq{Email: "user@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	token := sentResetToken(t, mailer, "https://shop.test/auth/reset-password?")
+
+	if err := authUseCase.ResetPassword(ctx, &dto.ResetPasswordReq{Token: token, Password: "first-password"}); err != nil {
+		t.Fatal(err)
+	}
+	firstHash := userRepo.users[1].Password
+	if firstHash == "" {
+		t.Fatal("the password was not changed")
+	}
+	for id, session := range sessions.sessions {
+		if session.RevokedAt == nil {
+			t.Errorf("session %s was not revoked", id)
+		}
+		if active := tokens.active(id); len(active) != 0 {
+			t.Errorf("%d refresh tokens of session %s are still active", len(active), id)
+		}
+	}
+
+	err := authUseCase.ResetPassword(ctx, &dto.ResetPasswordReq{Token: token, Password: "second-password"})
+	if !errors.Is(err, ErrInvalidResetToken) {
+		t.Errorf("reusing the token: got %v, want ErrInvalidResetToken", err)
+	}
+	if userRepo.users[1].Password != firstHash {
+		t.Error("a used token changed the password again")
+	}
+}
@@ -1 +1,406 @@
-package usecases
\ No newline at end of file
+package usecases
+
+import (
+	"context"
+	"errors"
+	"mini-ecommerce/internal/domain/entities"
+	"mini-ecommerce/internal/domain/repositories"
+	"mini-ecommerce/internal/interfaces/http/dto"
+	"mini-ecommerce/pkg/logger"
+	"mini-ecommerce/pkg/utils"
+	"strings"
+	"time"
+)
+
+var (
+	ErrCategoryNotFound       = errors.New("category not found")
+	ErrParentCategoryNotFound = errors.New("parent category not found")
+	ErrCategoryExists         = errors.New("a category with this name already exists under the same parent")
+	ErrCategoryCycle          = errors.New("category cannot be moved below itself or one of its subcategories")
+	ErrCategoryInUse          = errors.New("category still has subcategories or products")
+	ErrCategoryOrderMismatch  = errors.New("ids must list every subcategory of the parent exactly once")
+)
+
+// With activeOnly, the lookups of CategoryUsecase treat a category as missing
+// unless it is shown in the tree: it and all of its ancestors are active.
+type CategoryUsecase interface {
+	GetById(ctx context.Context, id int, activeOnly bool) (*dto.CategoryRes, error)
+	// GetBySlug also resolves previous slugs; compare the returned slug with
+	// the requested one to detect a redirect.
+	GetBySlug(ctx context.Context, slug string, activeOnly bool) (*dto.CategoryRes, error)
+	List(ctx context.Context, req *dto.CategoryListReq) (*dto.CategoryListRes, error)
+	// Tree returns the categories nested under their parents; activeOnly
+	// leaves out inactive categories with everything below them.
+	Tree(ctx context.Context, activeOnly bool) ([]*dto.CategoryTreeRes, error)
+	// Subtree returns the category with its active descendants nested below it.
+	Subtree(ctx context.Context, id int, activeOnly bool) (*dto.CategoryTreeRes, error)
+	// Breadcrumbs returns the path from the root down to the category.
+	Breadcrumbs(ctx context.Context, id int, activeOnly bool) ([]*dto.BreadcrumbRes, error)
+	Create(ctx context.Context, req *dto.CategoryReq) (*dto.CategoryRes, error)
+	Update(ctx context.Context, actorID int, req *dto.UpdateCategoryReq) (*dto.CategoryRes, error)
+	// Reorder rewrites the sort order of one level of the tree.
+	Reorder(ctx context.Context, actorID int, req *dto.ReorderCategoriesReq) error
+	Delete(ctx context.Context, actorID, id int) error
+}
+
+type categoryUseCaseImpl struct {
+	categoryRepo repositories.CategoryRepository
+}
+
+// GetById implements CategoryUsecase.
+func (c *categoryUseCaseImpl) GetById(ctx context.Context, id int, activeOnly bool) (*dto.CategoryRes, error) {
+	if activeOnly {
+		path, err := c.visiblePath(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		return toCategoryRes(path[len(path)-1]), nil
+	}
+	category, err := c.getCategory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toCategoryRes(category), nil
+}
+
+// GetBySlug implements CategoryUsecase.
+func (c *categoryUseCaseImpl) GetBySlug(ctx context.Context, slug string, activeOnly bool) (*dto.CategoryRes, error) {
+	category, err := c.categoryRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, repositories.ErrCategoryNotFound) {
+			return nil, ErrCategoryNotFound
+		}
+		return nil, err
+	}
+	if activeOnly {
+		if _, err := c.visiblePath(ctx, category.ID); err != nil {
+			return nil, err
+		}
+	}
+	return toCategoryRes(category), nil
+}
+
+// List implements CategoryUsecase.
+func (c *categoryUseCaseImpl) List(ctx context.Context, req *dto.CategoryListReq) (*dto.CategoryListRes, error) {
+	page, limit := utils.NormalizePage(req.Page, req.Limit, utils.DefaultLimit)
+	categories, total, err := c.categoryRepo.List(ctx, repositories.CategoryFilter{
+		ParentID: req.ParentID,
+		RootOnly: req.RootOnly,
+		IsActive: req.IsActive,
+		Offset:   utils.Offset(page, limit),
+		Limit:    limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	res := &dto.CategoryListRes{
+		Categories: make([]*dto.CategoryRes, 0, len(categories)),
+		Pagination: utils.NewPagination(page, limit, total),
+	}
+	for _, category := range categories {
+		res.Categories = append(res.Categories, toCategoryRes(category))
+	}
+	return res, nil
+}
+
+// Tree implements CategoryUsecase.
+// Categories below an inactive parent are left out with it.
+func (c *categoryUseCaseImpl) Tree(ctx context.Context, activeOnly bool) ([]*dto.CategoryTreeRes, error) {
+	categories, err := c.categoryRepo.ListAll(ctx, activeOnly)
+	if err != nil {
+		return nil, err
+	}
+	return buildCategoryTree(categories, nil), nil
+}
+
+// Subtree implements CategoryUsecase.
+func (c *categoryUseCaseImpl) Subtree(ctx context.Context, id int, activeOnly bool) (*dto.CategoryTreeRes, error) {
+	if activeOnly {
+		if _, err := c.visiblePath(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+	categories, err := c.categoryRepo.ListSubtree(ctx, id, true)
+	if err != nil {
+		if errors.Is(err, repositories.ErrCategoryNotFound) {
+			return nil, ErrCategoryNotFound
+		}
+		return nil, err
+	}
+	// ListSubtree returns the root first; its own parent is not part of the result.
+	root := &dto.CategoryTreeRes{CategoryRes: toCategoryRes(categories[0])}
+	root.Children = buildCategoryTree(categories[1:], &categories[0].ID)
+	return root, nil
+}
+
+// Breadcrumbs implements CategoryUsecase.
+func (c *categoryUseCaseImpl) Breadcrumbs(ctx context.Context, id int, activeOnly bool) ([]*dto.BreadcrumbRes, error) {
+	categories, err := c.categoryRepo.ListAncestors(ctx, id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrCategoryNotFound) {
+			return nil, ErrCategoryNotFound
+		}
+		return nil, err
+	}
+	if activeOnly && !allActive(categories) {
+		return nil, ErrCategoryNotFound
+	}
+	res := make([]*dto.BreadcrumbRes, 0, len(categories))
+	for _, category := range categories {
+		res = append(res, &dto.BreadcrumbRes{ID: category.ID, Name: category.Name, Slug: category.Slug})
+	}
+	return res, nil
+}
+
+// Create implements CategoryUsecase.
+func (c *categoryUseCaseImpl) Create(ctx context.Context, req *dto.CategoryReq) (*dto.CategoryRes, error) {
+	category := &entities.Category{
+		ParentID:    req.ParentID,
+		Name:        strings.TrimSpace(req.Name),
+		Description: req.Description,
+		ImageURL:    req.ImageURL,
+		IsActive:    true,
+		SortOrder:   req.SortOrder,
+	}
+	if req.IsActive != nil {
+		category.IsActive = *req.IsActive
+	}
+	if err := c.ensureValidPlacement(ctx, category); err != nil {
+		return nil, err
+	}
+	if err := c.assignSlug(ctx, category, req.Slug); err != nil {
+		return nil, err
+	}
+	if err := c.categoryRepo.Create(ctx, category); err != nil {
+		return nil, err
+	}
+	return toCategoryRes(category), nil
+}
+
+// Update implements CategoryUsecase.
+func (c *categoryUseCaseImpl) Update(ctx context.Context, actorID int, req *dto.UpdateCategoryReq) (*dto.CategoryRes, error) {
+	category, err := c.getCategory(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	renamed := req.Name != nil && strings.TrimSpace(*req.Name) != category.Name
+	if req.ParentID != nil {
+		if *req.ParentID == 0 {
+			category.ParentID = nil
+		} else {
+			parentID := *req.ParentID
+			category.ParentID = &parentID
+		}
+	}
+	if req.Name != nil {
+		category.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Description != nil {
+		category.Description = *req.Description
+	}
+	if req.ImageURL != nil {
+		category.ImageURL = *req.ImageURL
+	}
+	if req.IsActive != nil {
+		category.IsActive = *req.IsActive
+	}
+	if req.SortOrder != nil {
+		category.SortOrder = *req.SortOrder
+	}
+	if err := c.ensureValidPlacement(ctx, category); err != nil {
+		return nil, err
+	}
+	switch {
+	case req.Slug != nil:
+		err = c.assignSlug(ctx, category, *req.Slug)
+	case renamed:
+		err = c.assignSlug(ctx, category, "")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := c.categoryRepo.Update(ctx, category); err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrCategoryCycle):
+			return nil, ErrCategoryCycle
+		case errors.Is(err, repositories.ErrCategoryNotFound):
+			return nil, ErrCategoryNotFound
+		}
+		return nil, err
+	}
+	logger.Infof("[CategoryUsecase] user %d updated category %d", actorID, category.ID)
+	return toCategoryRes(category), nil
+}
+
+// Reorder implements CategoryUsecase.
+func (c *categoryUseCaseImpl) Reorder(ctx context.Context, actorID int, req *dto.ReorderCategoriesReq) error {
+	if req.ParentID != nil {
+		if _, err := c.categoryRepo.GetById(ctx, *req.ParentID); err != nil {
+			if errors.Is(err, repositories.ErrCategoryNotFound) {
+				return ErrParentCategoryNotFound
+			}
+			return err
+		}
+	}
+	if err := c.categoryRepo.Reorder(ctx, req.ParentID, req.IDs); err != nil {
+		if errors.Is(err, repositories.ErrCategoryOrderMismatch) {
+			return ErrCategoryOrderMismatch
+		}
+		return err
+	}
+	logger.Infof("[CategoryUsecase] user %d reordered %d categories", actorID, len(req.IDs))
+	return nil
+}
+
+// Delete implements CategoryUsecase.
+// Only categories without subcategories and products can be deleted.
+func (c *categoryUseCaseImpl) Delete(ctx context.Context, actorID, id int) error {
+	if err := c.categoryRepo.Delete(ctx, id); err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrCategoryNotFound):
+			return ErrCategoryNotFound
+		case errors.Is(err, repositories.ErrCategoryHasChildren),
+			errors.Is(err, repositories.ErrCategoryHasProducts):
+			return ErrCategoryInUse
+		}
+		return err
+	}
+	logger.Infof("[CategoryUsecase] user %d deleted category %d", actorID, id)
+	return nil
+}
+
+func (c *categoryUseCaseImpl) getCategory(ctx context.Context, id int) (*entities.Category, error) {
+	category, err := c.categoryRepo.GetById(ctx, id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrCategoryNotFound) {
+			return nil, ErrCategoryNotFound
+		}
+		return nil, err
+	}
+	return category, nil
+}
+
+// visiblePath returns the path from the root down to the category, failing
+// with ErrCategoryNotFound when a category on it is inactive.
+func (c *categoryUseCaseImpl) visiblePath(ctx context.Context, id int) ([]*entities.Category, error) {
+	path, err := c.categoryRepo.ListAncestors(ctx, id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrCategoryNotFound) {
+			return nil, ErrCategoryNotFound
+		}
+		return nil, err
+	}
+	if !allActive(path) {
+		return nil, ErrCategoryNotFound
+	}
+	return path, nil
+}
+
+func allActive(categories []*entities.Category) bool {
+	for _, category := range categories {
+		if !category.IsActive {
+			return false
+		}
+	}
+	return true
+}
+
+// ensureValidPlacement checks that the parent exists and no sibling uses the
+// same name. Cycles are checked by the repository inside the move itself.
+func (c *categoryUseCaseImpl) ensureValidPlacement(ctx context.Context, category *entities.Category) error {
+	if category.ParentID != nil {
+		if *category.ParentID == category.ID {
+			return ErrCategoryCycle
+		}
+		if _, err := c.categoryRepo.GetById(ctx, *category.ParentID); err != nil {
+			if errors.Is(err, repositories.ErrCategoryNotFound) {
+				return ErrParentCategoryNotFound
+			}
+			return err
+		}
+	}
+	exists, err := c.categoryRepo.ExistsByName(ctx, category.ParentID, category.Name, category.ID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrCategoryExists
+	}
+	return nil
+}
+
+// assignSlug sets the requested slug, or one generated from the name when
+// requested is empty. The category's own current and previous slugs count as
+// free, so renaming back restores the old slug.
+func (c *categoryUseCaseImpl) assignSlug(ctx context.Context, category *entities.Category, requested string) error {
+	taken := func(ctx context.Context, slug string) (bool, error) {
+		return c.categoryRepo.SlugExists(ctx, slug, category.ID)
+	}
+	if requested != "" {
+		exists, err := taken(ctx, requested)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return ErrSlugTaken
+		}
+		category.Slug = requested
+		return nil
+	}
+	slug, err := uniqueSlug(ctx, category.Name, "category", taken)
+	if err != nil {
+		return err
+	}
+	category.Slug = slug
+	return nil
+}
+
+// buildCategoryTree nests the categories below parentID. The input must be
+// ordered for display; categories whose parent is missing are dropped.
+func buildCategoryTree(categories []*entities.Category, parentID *int) []*dto.CategoryTreeRes {
+	children := make(map[int][]*entities.Category)
+	var roots []*entities.Category
+	for _, category := range categories {
+		switch {
+		case category.ParentID == nil && parentID == nil,
+			category.ParentID != nil && parentID != nil && *category.ParentID == *parentID:
+			roots = append(roots, category)
+		case category.ParentID != nil:
+			children[*category.ParentID] = append(children[*category.ParentID], category)
+		}
+	}
+	var nest func(level []*entities.Category) []*dto.CategoryTreeRes
+	nest = func(level []*entities.Category) []*dto.CategoryTreeRes {
+		res := make([]*dto.CategoryTreeRes, 0, len(level))
+		for _, category := range level {
+			res = append(res, &dto.CategoryTreeRes{
+				CategoryRes: toCategoryRes(category),
+				Children:    nest(children[category.ID]),
+			})
+		}
+		return res
+	}
+	return nest(roots)
+}
+
+func toCategoryRes(category *entities.Category) *dto.CategoryRes {
+	return &dto.CategoryRes{
+		ID:           category.ID,
+		ParentID:     category.ParentID,
+		Name:         category.Name,
+		Slug:         category.Slug,
+		Description:  category.Description,
+		ImageURL:     category.ImageURL,
+		IsActive:     category.IsActive,
+		SortOrder:    category.SortOrder,
+		ProductCount: category.ProductCount,
+		CreatedAt:    category.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:    category.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func NewCategoryUseCase(categoryRepo repositories.CategoryRepository) CategoryUsecase {
+	return &categoryUseCaseImpl{
+		categoryRepo: categoryRepo,
+	}
+}
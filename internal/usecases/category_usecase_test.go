@@ -0,0 +1,211 @@
+package usecases
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"mini-ecommerce/internal/domain/entities"
+	"mini-ecommerce/internal/domain/repositories"
+	"mini-ecommerce/internal/interfaces/http/dto"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func intPtr(v int) *int {
+	return &v
+}
+
+// treeIDs renders a tree as nested ids, e.g. [1 [2 [] 3 []]].
+func treeIDs(nodes []*dto.CategoryTreeRes) []interface{} {
+	ids := make([]interface{}, 0, 2*len(nodes))
+	for _, node := range nodes {
+		ids = append(ids, node.ID, treeIDs(node.Children))
+	}
+	return ids
+}
+
+func TestBuildCategoryTree(t *testing.T) {
+	categories := []*entities.Category{
+		{ID: 1, Name: "Electronics"},
+		{ID: 5, Name: "Books"},
+		{ID: 2, ParentID: intPtr(1), Name: "Phones"},
+		{ID: 3, ParentID: intPtr(1), Name: "Laptops"},
+		{ID: 4, ParentID: intPtr(2), Name: "Cases"},
+		// The parent of an orphan was filtered out, e.g. for being inactive.
+		{ID: 6, ParentID: intPtr(99), Name: "Orphan"},
+	}
+	tests := []struct {
+		name     string
+		parentID *int
+		want     []interface{}
+	}{
+		{
+			name: "from the roots",
+			want: []interface{}{
+				1, []interface{}{
+					2, []interface{}{4, []interface{}{}},
+					3, []interface{}{},
+				},
+				5, []interface{}{},
+			},
+		},
+		{
+			name:     "below a category",
+			parentID: intPtr(1),
+			want: []interface{}{
+				2, []interface{}{4, []interface{}{}},
+				3, []interface{}{},
+			},
+		},
+		{
+			name:     "leaf",
+			parentID: intPtr(4),
+			want:     []interface{}{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := treeIDs(buildCategoryTree(categories, tt.parentID)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("buildCategoryTree = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeCategoryRepository serves lookups from a fixed set of categories.
+type fakeCategoryRepository struct {
+	repositories.CategoryRepository
+	categories map[int]*entities.Category
+}
+
+func (f *fakeCategoryRepository) GetById(ctx context.Context, id int) (*entities.Category, error) {
+	category, ok := f.categories[id]
+	if !ok {
+		return nil, repositories.ErrCategoryNotFound
+	}
+	return category, nil
+}
+
+func (f *fakeCategoryRepository) GetBySlug(ctx context.Context, slug string) (*entities.Category, error) {
+	for _, category := range f.categories {
+		if category.Slug == slug {
+			return category, nil
+		}
+	}
+	return nil, repositories.ErrCategoryNotFound
+}
+
+func (f *fakeCategoryRepository) ListAncestors(ctx context.Context, id int) ([]*entities.Category, error) {
+	var path []*entities.Category
+	for next := &id; next != nil; {
+		category, ok := f.categories[*next]
+		if !ok {
+			return nil, repositories.ErrCategoryNotFound
+		}
+		path = append([]*entities.Category{category}, path...)
+		next = category.ParentID
+	}
+	return path, nil
+}
+
+func (f *fakeCategoryRepository) ListSubtree(ctx context.Context, id int, activeOnly bool) ([]*entities.Category, error) {
+	category, ok := f.categories[id]
+	if !ok {
+		return nil, repositories.ErrCategoryNotFound
+	}
+	return []*entities.Category{category}, nil
+}
+
+func (f *fakeCategoryRepository) ListAll(ctx context.Context, activeOnly bool) ([]*entities.Category, error) {
+	categories := make([]*entities.Category, 0, len(f.categories))
+	for _, category := range f.categories {
+		if category.IsActive || !activeOnly {
+			categories = append(categories, category)
+		}
+	}
+	// The repository orders by sort order, then name.
+	slices.SortFunc(categories, func(a, b *entities.Category) int {
+		return cmp.Or(cmp.Compare(a.SortOrder, b.SortOrder), cmp.Compare(a.Name, b.Name))
+	})
+	return categories, nil
+}
+
+func TestCategoryVisibility(t *testing.T) {
+	repo := &fakeCategoryRepository{categories: map[int]*entities.Category{
+		1: {ID: 1, Name: "Electronics", Slug: "electronics", IsActive: true},
+		2: {ID: 2, ParentID: intPtr(1), Name: "Phones", Slug: "phones", IsActive: true},
+		3: {ID: 3, Name: "Archive", Slug: "archive", IsActive: false},
+		4: {ID: 4, ParentID: intPtr(3), Name: "Old phones", Slug: "old-phones", IsActive: true},
+	}}
+	categoryUseCase := NewCategoryUseCase(repo)
+	ctx := context.Background()
+
+	lookups := map[string]func(id int, activeOnly bool) error{
+		"GetById": func(id int, activeOnly bool) error {
+			_, err := categoryUseCase.GetById(ctx, id, activeOnly)
+			return err
+		},
+		"GetBySlug": func(id int, activeOnly bool) error {
+			_, err := categoryUseCase.GetBySlug(ctx, repo.categories[id].Slug, activeOnly)
+			return err
+		},
+		"Subtree": func(id int, activeOnly bool) error {
+			_, err := categoryUseCase.Subtree(ctx, id, activeOnly)
+			return err
+		},
+		"Breadcrumbs": func(id int, activeOnly bool) error {
+			_, err := categoryUseCase.Breadcrumbs(ctx, id, activeOnly)
+			return err
+		},
+	}
+	tests := []struct {
+		name       string
+		id         int
+		activeOnly bool
+		wantErr    error
+	}{
+		{name: "active below active", id: 2, activeOnly: true},
+		{name: "inactive", id: 3, activeOnly: true, wantErr: ErrCategoryNotFound},
+		{name: "active below inactive", id: 4, activeOnly: true, wantErr: ErrCategoryNotFound},
+		{name: "inactive for managers", id: 3},
+		{name: "below inactive for managers", id: 4},
+	}
+	for lookup, find := range lookups {
+		for _, tt := range tests {
+			t.Run(lookup+" "+tt.name, func(t *testing.T) {
+				if err := find(tt.id, tt.activeOnly); !errors.Is(err, tt.wantErr) {
+					t.Errorf("%s(%d, %v) = %v, want %v", lookup, tt.id, tt.activeOnly, err, tt.wantErr)
+				}
+			})
+		}
+	}
+}
+
+func TestCategoryTreeVisibility(t *testing.T) {
+	repo := &fakeCategoryRepository{categories: map[int]*entities.Category{
+		1: {ID: 1, Name: "Electronics", IsActive: true},
+		2: {ID: 2, ParentID: intPtr(1), Name: "Phones", IsActive: true},
+		3: {ID: 3, Name: "Archive", IsActive: false},
+		4: {ID: 4, ParentID: intPtr(3), Name: "Old phones", IsActive: true},
+	}}
+	categoryUseCase := NewCategoryUseCase(repo)
+	ctx := context.Background()
+
+	tests := []struct {
+		activeOnly bool
+		want       []interface{}
+	}{
+		{activeOnly: true, want: []interface{}{1, []interface{}{2, []interface{}{}}}},
+		{activeOnly: false, want: []interface{}{3, []interface{}{4, []interface{}{}}, 1, []interface{}{2, []interface{}{}}}},
+	}
+	for _, tt := range tests {
+		tree, err := categoryUseCase.Tree(ctx, tt.activeOnly)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := treeIDs(tree); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Tree(%v) = %v, want %v", tt.activeOnly, got, tt.want)
+		}
+	}
+}
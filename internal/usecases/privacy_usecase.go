@@ -0,0 +1,207 @@
+package usecases
+
+import (
+	"context"
+	"mini-ecommerce/internal/domain/entities"
+	"mini-ecommerce/internal/domain/repositories"
+	"mini-ecommerce/internal/infrastructure/audit"
+	"mini-ecommerce/internal/infrastructure/auth"
+	"mini-ecommerce/internal/interfaces/http/dto"
+	"mini-ecommerce/pkg/logger"
+	"strings"
+	"time"
+	"unicode"
+)
+
+type PrivacyUsecase interface {
+	// Export collects everything stored about the user for download.
+	Export(ctx context.Context, userID int) (*dto.PersonalDataExportRes, error)
+	// Erase anonymizes the user's own account after confirming the password,
+	// and a two-factor code when two-factor authentication is enabled.
+	Erase(ctx context.Context, req *dto.EraseAccountReq) error
+}
+
+type privacyUseCaseImpl struct {
+	userRepo         repositories.UserRepository
+	addressRepo      repositories.AddressRepository
+	personalDataRepo repositories.PersonalDataRepository
+	twoFactor        TwoFactorUsecase
+	hasher           auth.PasswordHasher
+	auditor          audit.Recorder
+}
+
+// Export implements PrivacyUsecase.
+func (p *privacyUseCaseImpl) Export(ctx context.Context, userID int) (*dto.PersonalDataExportRes, error) {
+	user, err := p.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	addresses, err := p.addressRepo.ListByUserId(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	data, err := p.personalDataRepo.GetByUserId(ctx, userID)
+	if err != nil {
+		logger.Error(err, "[ErrPrivacyUsecase-1] failed to collect personal data")
+		return nil, err
+	}
+	res := &dto.PersonalDataExportRes{
+		ExportedAt: time.Now().Format(time.RFC3339),
+		Profile:    toUserRes(user),
+		Addresses:  toAddressResList(addresses),
+		Orders:     make([]*dto.ExportedOrderRes, 0, len(data.Orders)),
+		Payments:   make([]*dto.ExportedPaymentRes, 0, len(data.Payments)),
+		Reviews:    make([]*dto.ExportedReviewRes, 0, len(data.Reviews)),
+	}
+	for _, order := range data.Orders {
+		res.Orders = append(res.Orders, toExportedOrderRes(order, data.OrderItems[order.ID]))
+	}
+	for _, payment := range data.Payments {
+		res.Payments = append(res.Payments, &dto.ExportedPaymentRes{
+			ID:             payment.ID,
+			OrderID:        payment.OrderID,
+			Amount:         payment.Amount,
+			PaymentMethod:  payment.PaymentMethod,
+			Status:         payment.Status,
+			TransactionID:  maskValue(payment.TransactionID),
+			PaymentDetails: maskDetails(payment.PaymentDetails),
+			CreatedAt:      payment.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	for _, review := range data.Reviews {
+		res.Reviews = append(res.Reviews, &dto.ExportedReviewRes{
+			ProductID:  review.ProductID,
+			Rating:     review.Rating,
+			Title:      review.Title,
+			ReviewText: review.ReviewText,
+			CreatedAt:  review.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	p.auditor.Record(ctx, audit.Event{
+		Type:    audit.EventPersonalDataExported,
+		UserID:  userID,
+		Subject: user.Email,
+	})
+	return res, nil
+}
+
+// Erase implements PrivacyUsecase.
+func (p *privacyUseCaseImpl) Erase(ctx context.Context, req *dto.EraseAccountReq) error {
+	user, err := p.getUser(ctx, req.UserID)
+	if err != nil {
+		return err
+	}
+	if !p.hasher.Verify(req.Password, user.Password) {
+		return ErrInvalidCurrentPassword
+	}
+	if user.TwoFactorEnabled {
+		if req.Code == "" {
+			return ErrInvalidTwoFactorCode
+		}
+		if err := p.twoFactor.Verify(ctx, user.ID, req.Code); err != nil {
+			return err
+		}
+	}
+	if err := p.userRepo.Anonymize(ctx, user.ID); err != nil {
+		if err.Error() == "user not found" {
+			return ErrUserNotFound
+		}
+		logger.Error(err, "[ErrPrivacyUsecase-2] failed to anonymize user")
+		return err
+	}
+	p.auditor.Record(ctx, audit.Event{
+		Type:   audit.EventAccountErased,
+		UserID: user.ID,
+	})
+	return nil
+}
+
+func (p *privacyUseCaseImpl) getUser(ctx context.Context, id int) (*entities.User, error) {
+	user, err := p.userRepo.GetById(ctx, id)
+	if err != nil {
+		if err.Error() == "user not found" {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	if user.AnonymizedAt != nil {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+func toExportedOrderRes(order *entities.Order, items []*entities.OrderItem) *dto.ExportedOrderRes {
+	res := &dto.ExportedOrderRes{
+		ID:              order.ID,
+		Status:          order.Status,
+		Subtotal:        order.Subtotal,
+		ShippingCost:    order.ShippingCost,
+		TaxAmount:       order.TaxAmount,
+		TotalAmount:     order.TotalAmount,
+		PaymentMethod:   order.PaymentMethod,
+		PaymentStatus:   order.PaymentStatus,
+		ShippingAddress: order.ShippingAddress,
+		TrackingNumber:  order.TrackingNumber,
+		Notes:           order.Notes,
+		Items:           make([]*dto.ExportedOrderItemRes, 0, len(items)),
+		CreatedAt:       order.CreatedAt.Format(time.RFC3339),
+	}
+	for _, item := range items {
+		res.Items = append(res.Items, &dto.ExportedOrderItemRes{
+			ProductID:   item.ProductID,
+			ProductName: item.ProductName,
+			Quantity:    item.Quantity,
+			UnitPrice:   item.UnitPrice,
+			TotalPrice:  item.TotalPrice,
+		})
+	}
+	return res
+}
+
+// maskDetails masks every value that contains digits, such as card or
+// account numbers, keeping only the last four characters. Payment details are
+// stored masked already; this guards against gateways that echo more.
+func maskDetails(details map[string]interface{}) map[string]interface{} {
+	if details == nil {
+		return nil
+	}
+	masked := make(map[string]interface{}, len(details))
+	for key, value := range details {
+		switch v := value.(type) {
+		case string:
+			if strings.IndexFunc(v, unicode.IsDigit) >= 0 {
+				masked[key] = maskValue(v)
+			} else {
+				masked[key] = v
+			}
+		case map[string]interface{}:
+			masked[key] = maskDetails(v)
+		case float64:
+			// Numbers such as amounts are not identifying.
+			masked[key] = v
+		case bool, nil:
+			masked[key] = v
+		default:
+			masked[key] = "****"
+		}
+	}
+	return masked
+}
+
+func maskValue(value string) string {
+	if len(value) <= 4 {
+		return value
+	}
+	return strings.Repeat("*", len(value)-4) + value[len(value)-4:]
+}
+
+func NewPrivacyUseCase(userRepo repositories.UserRepository, addressRepo repositories.AddressRepository, personalDataRepo repositories.PersonalDataRepository, twoFactor TwoFactorUsecase, hasher auth.PasswordHasher, auditor audit.Recorder) PrivacyUsecase {
+	return &privacyUseCaseImpl{
+		userRepo:         userRepo,
+		addressRepo:      addressRepo,
+		personalDataRepo: personalDataRepo,
+		twoFactor:        twoFactor,
+		hasher:           hasher,
+		auditor:          auditor,
+	}
+}
@@ -0,0 +1,116 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mini-ecommerce/internal/domain/entities"
+	"mini-ecommerce/internal/infrastructure/audit"
+	"mini-ecommerce/internal/infrastructure/auth"
+	"mini-ecommerce/internal/interfaces/http/dto"
+	"testing"
+	"time"
+)
+
+// erasingUserRepository anonymizes users in memory like the real repository:
+// the row stays with its identifying fields replaced.
+type erasingUserRepository struct {
+	*fakeUserRepository
+	anonymized int
+}
+
+func (f *erasingUserRepository) Anonymize(ctx context.Context, id int) error {
+	user, ok := f.users[id]
+	if !ok || user.AnonymizedAt != nil {
+		return errors.New("user not found")
+	}
+	now := time.Now()
+	user.Email = fmt.Sprintf("deleted-user-%d@anonymized.invalid", id)
+	user.Name = "Deleted User"
+	user.Phone = ""
+	user.Password = ""
+	user.IsActive = false
+	user.TwoFactorEnabled = false
+	user.AnonymizedAt = &now
+	f.anonymized++
+	return nil
+}
+
+// fakeTwoFactorUsecase accepts a single code.
+type fakeTwoFactorUsecase struct {
+	TwoFactorUsecase
+	code string
+}
+
+func (f *fakeTwoFactorUsecase) Verify(ctx context.Context, userID int, code string) error {
+	if code != f.code {
+		return ErrInvalidTwoFactorCode
+	}
+	return nil
+}
+
+type recordingAuditor struct {
+	events []audit.Event
+}
+
+func (r *recordingAuditor) Record(ctx context.Context, event audit.Event) {
+	r.events = append(r.events, event)
+}
+
+func TestPrivacyErase(t *testing.T) {
+	hasher, err := auth.NewPasswordHasher(auth.AlgorithmBcrypt, auth.DefaultArgon2Params, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, err := hasher.Hash("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	userRepo := &erasingUserRepository{fakeUserRepository: &fakeUserRepository{users: map[int]*entities.User{
+		1: {ID: 1, Email: "jane@example.com", Name: "Jane Doe", Phone: "+14155552671", Password: hash, IsActive: true, TwoFactorEnabled: true},
+	}}}
+	auditor := &recordingAuditor{}
+	privacyUseCase := NewPrivacyUseCase(userRepo, nil, nil, &fakeTwoFactorUsecase{code: "123456"}, hasher, auditor)
+	ctx := context.Background()
+
+	rejected := []struct {
+		name    string
+		req     dto.EraseAccountReq
+		wantErr error
+	}{
+		{name: "wrong password", req: dto.EraseAccountReq{UserID: 1, Password: "wrong", Code: "123456"}, wantErr: ErrInvalidCurrentPassword},
+		{name: "missing code", req: dto.EraseAccountReq{UserID: 1, Password: "correct horse"}, wantErr: ErrInvalidTwoFactorCode},
+		{name: "wrong code", req: dto.EraseAccountReq{UserID: 1, Password: "correct horse", Code: "654321"}, wantErr: ErrInvalidTwoFactorCode},
+	}
+	for _, tt := range rejected {
+		if err := privacyUseCase.Erase(ctx, &tt.req); !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: Erase() = %v, want %v", tt.name, err, tt.wantErr)
+		}
+	}
+	if userRepo.anonymized != 0 {
+		t.Fatal("the account was erased without confirmation")
+	}
+
+	req := &dto.EraseAccountReq{UserID: 1, Password: "correct horse", Code: "123456"}
+	if err := privacyUseCase.Erase(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	user := userRepo.users[1]
+	if user.Email == "jane@example.com" || user.Name == "Jane Doe" || user.Phone != "" || user.Password != "" || user.IsActive {
+		t.Errorf("erased user %+v still holds personal data or can sign in", user)
+	}
+	if len(auditor.events) != 1 || auditor.events[0].Type != audit.EventAccountErased || auditor.events[0].UserID != 1 {
+		t.Errorf("audit events %+v, want one account erasure of user 1", auditor.events)
+	}
+
+	// Erasing again, or exporting, treats the account as gone.
+	if err := privacyUseCase.Erase(ctx, req); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("erasing again = %v, want ErrUserNotFound", err)
+	}
+	if _, err := privacyUseCase.Export(ctx, 1); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("exporting an erased account = %v, want ErrUserNotFound", err)
+	}
+	if userRepo.anonymized != 1 || len(auditor.events) != 1 {
+		t.Errorf("erasing again anonymized %d times and recorded %d events, want no change", userRepo.anonymized, len(auditor.events))
+	}
+}
@@ -0,0 +1,453 @@
+package usecases
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mini-ecommerce/internal/interfaces/http/dto"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Formats of product import and export files.
+const (
+	ProductFileCSV   = "csv"
+	ProductFileJSONL = "jsonl"
+)
+
+const (
+	// listSeparator separates the image URLs and option names of a CSV cell
+	listSeparator = "|"
+	// maxProductLine caps a JSON Lines row, generous for a full description
+	maxProductLine = 1 << 20
+)
+
+var ErrInvalidProductFile = errors.New("invalid product file")
+
+// productFileColumns are the CSV columns in export order; imports accept
+// them in any order and may leave out all but sku.
+var productFileColumns = []string{
+	"sku", "parent_sku", "name", "slug", "description", "price", "stock_quantity",
+	"category", "is_active", "weight", "specifications", "dimensions", "images",
+	"option_names", "options",
+}
+
+// ProductFileFormat returns the format matching a file name's extension, or
+// an empty string when there is none.
+func ProductFileFormat(name string) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv":
+		return ProductFileCSV
+	case ".jsonl", ".ndjson":
+		return ProductFileJSONL
+	}
+	return ""
+}
+
+// productRowReader reads the rows of an import file. Next returns the row
+// number and either the row or the problems found while parsing it; it
+// returns io.EOF after the last row.
+type productRowReader interface {
+	Next() (int, *dto.ProductFileRow, map[string]string, error)
+}
+
+func newProductRowReader(format string, r io.Reader) (productRowReader, error) {
+	switch format {
+	case ProductFileCSV:
+		return newCSVRowReader(r)
+	case ProductFileJSONL:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxProductLine)
+		return &jsonlRowReader{scanner: scanner}, nil
+	}
+	return nil, ErrUnsupportedProductFormat
+}
+
+type csvRowReader struct {
+	reader  *csv.Reader
+	columns []string
+	row     int
+}
+
+// newCSVRowReader reads the header, which names the column of every field.
+func newCSVRowReader(r io.Reader) (*csvRowReader, error) {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: the file is empty", ErrInvalidProductFile)
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidProductFile, err)
+	}
+	columns := make([]string, len(header))
+	seen := make(map[string]bool, len(header))
+	for i, name := range header {
+		if i == 0 {
+			// Spreadsheets often start UTF-8 files with a byte order mark.
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch {
+		case !slices.Contains(productFileColumns, name):
+			return nil, fmt.Errorf("%w: unknown column %q", ErrInvalidProductFile, name)
+		case seen[name]:
+			return nil, fmt.Errorf("%w: column %q appears twice", ErrInvalidProductFile, name)
+		}
+		seen[name] = true
+		columns[i] = name
+	}
+	if !seen["sku"] {
+		return nil, fmt.Errorf("%w: the sku column is required", ErrInvalidProductFile)
+	}
+	return &csvRowReader{reader: reader, columns: columns, row: 1}, nil
+}
+
+func (c *csvRowReader) Next() (int, *dto.ProductFileRow, map[string]string, error) {
+	for {
+		record, err := c.reader.Read()
+		if errors.Is(err, io.EOF) {
+			return 0, nil, nil, io.EOF
+		}
+		c.row++
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			// The reader continues with the next record after a malformed one.
+			return c.row, nil, map[string]string{"row": parseErr.Err.Error()}, nil
+		}
+		if err != nil {
+			return c.row, nil, nil, err
+		}
+		// Spreadsheets pad files with empty rows.
+		if isBlankRecord(record) {
+			continue
+		}
+		row := &dto.ProductFileRow{}
+		problems := make(map[string]string)
+		for i, column := range c.columns {
+			if err := setProductCell(row, column, strings.TrimSpace(record[i])); err != nil {
+				problems[column] = err.Error()
+			}
+		}
+		if len(problems) > 0 {
+			return c.row, row, problems, nil
+		}
+		return c.row, row, nil, nil
+	}
+}
+
+func isBlankRecord(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// setProductCell parses a CSV cell into the row; blank cells stay nil.
+func setProductCell(row *dto.ProductFileRow, column, value string) error {
+	if value == "" {
+		return nil
+	}
+	var err error
+	switch column {
+	case "sku":
+		row.SKU = value
+	case "parent_sku":
+		row.ParentSKU = &value
+	case "name":
+		row.Name = &value
+	case "slug":
+		row.Slug = &value
+	case "description":
+		row.Description = &value
+	case "category":
+		row.Category = &value
+	case "price":
+		row.Price, err = parseFloatCell(column, value)
+	case "weight":
+		row.Weight, err = parseFloatCell(column, value)
+	case "stock_quantity":
+		quantity, parseErr := strconv.Atoi(value)
+		if parseErr != nil {
+			return fmt.Errorf("%s must be a whole number", column)
+		}
+		row.StockQuantity = &quantity
+	case "is_active":
+		active, parseErr := parseBoolCell(value)
+		if parseErr != nil {
+			return fmt.Errorf("%s must be true or false", column)
+		}
+		row.IsActive = &active
+	case "specifications":
+		row.Specifications, err = parseObjectCell(column, value)
+	case "dimensions":
+		row.Dimensions, err = parseObjectCell(column, value)
+	case "images":
+		row.Images = splitListCell(value)
+	case "option_names":
+		row.OptionNames = splitListCell(value)
+	case "options":
+		if json.Unmarshal([]byte(value), &row.Options) != nil || row.Options == nil {
+			return fmt.Errorf("%s must be a JSON object of strings", column)
+		}
+	}
+	return err
+}
+
+// splitListCell splits a CSV cell holding a list, leaving out blank items.
+func splitListCell(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, listSeparator) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseBoolCell also accepts the yes and no of spreadsheet checkboxes.
+func parseBoolCell(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "yes":
+		return true, nil
+	case "no":
+		return false, nil
+	}
+	return strconv.ParseBool(value)
+}
+
+func parseFloatCell(column, value string) (*float64, error) {
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be a number", column)
+	}
+	return &number, nil
+}
+
+func parseObjectCell(column, value string) (*map[string]interface{}, error) {
+	var object map[string]interface{}
+	if err := json.Unmarshal([]byte(value), &object); err != nil || object == nil {
+		return nil, fmt.Errorf("%s must be a JSON object", column)
+	}
+	return &object, nil
+}
+
+type jsonlRowReader struct {
+	scanner *bufio.Scanner
+	row     int
+}
+
+func (j *jsonlRowReader) Next() (int, *dto.ProductFileRow, map[string]string, error) {
+	for j.scanner.Scan() {
+		j.row++
+		line := bytes.TrimSpace(j.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		decoder := json.NewDecoder(bytes.NewReader(line))
+		decoder.DisallowUnknownFields()
+		row := &dto.ProductFileRow{}
+		if err := decoder.Decode(row); err != nil {
+			return j.row, nil, jsonRowProblems(err), nil
+		}
+		if decoder.More() {
+			return j.row, nil, map[string]string{"row": "a line must hold a single JSON object"}, nil
+		}
+		clearBlankFields(row)
+		return j.row, row, nil, nil
+	}
+	if err := j.scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return j.row + 1, nil, nil, fmt.Errorf("%w: line %d is longer than %d bytes", ErrInvalidProductFile, j.row+1, maxProductLine)
+		}
+		return j.row + 1, nil, nil, err
+	}
+	return 0, nil, nil, io.EOF
+}
+
+// jsonRowProblems describes why a line could not be decoded.
+func jsonRowProblems(err error) map[string]string {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		// A wrong option value is reported for the options as a whole.
+		column, _, nested := strings.Cut(typeErr.Field, ".")
+		if nested {
+			return map[string]string{column: column + " must be an object of strings"}
+		}
+		return map[string]string{column: column + " must be " + describeJSONType(typeErr.Type)}
+	}
+	return map[string]string{"row": strings.TrimPrefix(err.Error(), "json: ")}
+}
+
+func describeJSONType(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Int:
+		return "a whole number"
+	case reflect.Float64:
+		return "a number"
+	case reflect.Bool:
+		return "true or false"
+	case reflect.Map:
+		return "an object"
+	case reflect.Slice:
+		return "a list of strings"
+	}
+	return "a string"
+}
+
+// clearBlankFields treats blank strings and lists like missing ones, as
+// blank CSV cells are.
+func clearBlankFields(row *dto.ProductFileRow) {
+	row.SKU = strings.TrimSpace(row.SKU)
+	for _, field := range []**string{&row.ParentSKU, &row.Name, &row.Slug, &row.Description, &row.Category} {
+		if *field == nil {
+			continue
+		}
+		if value := strings.TrimSpace(**field); value != "" {
+			*field = &value
+		} else {
+			*field = nil
+		}
+	}
+	row.Images = trimList(row.Images)
+	row.OptionNames = trimList(row.OptionNames)
+}
+
+// trimList trims the items of a list and leaves out blank ones; an empty
+// list becomes nil.
+func trimList(items []string) []string {
+	var trimmed []string
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			trimmed = append(trimmed, item)
+		}
+	}
+	return trimmed
+}
+
+// productRowWriter writes the rows of an export file.
+type productRowWriter interface {
+	Write(row *dto.ProductFileRow) error
+	// Flush writes buffered rows through to the underlying writer.
+	Flush() error
+}
+
+func newProductRowWriter(format string, w io.Writer) (productRowWriter, error) {
+	switch format {
+	case ProductFileCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(productFileColumns); err != nil {
+			return nil, err
+		}
+		return &csvRowWriter{writer: writer}, nil
+	case ProductFileJSONL:
+		encoder := json.NewEncoder(w)
+		encoder.SetEscapeHTML(false)
+		return &jsonlRowWriter{encoder: encoder}, nil
+	}
+	return nil, ErrUnsupportedProductFormat
+}
+
+type csvRowWriter struct {
+	writer *csv.Writer
+}
+
+func (c *csvRowWriter) Write(row *dto.ProductFileRow) error {
+	record := make([]string, 0, len(productFileColumns))
+	record = append(record,
+		row.SKU,
+		stringCell(row.ParentSKU),
+		stringCell(row.Name),
+		stringCell(row.Slug),
+		stringCell(row.Description),
+		floatCell(row.Price),
+		intCell(row.StockQuantity),
+		stringCell(row.Category),
+		boolCell(row.IsActive),
+		floatCell(row.Weight),
+		objectCell(row.Specifications),
+		objectCell(row.Dimensions),
+		strings.Join(row.Images, listSeparator),
+		strings.Join(row.OptionNames, listSeparator),
+		optionsCell(row.Options),
+	)
+	return c.writer.Write(record)
+}
+
+func (c *csvRowWriter) Flush() error {
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+type jsonlRowWriter struct {
+	encoder *json.Encoder
+}
+
+func (j *jsonlRowWriter) Write(row *dto.ProductFileRow) error {
+	return j.encoder.Encode(row)
+}
+
+func (j *jsonlRowWriter) Flush() error {
+	return nil
+}
+
+func stringCell(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func floatCell(value *float64) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*value, 'f', -1, 64)
+}
+
+func intCell(value *int) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.Itoa(*value)
+}
+
+func boolCell(value *bool) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatBool(*value)
+}
+
+func optionsCell(value map[string]string) string {
+	if len(value) == 0 {
+		return ""
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+	return string(encoded)
+}
+
+func objectCell(value *map[string]interface{}) string {
+	if value == nil || len(*value) == 0 {
+		return ""
+	}
+	encoded, err := json.Marshal(*value)
+	if err != nil {
+		return ""
+	}
+	return string(encoded)
+}
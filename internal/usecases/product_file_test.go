@@ -0,0 +1,242 @@
+package usecases
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mini-ecommerce/internal/interfaces/http/dto"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// readRow is one result of a productRowReader.
+type readRow struct {
+	number   int
+	row      *dto.ProductFileRow
+	problems map[string]string
+}
+
+// readRows reads every row of an import file.
+func readRows(t *testing.T, format, content string) []readRow {
+	t.Helper()
+	reader, err := newProductRowReader(format, strings.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rows []readRow
+	for {
+		number, row, problems, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return rows
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		rows = append(rows, readRow{number, row, problems})
+	}
+}
+
+func stringPtr(v string) *string {
+	return &v
+}
+
+func TestCSVRowReaderHeader(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "empty", content: "", wantErr: "the file is empty"},
+		{name: "unknown column", content: "sku,colour\n", wantErr: `unknown column "colour"`},
+		{name: "repeated column", content: "sku,name,Name\n", wantErr: `column "name" appears twice`},
+		{name: "no sku", content: "name,price\n", wantErr: "the sku column is required"},
+		{name: "byte order mark", content: "\ufeffSKU , Name\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newProductRowReader(ProductFileCSV, strings.NewReader(tt.content))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidProductFile) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("got %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCSVRowReaderRows(t *testing.T) {
+	content := "sku,name,price,stock_quantity,is_active,images,option_names,specifications\n" +
+		"A-1, Lamp ,19.5,3,yes,https://x.test/a.jpg | https://x.test/b.jpg,size|color,\"{\"\"watts\"\": 40}\"\n" +
+		",,,,,,,\n" +
+		"A-2,,cheap,-,maybe,,,[1]\n" +
+		"A-3,\"unclosed\n"
+	rows := readRows(t, ProductFileCSV, content)
+	if len(rows) != 3 {
+		t.Fatalf("got %d rows, want 3", len(rows))
+	}
+
+	active := true
+	price, quantity := 19.5, 3
+	want := &dto.ProductFileRow{
+		SKU:            "A-1",
+		Name:           stringPtr("Lamp"),
+		Price:          &price,
+		StockQuantity:  &quantity,
+		IsActive:       &active,
+		Images:         []string{"https://x.test/a.jpg", "https://x.test/b.jpg"},
+		OptionNames:    []string{"size", "color"},
+		Specifications: &map[string]interface{}{"watts": float64(40)},
+	}
+	if rows[0].number != 2 || rows[0].problems != nil || !reflect.DeepEqual(rows[0].row, want) {
+		t.Errorf("row 2 = %d %+v %v, want %+v", rows[0].number, rows[0].row, rows[0].problems, want)
+	}
+
+	// The blank row is skipped but still numbered.
+	wantProblems := map[string]string{
+		"price":          "price must be a number",
+		"stock_quantity": "stock_quantity must be a whole number",
+		"is_active":      "is_active must be true or false",
+		"specifications": "specifications must be a JSON object",
+	}
+	if rows[1].number != 4 || !reflect.DeepEqual(rows[1].problems, wantProblems) {
+		t.Errorf("row 4 = %d %v, want %v", rows[1].number, rows[1].problems, wantProblems)
+	}
+	if rows[2].number != 5 || rows[2].row != nil || rows[2].problems["row"] == "" {
+		t.Errorf("row 5 = %d %+v %v, want a row problem", rows[2].number, rows[2].row, rows[2].problems)
+	}
+}
+
+func TestCSVRowReaderVariantRow(t *testing.T) {
+	rows := readRows(t, ProductFileCSV, "sku,parent_sku,options\nA-1-M,A-1,\"{\"\"size\"\": \"\"M\"\"}\"\nA-1-L,A-1,\"{\"\"size\"\": 1}\"\n")
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2", len(rows))
+	}
+	want := &dto.ProductFileRow{SKU: "A-1-M", ParentSKU: stringPtr("A-1"), Options: map[string]string{"size": "M"}}
+	if rows[0].problems != nil || !reflect.DeepEqual(rows[0].row, want) {
+		t.Errorf("got %+v %v, want %+v", rows[0].row, rows[0].problems, want)
+	}
+	if rows[1].problems["options"] != "options must be a JSON object of strings" {
+		t.Errorf("got problems %v for a number option value", rows[1].problems)
+	}
+}
+
+func TestJSONLRowReader(t *testing.T) {
+	content := `{"sku": " A-1 ", "name": "  ", "images": [" https://x.test/a.jpg ", ""], "options": null}` + "\n" +
+		"\n" +
+		`{"sku": "A-2", "price": "free"}` + "\n" +
+		`{"sku": "A-3", "colour": "red"}` + "\n" +
+		`{"sku": "A-4"} {"sku": "A-5"}` + "\n" +
+		`{"sku": "A-6", "parent_sku": "A-1", "options": {"size": 1}}` + "\n"
+	rows := readRows(t, ProductFileJSONL, content)
+	if len(rows) != 5 {
+		t.Fatalf("got %d rows, want 5", len(rows))
+	}
+
+	want := &dto.ProductFileRow{SKU: "A-1", Images: []string{"https://x.test/a.jpg"}}
+	if rows[0].number != 1 || rows[0].problems != nil || !reflect.DeepEqual(rows[0].row, want) {
+		t.Errorf("row 1 = %d %+v %v, want %+v", rows[0].number, rows[0].row, rows[0].problems, want)
+	}
+	tests := []struct {
+		index   int
+		number  int
+		column  string
+		problem string
+	}{
+		{index: 1, number: 3, column: "price", problem: "price must be a number"},
+		{index: 2, number: 4, column: "row", problem: `unknown field "colour"`},
+		{index: 3, number: 5, column: "row", problem: "a line must hold a single JSON object"},
+		{index: 4, number: 6, column: "options", problem: "options must be an object of strings"},
+	}
+	for _, tt := range tests {
+		got := rows[tt.index]
+		if got.number != tt.number || got.problems[tt.column] != tt.problem {
+			t.Errorf("row %d = %d %v, want %s: %q", tt.number, got.number, got.problems, tt.column, tt.problem)
+		}
+	}
+}
+
+func TestJSONLRowReaderLongLine(t *testing.T) {
+	reader, err := newProductRowReader(ProductFileJSONL, strings.NewReader(`{"sku": "A-1", "description": "`+strings.Repeat("x", maxProductLine)+`"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, err := reader.Next(); !errors.Is(err, ErrInvalidProductFile) {
+		t.Errorf("got %v, want ErrInvalidProductFile", err)
+	}
+}
+
+// TestProductRowRoundTrip checks that Import reads back what Export writes.
+func TestProductRowRoundTrip(t *testing.T) {
+	active, inactive := true, false
+	price, weight := 19.5, 0.25
+	quantity := 3
+	rows := []*dto.ProductFileRow{
+		{
+			SKU:            "A-1",
+			Name:           stringPtr("Lamp, \"small\""),
+			Description:    stringPtr("Two\nlines"),
+			Price:          &price,
+			StockQuantity:  &quantity,
+			Category:       stringPtr("Home > Lighting"),
+			IsActive:       &active,
+			Weight:         &weight,
+			Specifications: &map[string]interface{}{"watts": float64(40)},
+			Images:         []string{"https://x.test/a.jpg", "https://x.test/b.jpg"},
+			OptionNames:    []string{"size"},
+		},
+		{
+			SKU:           "A-1-M",
+			ParentSKU:     stringPtr("A-1"),
+			StockQuantity: &quantity,
+			IsActive:      &inactive,
+			Options:       map[string]string{"size": "M"},
+		},
+	}
+	for _, format := range []string{ProductFileCSV, ProductFileJSONL} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			writer, err := newProductRowWriter(format, &buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, row := range rows {
+				if err := writer.Write(row); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := writer.Flush(); err != nil {
+				t.Fatal(err)
+			}
+			read := readRows(t, format, buf.String())
+			if len(read) != len(rows) {
+				t.Fatalf("read %d rows, want %d", len(read), len(rows))
+			}
+			for i, got := range read {
+				if got.problems != nil || !reflect.DeepEqual(got.row, rows[i]) {
+					t.Errorf("row %d = %+v %v, want %+v", i, got.row, got.problems, rows[i])
+				}
+			}
+		})
+	}
+}
+
+func TestProductFileFormat(t *testing.T) {
+	tests := map[string]string{
+		"products.csv":    ProductFileCSV,
+		"PRODUCTS.CSV":    ProductFileCSV,
+		"products.jsonl":  ProductFileJSONL,
+		"products.ndjson": ProductFileJSONL,
+		"products.xlsx":   "",
+		"":                "",
+	}
+	for name, want := range tests {
+		if got := ProductFileFormat(name); got != want {
+			t.Errorf("ProductFileFormat(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
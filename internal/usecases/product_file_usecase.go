@@ -0,0 +1,576 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mini-ecommerce/internal/domain/entities"
+	"mini-ecommerce/internal/domain/repositories"
+	"mini-ecommerce/internal/interfaces/http/dto"
+	"mini-ecommerce/pkg/logger"
+	"mini-ecommerce/pkg/validation"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	// exportBatchSize is the number of products loaded and written at a time
+	exportBatchSize = 200
+	// categoryPathSeparator joins the names of a category path such as
+	// "Fashion > Shoes"; paths are read with any spacing around the ">"
+	categoryPathSeparator = " > "
+	categoryPathDelimiter = ">"
+)
+
+var (
+	ErrUnsupportedProductFormat = errors.New("format must be csv or jsonl")
+
+	errNameRequired     = errors.New("name is required for a new product")
+	errCategoryRequired = errors.New("category is required for a new product")
+	errOptionsRequired  = errors.New("options are required for a new variant")
+	errParentNotFound   = errors.New("parent product not found")
+	errOptionsOnProduct = errors.New("options are only given on variant rows, with a parent_sku")
+)
+
+// importErrorColumns maps product errors caused by the values of a row to
+// the column they concern. Any other error stops the import.
+var importErrorColumns = []struct {
+	err    error
+	column string
+}{
+	{ErrSKUExists, "sku"},
+	{ErrProductNotFound, "sku"},
+	{ErrSlugTaken, "slug"},
+	{ErrProductCategoryNotFound, "category"},
+	{ErrMultiplePrimaryImages, "images"},
+	{ErrInvalidOptionNames, "option_names"},
+	{ErrOptionsInUse, "option_names"},
+	{ErrInvalidVariantOptions, "options"},
+	{ErrDuplicateVariant, "options"},
+	{errNameRequired, "name"},
+	{errCategoryRequired, "category"},
+	{errOptionsRequired, "options"},
+	{errParentNotFound, "parent_sku"},
+	{errOptionsOnProduct, "options"},
+}
+
+// variantRowColumns are the product columns a variant row leaves blank.
+var variantRowColumns = []string{
+	"name", "slug", "description", "category", "specifications", "dimensions", "images", "option_names",
+}
+
+type ProductFileUsecase interface {
+	// Import creates or updates a product, or a variant of one, for every row
+	// of r, matched by SKU. Each row is saved on its own, so rows failing
+	// validation are reported and skipped; a dry run runs the same checks.
+	// After each saved row progress, when given, receives the number of the
+	// row to continue from. On an error that is not caused by a row the
+	// partial result is returned with the error; its next row is the one that
+	// failed.
+	Import(ctx context.Context, actorID int, r io.Reader, req *dto.ProductImportReq, progress func(nextRow int)) (*dto.ProductImportRes, error)
+	// Export writes every product in id order, each followed by its
+	// variants, in the same format Import reads, flushing w after each batch
+	// when it can be flushed.
+	Export(ctx context.Context, req *dto.ProductExportReq, w io.Writer) error
+}
+
+type productFileUseCaseImpl struct {
+	productUseCase ProductUsecase
+	productRepo    repositories.ProductRepository
+	categoryRepo   repositories.CategoryRepository
+}
+
+// categoryIndex resolves categories by name or path, compared
+// case-insensitively, and names them by path for exports.
+type categoryIndex struct {
+	byPath map[string]int
+	byName map[string][]int
+	paths  map[int]string
+}
+
+// Import implements ProductFileUsecase.
+func (p *productFileUseCaseImpl) Import(ctx context.Context, actorID int, r io.Reader, req *dto.ProductImportReq, progress func(nextRow int)) (*dto.ProductImportRes, error) {
+	rows, err := newProductRowReader(req.Format, r)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := p.loadCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := &dto.ProductImportRes{
+		DryRun: req.DryRun,
+		Errors: []*dto.ProductImportRowError{},
+	}
+	// seen holds the SKUs of earlier rows, which a dry run counts as existing
+	seen := make(map[string]bool)
+	processed := 0
+	for {
+		number, row, problems, err := rows.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			res.NextRow = &number
+			return res, err
+		}
+		if number < req.StartRow {
+			continue
+		}
+		if req.Limit > 0 && processed == req.Limit {
+			res.NextRow = &number
+			break
+		}
+		processed++
+		created := false
+		if problems == nil {
+			created, problems, err = p.importRow(ctx, actorID, row, categories, req.DryRun, seen)
+			if err != nil {
+				res.NextRow = &number
+				return res, err
+			}
+		}
+		switch {
+		case problems != nil:
+			res.Failed++
+			rowError := &dto.ProductImportRowError{Row: number, Errors: problems}
+			if row != nil {
+				rowError.SKU = row.SKU
+			}
+			res.Errors = append(res.Errors, rowError)
+		case created:
+			res.Created++
+		default:
+			res.Updated++
+		}
+		if progress != nil && !req.DryRun {
+			progress(number + 1)
+		}
+	}
+	if !req.DryRun {
+		logger.Infof("[ProductFileUsecase] user %d imported products: %d created, %d updated, %d failed", actorID, res.Created, res.Updated, res.Failed)
+	}
+	return res, nil
+}
+
+// importRow checks the row and, unless dryRun, saves it. It reports whether
+// the row creates a product or variant, or the problems that keep it from
+// being saved.
+func (p *productFileUseCaseImpl) importRow(ctx context.Context, actorID int, row *dto.ProductFileRow, categories *categoryIndex, dryRun bool, seen map[string]bool) (bool, map[string]string, error) {
+	if err := validation.Validate.Struct(row); err != nil {
+		return false, rowValidationErrors(err), nil
+	}
+	var created bool
+	var err error
+	if row.ParentSKU != nil {
+		if problems := variantRowProblems(row); problems != nil {
+			return false, problems, nil
+		}
+		created, err = p.importVariant(ctx, actorID, row, dryRun, seen)
+	} else {
+		var categoryID *int
+		if row.Category != nil {
+			id, err := categories.resolve(*row.Category)
+			if err != nil {
+				return false, map[string]string{"category": err.Error()}, nil
+			}
+			categoryID = &id
+		}
+		created, err = p.importProduct(ctx, actorID, row, categoryID, dryRun, seen)
+	}
+	if err != nil {
+		for _, mapping := range importErrorColumns {
+			if errors.Is(err, mapping.err) {
+				return false, map[string]string{mapping.column: err.Error()}, nil
+			}
+		}
+		return false, nil, err
+	}
+	seen[row.SKU] = true
+	return created, nil, nil
+}
+
+// importProduct creates or updates the row's product, or in a dry run runs
+// the checks of doing so. It reports whether the product is new.
+func (p *productFileUseCaseImpl) importProduct(ctx context.Context, actorID int, row *dto.ProductFileRow, categoryID *int, dryRun bool, seen map[string]bool) (bool, error) {
+	if row.Options != nil {
+		return false, errOptionsOnProduct
+	}
+	existing, err := p.productRepo.GetBySKU(ctx, row.SKU)
+	if err != nil && !errors.Is(err, repositories.ErrProductNotFound) {
+		return false, err
+	}
+	switch {
+	case existing != nil:
+		req := toUpdateProductReq(existing, row, categoryID)
+		if dryRun {
+			return false, p.productUseCase.ValidateUpdate(ctx, req)
+		}
+		_, err = p.productUseCase.Update(ctx, actorID, req)
+		return false, err
+	case dryRun && seen[row.SKU]:
+		// An earlier row would have created the product, so the changes can
+		// only be checked against it once it exists.
+		return false, nil
+	case row.Name == nil:
+		return false, errNameRequired
+	case categoryID == nil:
+		return false, errCategoryRequired
+	}
+	req := toProductReq(row, *categoryID)
+	if dryRun {
+		return true, p.productUseCase.ValidateCreate(ctx, req)
+	}
+	_, err = p.productUseCase.Create(ctx, actorID, req)
+	return true, err
+}
+
+// importVariant adds or updates the row's variant of its parent product, or
+// in a dry run runs the checks of doing so. It reports whether the variant
+// is new.
+func (p *productFileUseCaseImpl) importVariant(ctx context.Context, actorID int, row *dto.ProductFileRow, dryRun bool, seen map[string]bool) (bool, error) {
+	parent, err := p.productRepo.GetBySKU(ctx, *row.ParentSKU)
+	if errors.Is(err, repositories.ErrProductNotFound) {
+		if dryRun && seen[*row.ParentSKU] {
+			// An earlier row would have created the product, so its variants
+			// can only be checked once it exists.
+			return !seen[row.SKU], nil
+		}
+		return false, errParentNotFound
+	}
+	if err != nil {
+		return false, err
+	}
+	var current *entities.ProductVariant
+	for _, variant := range parent.Variants {
+		if variant.SKU == row.SKU {
+			current = variant
+			break
+		}
+	}
+	if current == nil {
+		if dryRun && seen[row.SKU] {
+			// An earlier row would have added the variant.
+			return false, nil
+		}
+		if len(row.Options) == 0 {
+			return false, errOptionsRequired
+		}
+	}
+	req := toProductVariantReq(current, row)
+	if current == nil {
+		if dryRun {
+			return true, p.productUseCase.ValidateVariant(ctx, parent.ID, 0, req)
+		}
+		_, err = p.productUseCase.AddVariant(ctx, actorID, parent.ID, req)
+		return true, err
+	}
+	if dryRun {
+		return false, p.productUseCase.ValidateVariant(ctx, parent.ID, current.ID, req)
+	}
+	_, err = p.productUseCase.UpdateVariant(ctx, actorID, parent.ID, current.ID, req)
+	return false, err
+}
+
+// variantRowProblems reports the product columns a variant row gives.
+func variantRowProblems(row *dto.ProductFileRow) map[string]string {
+	given := map[string]bool{
+		"name":           row.Name != nil,
+		"slug":           row.Slug != nil,
+		"description":    row.Description != nil,
+		"category":       row.Category != nil,
+		"specifications": row.Specifications != nil,
+		"dimensions":     row.Dimensions != nil,
+		"images":         len(row.Images) > 0,
+		"option_names":   len(row.OptionNames) > 0,
+	}
+	var problems map[string]string
+	for _, column := range variantRowColumns {
+		if given[column] {
+			if problems == nil {
+				problems = make(map[string]string)
+			}
+			problems[column] = column + " is not used on a variant row"
+		}
+	}
+	return problems
+}
+
+func toProductReq(row *dto.ProductFileRow, categoryID int) *dto.ProductReq {
+	req := &dto.ProductReq{
+		Name:       *row.Name,
+		CategoryID: categoryID,
+		SKU:        row.SKU,
+		IsActive:   row.IsActive,
+		Options:    row.OptionNames,
+	}
+	if row.Slug != nil {
+		req.Slug = *row.Slug
+	}
+	if row.Description != nil {
+		req.Description = *row.Description
+	}
+	if row.Price != nil {
+		req.Price = *row.Price
+	}
+	if row.StockQuantity != nil {
+		req.StockQuantity = *row.StockQuantity
+	}
+	if row.Weight != nil {
+		req.Weight = *row.Weight
+	}
+	if row.Specifications != nil {
+		req.Specifications = *row.Specifications
+	}
+	if row.Dimensions != nil {
+		req.Dimensions = *row.Dimensions
+	}
+	for _, url := range row.Images {
+		req.Images = append(req.Images, dto.ProductImageReq{URL: url})
+	}
+	return req
+}
+
+// toUpdateProductReq changes the fields given by the row. The stock of a
+// product with variants is set per variant, so the row's stock is ignored
+// for it.
+func toUpdateProductReq(product *entities.Product, row *dto.ProductFileRow, categoryID *int) *dto.UpdateProductReq {
+	req := &dto.UpdateProductReq{
+		ID:             product.ID,
+		Name:           row.Name,
+		Slug:           row.Slug,
+		Description:    row.Description,
+		Price:          row.Price,
+		CategoryID:     categoryID,
+		Specifications: row.Specifications,
+		IsActive:       row.IsActive,
+		Weight:         row.Weight,
+		Dimensions:     row.Dimensions,
+	}
+	if len(product.Variants) == 0 {
+		req.StockQuantity = row.StockQuantity
+	}
+	if row.OptionNames != nil {
+		req.Options = &row.OptionNames
+	}
+	return req
+}
+
+// toProductVariantReq maps a variant row. UpdateVariant replaces every
+// field, so for an existing variant the row's blanks keep its values.
+func toProductVariantReq(variant *entities.ProductVariant, row *dto.ProductFileRow) *dto.ProductVariantReq {
+	req := &dto.ProductVariantReq{
+		SKU:      row.SKU,
+		Options:  row.Options,
+		Price:    row.Price,
+		Weight:   row.Weight,
+		IsActive: row.IsActive,
+	}
+	if variant != nil {
+		if req.Options == nil {
+			req.Options = variant.Options
+		}
+		if req.Price == nil {
+			req.Price = variant.Price
+		}
+		if req.Weight == nil {
+			req.Weight = variant.Weight
+		}
+		if req.IsActive == nil {
+			req.IsActive = &variant.IsActive
+		}
+		req.StockQuantity = variant.StockQuantity
+	}
+	if row.StockQuantity != nil {
+		req.StockQuantity = *row.StockQuantity
+	}
+	return req
+}
+
+// Export implements ProductFileUsecase.
+func (p *productFileUseCaseImpl) Export(ctx context.Context, req *dto.ProductExportReq, w io.Writer) error {
+	format := req.Format
+	if format == "" {
+		format = ProductFileCSV
+	}
+	writer, err := newProductRowWriter(format, w)
+	if err != nil {
+		return err
+	}
+	categories, err := p.loadCategories(ctx)
+	if err != nil {
+		return err
+	}
+	flusher, _ := w.(interface{ Flush() error })
+	afterID := 0
+	for {
+		products, err := p.productRepo.ListAfterId(ctx, afterID, exportBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, product := range products {
+			if err := writer.Write(toProductFileRow(product, categories)); err != nil {
+				return err
+			}
+			// A variant row names its product by SKU, so the variants of a
+			// product without one are left out.
+			for _, variant := range product.Variants {
+				if product.SKU == "" {
+					break
+				}
+				if err := writer.Write(toVariantFileRow(product, variant)); err != nil {
+					return err
+				}
+			}
+			afterID = product.ID
+		}
+		if err := writer.Flush(); err != nil {
+			return err
+		}
+		if flusher != nil {
+			if err := flusher.Flush(); err != nil {
+				return err
+			}
+		}
+		if len(products) < exportBatchSize {
+			return nil
+		}
+	}
+}
+
+func (p *productFileUseCaseImpl) loadCategories(ctx context.Context) (*categoryIndex, error) {
+	categories, err := p.categoryRepo.ListAll(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int]*entities.Category, len(categories))
+	for _, category := range categories {
+		byID[category.ID] = category
+	}
+	index := &categoryIndex{
+		byPath: make(map[string]int, len(categories)),
+		byName: make(map[string][]int, len(categories)),
+		paths:  make(map[int]string, len(categories)),
+	}
+	for _, category := range categories {
+		names := []string{category.Name}
+		for parent := category.ParentID; parent != nil; {
+			ancestor, ok := byID[*parent]
+			if !ok {
+				break
+			}
+			names = append([]string{ancestor.Name}, names...)
+			parent = ancestor.ParentID
+		}
+		path := strings.Join(names, categoryPathSeparator)
+		index.paths[category.ID] = path
+		index.byPath[strings.ToLower(path)] = category.ID
+		name := strings.ToLower(category.Name)
+		index.byName[name] = append(index.byName[name], category.ID)
+	}
+	return index, nil
+}
+
+// resolve finds the category named by value, a path when it contains the
+// separator and a name otherwise.
+func (c *categoryIndex) resolve(value string) (int, error) {
+	if strings.Contains(value, categoryPathDelimiter) {
+		names := strings.Split(value, categoryPathDelimiter)
+		for i := range names {
+			names[i] = strings.TrimSpace(names[i])
+		}
+		if id, ok := c.byPath[strings.ToLower(strings.Join(names, categoryPathSeparator))]; ok {
+			return id, nil
+		}
+		return 0, fmt.Errorf("category %q not found", value)
+	}
+	ids := c.byName[strings.ToLower(value)]
+	switch len(ids) {
+	case 0:
+		return 0, fmt.Errorf("category %q not found", value)
+	case 1:
+		return ids[0], nil
+	}
+	return 0, fmt.Errorf("several categories are named %q, use a path such as %q", value, c.paths[ids[0]])
+}
+
+// rowValidationErrors keys the validation errors of a row by column.
+func rowValidationErrors(err error) map[string]string {
+	problems := make(map[string]string)
+	errs, ok := err.(validator.ValidationErrors)
+	if !ok {
+		problems["row"] = err.Error()
+		return problems
+	}
+	rowType := reflect.TypeOf(dto.ProductFileRow{})
+	for _, e := range errs {
+		name := e.StructField()
+		if i := strings.IndexByte(name, '['); i >= 0 {
+			name = name[:i]
+		}
+		column := name
+		if field, ok := rowType.FieldByName(name); ok {
+			column = strings.Split(field.Tag.Get("json"), ",")[0]
+		}
+		problems[column] = e.Translate(validation.Trans)
+	}
+	return problems
+}
+
+func toProductFileRow(product *entities.Product, categories *categoryIndex) *dto.ProductFileRow {
+	row := &dto.ProductFileRow{
+		SKU:           product.SKU,
+		Name:          &product.Name,
+		Slug:          &product.Slug,
+		Description:   &product.Description,
+		Price:         &product.Price,
+		StockQuantity: &product.StockQuantity,
+		IsActive:      &product.IsActive,
+		Weight:        &product.Weight,
+		Images:        []string{},
+		OptionNames:   []string{},
+	}
+	if path, ok := categories.paths[product.CategoryID]; ok {
+		row.Category = &path
+	}
+	if len(product.Specifications) > 0 {
+		row.Specifications = &product.Specifications
+	}
+	if len(product.Dimensions) > 0 {
+		row.Dimensions = &product.Dimensions
+	}
+	row.OptionNames = append(row.OptionNames, product.Options...)
+	// Variant images are left out, as a row cannot link an image to a
+	// variant.
+	for _, image := range product.Images {
+		if image.VariantID == nil {
+			row.Images = append(row.Images, image.URL)
+		}
+	}
+	return row
+}
+
+// toVariantFileRow leaves the price and weight blank when the variant uses
+// the product's.
+func toVariantFileRow(product *entities.Product, variant *entities.ProductVariant) *dto.ProductFileRow {
+	return &dto.ProductFileRow{
+		SKU:           variant.SKU,
+		ParentSKU:     &product.SKU,
+		Price:         variant.Price,
+		StockQuantity: &variant.StockQuantity,
+		IsActive:      &variant.IsActive,
+		Weight:        variant.Weight,
+		Options:       variant.Options,
+	}
+}
+
+func NewProductFileUseCase(productUseCase ProductUsecase, productRepo repositories.ProductRepository, categoryRepo repositories.CategoryRepository) ProductFileUsecase {
+	return &productFileUseCaseImpl{
+		productUseCase: productUseCase,
+		productRepo:    productRepo,
+		categoryRepo:   categoryRepo,
+	}
+}
@@ -0,0 +1,198 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mini-ecommerce/internal/domain/entities"
+	"mini-ecommerce/internal/interfaces/http/dto"
+	"mini-ecommerce/pkg/validation"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// fakeProductUsecase records the calls an import makes. check decides the
+// outcome of a call by SKU and is shared by the saving and validating
+// methods, as the real usecase shares its checks.
+type fakeProductUsecase struct {
+	ProductUsecase
+	check func(sku string) error
+	calls []string
+}
+
+func (f *fakeProductUsecase) record(call, sku string) error {
+	f.calls = append(f.calls, call+" "+sku)
+	if f.check == nil {
+		return nil
+	}
+	return f.check(sku)
+}
+
+func (f *fakeProductUsecase) Create(ctx context.Context, actorID int, req *dto.ProductReq) (*dto.ProductRes, error) {
+	return &dto.ProductRes{}, f.record("create", req.SKU)
+}
+
+func (f *fakeProductUsecase) ValidateCreate(ctx context.Context, req *dto.ProductReq) error {
+	return f.record("validate create", req.SKU)
+}
+
+func (f *fakeProductUsecase) Update(ctx context.Context, actorID int, req *dto.UpdateProductReq) (*dto.ProductRes, error) {
+	return &dto.ProductRes{}, f.record("update", fmt.Sprint(req.ID))
+}
+
+func (f *fakeProductUsecase) ValidateUpdate(ctx context.Context, req *dto.UpdateProductReq) error {
+	return f.record("validate update", fmt.Sprint(req.ID))
+}
+
+func (f *fakeProductUsecase) AddVariant(ctx context.Context, actorID, productID int, req *dto.ProductVariantReq) (*dto.ProductVariantRes, error) {
+	return &dto.ProductVariantRes{}, f.record("add variant", req.SKU)
+}
+
+func (f *fakeProductUsecase) UpdateVariant(ctx context.Context, actorID, productID, variantID int, req *dto.ProductVariantReq) (*dto.ProductVariantRes, error) {
+	return &dto.ProductVariantRes{}, f.record("update variant", req.SKU)
+}
+
+func (f *fakeProductUsecase) ValidateVariant(ctx context.Context, productID, variantID int, req *dto.ProductVariantReq) error {
+	call := "validate update variant"
+	if variantID == 0 {
+		call = "validate add variant"
+	}
+	return f.record(call, req.SKU)
+}
+
+// newTestProductFileUseCase serves a shirt with one variant in a single
+// category. Rows are validated, so it sets up the validator.
+func newTestProductFileUseCase(productUseCase ProductUsecase) ProductFileUsecase {
+	validation.InitValidator()
+	shirt := func() *entities.Product {
+		return &entities.Product{
+			ID: 1, SKU: "SHIRT", Name: "Shirt", Slug: "shirt", Price: 20, CategoryID: 1, IsActive: true,
+			Options: []string{"size"},
+			Variants: []*entities.ProductVariant{
+				{ID: 10, SKU: "SHIRT-M", Options: map[string]string{"size": "M"}, StockQuantity: 4, IsActive: true},
+			},
+		}
+	}
+	productRepo := &fakeProductRepository{products: map[int]func() *entities.Product{1: shirt}}
+	categoryRepo := &fakeCategoryRepository{categories: map[int]*entities.Category{
+		1: {ID: 1, Name: "Fashion", Slug: "fashion", IsActive: true},
+	}}
+	return NewProductFileUseCase(productUseCase, productRepo, categoryRepo)
+}
+
+func TestProductImportDryRunRunsSaveChecks(t *testing.T) {
+	content := `{"sku": "LAMP", "name": "Lamp", "category": "Fashion"}` + "\n" +
+		`{"sku": "TAKEN", "name": "Taken", "category": "Fashion"}` + "\n" +
+		`{"sku": "SHIRT", "price": 25}` + "\n" +
+		`{"sku": "SHIRT-M", "parent_sku": "SHIRT", "stock_quantity": 2}` + "\n" +
+		`{"sku": "SHIRT-L", "parent_sku": "SHIRT", "options": {"size": "L"}}` + "\n" +
+		`{"sku": "LAMP-1", "parent_sku": "LAMP", "options": {"size": "S"}}` + "\n" +
+		`{"sku": "SHIRT-XL", "parent_sku": "SHIRT"}` + "\n" +
+		`{"sku": "SHIRT-S", "parent_sku": "SHIRT", "name": "Small"}` + "\n" +
+		`{"sku": "SOCK-M", "parent_sku": "SOCK", "options": {"size": "M"}}` + "\n" +
+		`{"sku": "HAT", "options": {"size": "M"}}` + "\n"
+	wantErrors := []*dto.ProductImportRowError{
+		{Row: 2, SKU: "TAKEN", Errors: map[string]string{"slug": ErrSlugTaken.Error()}},
+		{Row: 7, SKU: "SHIRT-XL", Errors: map[string]string{"options": errOptionsRequired.Error()}},
+		{Row: 8, SKU: "SHIRT-S", Errors: map[string]string{"name": "name is not used on a variant row"}},
+		{Row: 9, SKU: "SOCK-M", Errors: map[string]string{"parent_sku": errParentNotFound.Error()}},
+		{Row: 10, SKU: "HAT", Errors: map[string]string{"options": errOptionsOnProduct.Error()}},
+	}
+	tests := []struct {
+		dryRun    bool
+		wantCalls []string
+	}{
+		{
+			dryRun: true,
+			wantCalls: []string{
+				"validate create LAMP", "validate create TAKEN", "validate update 1",
+				"validate update variant SHIRT-M", "validate add variant SHIRT-L",
+			},
+		},
+		{
+			dryRun: false,
+			wantCalls: []string{
+				"create LAMP", "create TAKEN", "update 1", "update variant SHIRT-M", "add variant SHIRT-L",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("dry run %v", tt.dryRun), func(t *testing.T) {
+			productUseCase := &fakeProductUsecase{check: func(sku string) error {
+				if sku == "TAKEN" {
+					return ErrSlugTaken
+				}
+				return nil
+			}}
+			req := &dto.ProductImportReq{Format: ProductFileJSONL, DryRun: tt.dryRun}
+			res, err := newTestProductFileUseCase(productUseCase).Import(context.Background(), 1, strings.NewReader(content), req, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// LAMP-1 names a product created by row 1, which the fake does
+			// not store: a dry run counts it, a real import cannot find it.
+			wantCreated, wantFailed, wantRowErrors := 3, 5, wantErrors
+			if !tt.dryRun {
+				wantCreated, wantFailed = 2, 6
+				wantRowErrors = slices.Insert(slices.Clone(wantErrors), 1, &dto.ProductImportRowError{
+					Row: 6, SKU: "LAMP-1", Errors: map[string]string{"parent_sku": errParentNotFound.Error()},
+				})
+			}
+			if res.Created != wantCreated || res.Updated != 2 || res.Failed != wantFailed {
+				t.Errorf("got %d created, %d updated, %d failed, want %d, 2, %d", res.Created, res.Updated, res.Failed, wantCreated, wantFailed)
+			}
+			if !reflect.DeepEqual(res.Errors, wantRowErrors) {
+				for _, rowError := range res.Errors {
+					t.Logf("row error %+v", rowError)
+				}
+				t.Errorf("row errors differ")
+			}
+			if !reflect.DeepEqual(productUseCase.calls, tt.wantCalls) {
+				t.Errorf("got calls %q, want %q", productUseCase.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestProductImportKeepsVariantFields(t *testing.T) {
+	var got *dto.ProductVariantReq
+	productUseCase := &recordingVariantUsecase{update: func(req *dto.ProductVariantReq) { got = req }}
+	content := `{"sku": "SHIRT-M", "parent_sku": "SHIRT", "stock_quantity": 2}` + "\n"
+	req := &dto.ProductImportReq{Format: ProductFileJSONL}
+	if _, err := newTestProductFileUseCase(productUseCase).Import(context.Background(), 1, strings.NewReader(content), req, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got == nil {
+		t.Fatal("the variant was not updated")
+	}
+	if got.StockQuantity != 2 || !reflect.DeepEqual(got.Options, map[string]string{"size": "M"}) || got.IsActive == nil || !*got.IsActive {
+		t.Errorf("got %+v, want the new stock with the other fields kept", got)
+	}
+}
+
+// recordingVariantUsecase hands the request of UpdateVariant to update.
+type recordingVariantUsecase struct {
+	ProductUsecase
+	update func(req *dto.ProductVariantReq)
+}
+
+func (r *recordingVariantUsecase) UpdateVariant(ctx context.Context, actorID, productID, variantID int, req *dto.ProductVariantReq) (*dto.ProductVariantRes, error) {
+	r.update(req)
+	return &dto.ProductVariantRes{}, nil
+}
+
+func TestProductExportWritesVariants(t *testing.T) {
+	var buf bytes.Buffer
+	err := newTestProductFileUseCase(nil).Export(context.Background(), &dto.ProductExportReq{Format: ProductFileCSV}, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "sku,parent_sku,name,slug,description,price,stock_quantity,category,is_active,weight,specifications,dimensions,images,option_names,options\n" +
+		"SHIRT,,Shirt,shirt,,20,0,Fashion,true,0,,,,size,\n" +
+		"SHIRT-M,SHIRT,,,,,4,,true,,,,,,\"{\"\"size\"\":\"\"M\"\"}\"\n"
+	if buf.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", buf.String(), want)
+	}
+}
@@ -1 +1,815 @@
-package usecases
\ No newline at end of file
+package usecases
+
+import (
+	"context"
+	"errors"
+	"mini-ecommerce/internal/domain/entities"
+	"mini-ecommerce/internal/domain/repositories"
+	"mini-ecommerce/internal/interfaces/http/dto"
+	"mini-ecommerce/pkg/logger"
+	"mini-ecommerce/pkg/utils"
+	"strings"
+	"time"
+)
+
+// defaultProductLimit fills a storefront grid of three or four columns
+const defaultProductLimit = 12
+
+// priceFacetBounds are the upper bounds of the price facet ranges; the last
+// range is open-ended.
+var priceFacetBounds = []float64{25, 50, 100, 250, 500, 1000}
+
+var (
+	ErrProductNotFound         = errors.New("product not found")
+	ErrProductCategoryNotFound = errors.New("category not found")
+	ErrSKUExists               = errors.New("a product with this SKU already exists")
+	ErrMultiplePrimaryImages   = errors.New("only one image can be primary")
+	ErrProductImageNotFound    = errors.New("product image not found")
+	ErrProductInUse            = errors.New("product has been ordered and cannot be deleted, deactivate it instead")
+	ErrInvalidPriceRange       = errors.New("min_price must not be greater than max_price")
+	ErrInvalidSortField        = errors.New("sort_by must be one of name, price, created_at, updated_at, stock_quantity")
+	ErrImageOrderMismatch      = errors.New("ids must list every image of the product exactly once")
+	ErrVariantNotFound         = errors.New("product variant not found")
+	ErrInvalidVariantOptions   = errors.New("variant options must give one value for each of the product's options")
+	ErrDuplicateVariant        = errors.New("another variant has the same options")
+	ErrVariantInUse            = errors.New("variant has been ordered and cannot be deleted, deactivate it instead")
+	ErrInvalidOptionNames      = errors.New("option names must not be blank or repeated")
+	ErrOptionsInUse            = errors.New("options cannot change while the product has variants, only their order")
+	ErrStockManagedByVariants  = errors.New("the stock of a product with variants is set per variant")
+	ErrVariantImageOnCreate    = errors.New("images of a variant can be added once the variant exists")
+)
+
+type ProductUsecase interface {
+	// List returns the products matching req, of either status unless
+	// req.IsActive is set, with facet counts for the filter.
+	List(ctx context.Context, req *dto.ProductListReq) (*dto.ProductListRes, error)
+	// GetById with activeOnly treats an inactive product as missing and
+	// leaves out its inactive variants and their images.
+	GetById(ctx context.Context, id int, activeOnly bool) (*dto.ProductRes, error)
+	// GetBySlug also resolves previous slugs; compare the returned slug with
+	// the requested one to detect a redirect. activeOnly works as for GetById.
+	GetBySlug(ctx context.Context, slug string, activeOnly bool) (*dto.ProductRes, error)
+	Create(ctx context.Context, actorID int, req *dto.ProductReq) (*dto.ProductRes, error)
+	// ValidateCreate runs the checks of Create without saving anything.
+	ValidateCreate(ctx context.Context, req *dto.ProductReq) error
+	Update(ctx context.Context, actorID int, req *dto.UpdateProductReq) (*dto.ProductRes, error)
+	// ValidateUpdate runs the checks of Update without saving anything.
+	ValidateUpdate(ctx context.Context, req *dto.UpdateProductReq) error
+	Delete(ctx context.Context, actorID, id int) error
+	// AddImage appends an image and returns the product's images. The first
+	// image of a product always becomes primary.
+	AddImage(ctx context.Context, actorID, productID int, req *dto.ProductImageReq) ([]*dto.ProductImageRes, error)
+	SetPrimaryImage(ctx context.Context, actorID, productID, imageID int) ([]*dto.ProductImageRes, error)
+	// DeleteImage removes an image; when it was primary the next image in
+	// display order becomes primary.
+	DeleteImage(ctx context.Context, actorID, productID, imageID int) error
+	// ReorderImages rewrites the display order of the product's images and
+	// returns them in the new order.
+	ReorderImages(ctx context.Context, actorID, productID int, req *dto.ReorderImagesReq) ([]*dto.ProductImageRes, error)
+	// AddVariant appends a variant; its options must give a value for each of
+	// the product's options.
+	AddVariant(ctx context.Context, actorID, productID int, req *dto.ProductVariantReq) (*dto.ProductVariantRes, error)
+	// UpdateVariant replaces all fields of the variant.
+	UpdateVariant(ctx context.Context, actorID, productID, variantID int, req *dto.ProductVariantReq) (*dto.ProductVariantRes, error)
+	// UpdateVariantStock sets the stock of the variant only, for callers that
+	// may not edit the rest of the product.
+	UpdateVariantStock(ctx context.Context, actorID, productID, variantID, quantity int) (*dto.ProductVariantRes, error)
+	// ValidateVariant runs the checks of UpdateVariant, or of AddVariant when
+	// variantID is 0, without saving anything.
+	ValidateVariant(ctx context.Context, productID, variantID int, req *dto.ProductVariantReq) error
+	// DeleteVariant removes a variant that was never ordered.
+	DeleteVariant(ctx context.Context, actorID, productID, variantID int) error
+}
+
+type productUseCaseImpl struct {
+	productRepo        repositories.ProductRepository
+	productImageRepo   repositories.ProductImageRepository
+	productVariantRepo repositories.ProductVariantRepository
+	categoryRepo       repositories.CategoryRepository
+}
+
+// List implements ProductUsecase.
+func (p *productUseCaseImpl) List(ctx context.Context, req *dto.ProductListReq) (*dto.ProductListRes, error) {
+	if req.MinPrice != nil && req.MaxPrice != nil && *req.MinPrice > *req.MaxPrice {
+		return nil, ErrInvalidPriceRange
+	}
+	page, limit := utils.NormalizePage(req.Page, req.Limit, defaultProductLimit)
+	filter := repositories.ProductFilter{
+		CategoryID: req.CategoryID,
+		Search:     strings.TrimSpace(req.Search),
+		MinPrice:   req.MinPrice,
+		MaxPrice:   req.MaxPrice,
+		IsActive:   req.IsActive,
+		InStock:    req.InStock,
+		Specs:      req.Specs,
+		SortBy:     req.SortBy,
+		SortDesc:   req.SortOrder == "desc",
+		Offset:     utils.Offset(page, limit),
+		Limit:      limit,
+	}
+	products, total, err := p.productRepo.List(ctx, filter)
+	if err != nil {
+		if errors.Is(err, repositories.ErrInvalidProductSort) {
+			return nil, ErrInvalidSortField
+		}
+		return nil, err
+	}
+	facets, err := p.productRepo.Facets(ctx, filter, priceFacetBounds)
+	if err != nil {
+		return nil, err
+	}
+	res := &dto.ProductListRes{
+		Products:   make([]*dto.ProductRes, 0, len(products)),
+		Facets:     toProductFacetsRes(facets),
+		Pagination: utils.NewPagination(page, limit, total),
+	}
+	for _, product := range products {
+		res.Products = append(res.Products, toProductRes(product))
+	}
+	return res, nil
+}
+
+// GetById implements ProductUsecase.
+func (p *productUseCaseImpl) GetById(ctx context.Context, id int, activeOnly bool) (*dto.ProductRes, error) {
+	product, err := p.getProduct(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if activeOnly && !hideInactiveVariants(product) {
+		return nil, ErrProductNotFound
+	}
+	return toProductRes(product), nil
+}
+
+// GetBySlug implements ProductUsecase.
+func (p *productUseCaseImpl) GetBySlug(ctx context.Context, slug string, activeOnly bool) (*dto.ProductRes, error) {
+	product, err := p.productRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, repositories.ErrProductNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, err
+	}
+	if activeOnly && !hideInactiveVariants(product) {
+		return nil, ErrProductNotFound
+	}
+	return toProductRes(product), nil
+}
+
+// hideInactiveVariants drops the inactive variants of an active product and
+// the images showing them. It reports false for an inactive product.
+func hideInactiveVariants(product *entities.Product) bool {
+	if !product.IsActive {
+		return false
+	}
+	hidden := make(map[int]bool)
+	variants := make([]*entities.ProductVariant, 0, len(product.Variants))
+	for _, variant := range product.Variants {
+		if variant.IsActive {
+			variants = append(variants, variant)
+		} else {
+			hidden[variant.ID] = true
+		}
+	}
+	if len(hidden) == 0 {
+		return true
+	}
+	product.Variants = variants
+	images := make([]*entities.ProductImage, 0, len(product.Images))
+	for _, image := range product.Images {
+		if image.VariantID == nil || !hidden[*image.VariantID] {
+			images = append(images, image)
+		}
+	}
+	product.Images = images
+	return true
+}
+
+// Create implements ProductUsecase.
+func (p *productUseCaseImpl) Create(ctx context.Context, actorID int, req *dto.ProductReq) (*dto.ProductRes, error) {
+	product, err := p.newProduct(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.productRepo.Create(ctx, product); err != nil {
+		return nil, err
+	}
+	logger.Infof("[ProductUsecase] user %d created product %d", actorID, product.ID)
+	return p.GetById(ctx, product.ID, false)
+}
+
+// ValidateCreate implements ProductUsecase.
+func (p *productUseCaseImpl) ValidateCreate(ctx context.Context, req *dto.ProductReq) error {
+	_, err := p.newProduct(ctx, req)
+	return err
+}
+
+// newProduct maps and checks the product req creates, with its slug
+// assigned.
+func (p *productUseCaseImpl) newProduct(ctx context.Context, req *dto.ProductReq) (*entities.Product, error) {
+	product := &entities.Product{
+		Name:           strings.TrimSpace(req.Name),
+		Description:    req.Description,
+		Price:          req.Price,
+		StockQuantity:  req.StockQuantity,
+		CategoryID:     req.CategoryID,
+		SKU:            strings.TrimSpace(req.SKU),
+		Specifications: req.Specifications,
+		IsActive:       true,
+		Weight:         req.Weight,
+		Dimensions:     req.Dimensions,
+	}
+	if req.IsActive != nil {
+		product.IsActive = *req.IsActive
+	}
+	images, err := toProductImages(req.Images)
+	if err != nil {
+		return nil, err
+	}
+	product.Images = images
+	if product.Options, err = normalizeOptionNames(req.Options); err != nil {
+		return nil, err
+	}
+	if product.Variants, err = p.toNewVariants(ctx, product, req.Variants); err != nil {
+		return nil, err
+	}
+	if err := p.ensureValidProduct(ctx, product); err != nil {
+		return nil, err
+	}
+	if err := p.assignSlug(ctx, product, req.Slug); err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+// Update implements ProductUsecase.
+func (p *productUseCaseImpl) Update(ctx context.Context, actorID int, req *dto.UpdateProductReq) (*dto.ProductRes, error) {
+	product, err := p.updatedProduct(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.productRepo.Update(ctx, product); err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrProductNotFound):
+			return nil, ErrProductNotFound
+		case errors.Is(err, repositories.ErrProductHasVariants):
+			return nil, ErrOptionsInUse
+		}
+		return nil, err
+	}
+	logger.Infof("[ProductUsecase] user %d updated product %d", actorID, product.ID)
+	return p.GetById(ctx, product.ID, false)
+}
+
+// ValidateUpdate implements ProductUsecase.
+func (p *productUseCaseImpl) ValidateUpdate(ctx context.Context, req *dto.UpdateProductReq) error {
+	_, err := p.updatedProduct(ctx, req)
+	return err
+}
+
+// updatedProduct loads the product and applies and checks the changes of
+// req. The repository checks the options again when saving, as variants may
+// be added in between.
+func (p *productUseCaseImpl) updatedProduct(ctx context.Context, req *dto.UpdateProductReq) (*entities.Product, error) {
+	product, err := p.getProduct(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	renamed := req.Name != nil && strings.TrimSpace(*req.Name) != product.Name
+	if req.Name != nil {
+		product.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Description != nil {
+		product.Description = *req.Description
+	}
+	if req.Price != nil {
+		product.Price = *req.Price
+	}
+	if req.StockQuantity != nil {
+		if len(product.Variants) > 0 {
+			return nil, ErrStockManagedByVariants
+		}
+		product.StockQuantity = *req.StockQuantity
+	}
+	if req.CategoryID != nil {
+		product.CategoryID = *req.CategoryID
+	}
+	if req.SKU != nil {
+		product.SKU = strings.TrimSpace(*req.SKU)
+	}
+	if req.Specifications != nil {
+		product.Specifications = *req.Specifications
+	}
+	if req.IsActive != nil {
+		product.IsActive = *req.IsActive
+	}
+	if req.Weight != nil {
+		product.Weight = *req.Weight
+	}
+	if req.Dimensions != nil {
+		product.Dimensions = *req.Dimensions
+	}
+	if req.Options != nil {
+		options, err := normalizeOptionNames(*req.Options)
+		if err != nil {
+			return nil, err
+		}
+		if len(product.Variants) > 0 && !entities.SameOptionNames(product.Options, options) {
+			return nil, ErrOptionsInUse
+		}
+		product.Options = options
+	}
+	if err := p.ensureValidProduct(ctx, product); err != nil {
+		return nil, err
+	}
+	switch {
+	case req.Slug != nil:
+		err = p.assignSlug(ctx, product, *req.Slug)
+	case renamed:
+		err = p.assignSlug(ctx, product, "")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+// Delete implements ProductUsecase.
+// Products that appear in orders must be deactivated instead.
+func (p *productUseCaseImpl) Delete(ctx context.Context, actorID, id int) error {
+	if err := p.productRepo.Delete(ctx, id); err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrProductNotFound):
+			return ErrProductNotFound
+		case errors.Is(err, repositories.ErrProductHasOrders):
+			return ErrProductInUse
+		}
+		return err
+	}
+	logger.Infof("[ProductUsecase] user %d deleted product %d", actorID, id)
+	return nil
+}
+
+// AddImage implements ProductUsecase.
+func (p *productUseCaseImpl) AddImage(ctx context.Context, actorID, productID int, req *dto.ProductImageReq) ([]*dto.ProductImageRes, error) {
+	image := &entities.ProductImage{
+		ProductID: productID,
+		URL:       req.URL,
+		AltText:   req.AltText,
+		IsPrimary: req.IsPrimary,
+		VariantID: req.VariantID,
+	}
+	if err := p.productImageRepo.Create(ctx, image); err != nil {
+		return nil, productImageError(err)
+	}
+	logger.Infof("[ProductUsecase] user %d added image %d to product %d", actorID, image.ID, productID)
+	return p.listImages(ctx, productID)
+}
+
+// SetPrimaryImage implements ProductUsecase.
+func (p *productUseCaseImpl) SetPrimaryImage(ctx context.Context, actorID, productID, imageID int) ([]*dto.ProductImageRes, error) {
+	if err := p.productImageRepo.SetPrimary(ctx, productID, imageID); err != nil {
+		return nil, productImageError(err)
+	}
+	logger.Infof("[ProductUsecase] user %d made image %d primary for product %d", actorID, imageID, productID)
+	return p.listImages(ctx, productID)
+}
+
+// DeleteImage implements ProductUsecase.
+func (p *productUseCaseImpl) DeleteImage(ctx context.Context, actorID, productID, imageID int) error {
+	if err := p.productImageRepo.Delete(ctx, productID, imageID); err != nil {
+		return productImageError(err)
+	}
+	logger.Infof("[ProductUsecase] user %d deleted image %d of product %d", actorID, imageID, productID)
+	return nil
+}
+
+// ReorderImages implements ProductUsecase.
+func (p *productUseCaseImpl) ReorderImages(ctx context.Context, actorID, productID int, req *dto.ReorderImagesReq) ([]*dto.ProductImageRes, error) {
+	if err := p.productImageRepo.Reorder(ctx, productID, req.IDs); err != nil {
+		return nil, productImageError(err)
+	}
+	logger.Infof("[ProductUsecase] user %d reordered the images of product %d", actorID, productID)
+	return p.listImages(ctx, productID)
+}
+
+// AddVariant implements ProductUsecase.
+func (p *productUseCaseImpl) AddVariant(ctx context.Context, actorID, productID int, req *dto.ProductVariantReq) (*dto.ProductVariantRes, error) {
+	product, variant, err := p.checkedVariant(ctx, productID, 0, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.productVariantRepo.Create(ctx, variant); err != nil {
+		return nil, productVariantError(err)
+	}
+	logger.Infof("[ProductUsecase] user %d added variant %d to product %d", actorID, variant.ID, productID)
+	return toProductVariantRes(product, variant), nil
+}
+
+// UpdateVariant implements ProductUsecase.
+func (p *productUseCaseImpl) UpdateVariant(ctx context.Context, actorID, productID, variantID int, req *dto.ProductVariantReq) (*dto.ProductVariantRes, error) {
+	product, variant, err := p.checkedVariant(ctx, productID, variantID, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.productVariantRepo.Update(ctx, variant); err != nil {
+		return nil, productVariantError(err)
+	}
+	logger.Infof("[ProductUsecase] user %d updated variant %d of product %d", actorID, variantID, productID)
+	return toProductVariantRes(product, variant), nil
+}
+
+// UpdateVariantStock implements ProductUsecase.
+func (p *productUseCaseImpl) UpdateVariantStock(ctx context.Context, actorID, productID, variantID, quantity int) (*dto.ProductVariantRes, error) {
+	product, err := p.getProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.productVariantRepo.UpdateStock(ctx, productID, variantID, quantity); err != nil {
+		return nil, productVariantError(err)
+	}
+	variant, err := p.productVariantRepo.GetById(ctx, productID, variantID)
+	if err != nil {
+		return nil, productVariantError(err)
+	}
+	logger.Infof("[ProductUsecase] user %d set the stock of variant %d of product %d to %d", actorID, variantID, productID, quantity)
+	return toProductVariantRes(product, variant), nil
+}
+
+// ValidateVariant implements ProductUsecase.
+func (p *productUseCaseImpl) ValidateVariant(ctx context.Context, productID, variantID int, req *dto.ProductVariantReq) error {
+	_, _, err := p.checkedVariant(ctx, productID, variantID, req)
+	return err
+}
+
+// checkedVariant maps and checks the variant req describes: a new variant of
+// the product when variantID is 0, the replacement of that variant
+// otherwise. The repository checks the options again when saving, as
+// variants may be added in between.
+func (p *productUseCaseImpl) checkedVariant(ctx context.Context, productID, variantID int, req *dto.ProductVariantReq) (*entities.Product, *entities.ProductVariant, error) {
+	product, err := p.getProduct(ctx, productID)
+	if err != nil {
+		return nil, nil, err
+	}
+	var current *entities.ProductVariant
+	if variantID != 0 {
+		if current, err = p.productVariantRepo.GetById(ctx, productID, variantID); err != nil {
+			return nil, nil, productVariantError(err)
+		}
+	}
+	variant, err := toVariant(product, req)
+	if err != nil {
+		return nil, nil, err
+	}
+	if current != nil {
+		variant.ID = current.ID
+		variant.SortOrder = current.SortOrder
+		variant.CreatedAt = current.CreatedAt
+		variant.Images = current.Images
+	}
+	key := optionsKey(product.Options, variant.Options)
+	for _, other := range product.Variants {
+		if other.ID != variant.ID && optionsKey(product.Options, other.Options) == key {
+			return nil, nil, ErrDuplicateVariant
+		}
+	}
+	if err := p.ensureSKUAvailable(ctx, variant.SKU, 0, variant.ID); err != nil {
+		return nil, nil, err
+	}
+	return product, variant, nil
+}
+
+// DeleteVariant implements ProductUsecase.
+// Variants that appear in orders must be deactivated instead.
+func (p *productUseCaseImpl) DeleteVariant(ctx context.Context, actorID, productID, variantID int) error {
+	if err := p.productVariantRepo.Delete(ctx, productID, variantID); err != nil {
+		return productVariantError(err)
+	}
+	logger.Infof("[ProductUsecase] user %d deleted variant %d of product %d", actorID, variantID, productID)
+	return nil
+}
+
+func (p *productUseCaseImpl) getProduct(ctx context.Context, id int) (*entities.Product, error) {
+	product, err := p.productRepo.GetById(ctx, id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrProductNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, err
+	}
+	return product, nil
+}
+
+func (p *productUseCaseImpl) listImages(ctx context.Context, productID int) ([]*dto.ProductImageRes, error) {
+	images, err := p.productImageRepo.ListByProductId(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	return toProductImageResList(images), nil
+}
+
+// ensureValidProduct checks that the category exists and no other product
+// or variant uses the SKU.
+func (p *productUseCaseImpl) ensureValidProduct(ctx context.Context, product *entities.Product) error {
+	if _, err := p.categoryRepo.GetById(ctx, product.CategoryID); err != nil {
+		if errors.Is(err, repositories.ErrCategoryNotFound) {
+			return ErrProductCategoryNotFound
+		}
+		return err
+	}
+	if product.SKU == "" {
+		return nil
+	}
+	return p.ensureSKUAvailable(ctx, product.SKU, product.ID, 0)
+}
+
+// ensureSKUAvailable checks that no product or variant other than the given
+// ones uses the SKU. Products and variants share one SKU namespace.
+func (p *productUseCaseImpl) ensureSKUAvailable(ctx context.Context, sku string, productID, variantID int) error {
+	exists, err := p.productRepo.ExistsBySKU(ctx, sku, productID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		exists, err = p.productVariantRepo.ExistsBySKU(ctx, sku, variantID)
+		if err != nil {
+			return err
+		}
+	}
+	if exists {
+		return ErrSKUExists
+	}
+	return nil
+}
+
+// toNewVariants maps the variants of a new product. Their SKUs must differ
+// from each other and the product's, and their options from each other.
+func (p *productUseCaseImpl) toNewVariants(ctx context.Context, product *entities.Product, reqs []dto.ProductVariantReq) ([]*entities.ProductVariant, error) {
+	variants := make([]*entities.ProductVariant, 0, len(reqs))
+	skus := map[string]bool{product.SKU: product.SKU != ""}
+	combinations := make(map[string]bool, len(reqs))
+	for i := range reqs {
+		variant, err := toVariant(product, &reqs[i])
+		if err != nil {
+			return nil, err
+		}
+		if skus[variant.SKU] {
+			return nil, ErrSKUExists
+		}
+		skus[variant.SKU] = true
+		key := optionsKey(product.Options, variant.Options)
+		if combinations[key] {
+			return nil, ErrDuplicateVariant
+		}
+		combinations[key] = true
+		if err := p.ensureSKUAvailable(ctx, variant.SKU, 0, 0); err != nil {
+			return nil, err
+		}
+		variants = append(variants, variant)
+	}
+	return variants, nil
+}
+
+// assignSlug sets the requested slug, or one generated from the name when
+// requested is empty. The product's own current and previous slugs count as
+// free, so renaming back restores the old slug.
+func (p *productUseCaseImpl) assignSlug(ctx context.Context, product *entities.Product, requested string) error {
+	taken := func(ctx context.Context, slug string) (bool, error) {
+		return p.productRepo.SlugExists(ctx, slug, product.ID)
+	}
+	if requested != "" {
+		exists, err := taken(ctx, requested)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return ErrSlugTaken
+		}
+		product.Slug = requested
+		return nil
+	}
+	slug, err := uniqueSlug(ctx, product.Name, "product", taken)
+	if err != nil {
+		return err
+	}
+	product.Slug = slug
+	return nil
+}
+
+// toProductImages maps the requested images, making the first one primary
+// when none is marked.
+func toProductImages(reqs []dto.ProductImageReq) ([]*entities.ProductImage, error) {
+	images := make([]*entities.ProductImage, 0, len(reqs))
+	primary := 0
+	for _, req := range reqs {
+		if req.VariantID != nil {
+			return nil, ErrVariantImageOnCreate
+		}
+		if req.IsPrimary {
+			primary++
+		}
+		images = append(images, &entities.ProductImage{
+			URL:       req.URL,
+			AltText:   req.AltText,
+			IsPrimary: req.IsPrimary,
+		})
+	}
+	if primary > 1 {
+		return nil, ErrMultiplePrimaryImages
+	}
+	if primary == 0 && len(images) > 0 {
+		images[0].IsPrimary = true
+	}
+	return images, nil
+}
+
+// normalizeOptionNames trims the option names, which must be unique
+// regardless of case.
+func normalizeOptionNames(names []string) ([]string, error) {
+	result := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		key := strings.ToLower(name)
+		if name == "" || seen[key] {
+			return nil, ErrInvalidOptionNames
+		}
+		seen[key] = true
+		result = append(result, name)
+	}
+	return result, nil
+}
+
+// toVariant maps a requested variant of the product. Its options must name
+// exactly the product's options, each with a value.
+func toVariant(product *entities.Product, req *dto.ProductVariantReq) (*entities.ProductVariant, error) {
+	if len(req.Options) != len(product.Options) {
+		return nil, ErrInvalidVariantOptions
+	}
+	options := make(map[string]string, len(req.Options))
+	for _, name := range product.Options {
+		value, ok := req.Options[name]
+		if value = strings.TrimSpace(value); !ok || value == "" {
+			return nil, ErrInvalidVariantOptions
+		}
+		options[name] = value
+	}
+	variant := &entities.ProductVariant{
+		ProductID:     product.ID,
+		SKU:           strings.TrimSpace(req.SKU),
+		Options:       options,
+		Price:         req.Price,
+		StockQuantity: req.StockQuantity,
+		Weight:        req.Weight,
+		IsActive:      true,
+	}
+	if req.IsActive != nil {
+		variant.IsActive = *req.IsActive
+	}
+	return variant, nil
+}
+
+// optionsKey identifies a combination of option values.
+func optionsKey(names []string, options map[string]string) string {
+	values := make([]string, 0, len(names))
+	for _, name := range names {
+		values = append(values, options[name])
+	}
+	return strings.Join(values, "\x00")
+}
+
+func productVariantError(err error) error {
+	switch {
+	case errors.Is(err, repositories.ErrProductNotFound):
+		return ErrProductNotFound
+	case errors.Is(err, repositories.ErrProductVariantNotFound):
+		return ErrVariantNotFound
+	case errors.Is(err, repositories.ErrDuplicateVariantOptions):
+		return ErrDuplicateVariant
+	case errors.Is(err, repositories.ErrVariantHasOrders):
+		return ErrVariantInUse
+	}
+	return err
+}
+
+func productImageError(err error) error {
+	switch {
+	case errors.Is(err, repositories.ErrProductNotFound):
+		return ErrProductNotFound
+	case errors.Is(err, repositories.ErrProductVariantNotFound):
+		return ErrVariantNotFound
+	case errors.Is(err, repositories.ErrProductImageNotFound):
+		return ErrProductImageNotFound
+	case errors.Is(err, repositories.ErrImageOrderMismatch):
+		return ErrImageOrderMismatch
+	}
+	return err
+}
+
+func toProductFacetsRes(facets *entities.ProductFacets) *dto.ProductFacetsRes {
+	res := &dto.ProductFacetsRes{
+		Categories:     make([]*dto.CategoryFacetRes, 0, len(facets.Categories)),
+		PriceRanges:    make([]*dto.PriceRangeFacetRes, 0, len(facets.PriceRanges)),
+		Specifications: make(map[string][]*dto.SpecValueFacetRes),
+	}
+	for _, category := range facets.Categories {
+		res.Categories = append(res.Categories, &dto.CategoryFacetRes{
+			ID:    category.ID,
+			Name:  category.Name,
+			Slug:  category.Slug,
+			Count: category.Count,
+		})
+	}
+	for _, priceRange := range facets.PriceRanges {
+		res.PriceRanges = append(res.PriceRanges, &dto.PriceRangeFacetRes{
+			Min:   priceRange.Min,
+			Max:   priceRange.Max,
+			Count: priceRange.Count,
+		})
+	}
+	for _, spec := range facets.Specifications {
+		res.Specifications[spec.Key] = append(res.Specifications[spec.Key], &dto.SpecValueFacetRes{
+			Value: spec.Value,
+			Count: spec.Count,
+		})
+	}
+	return res
+}
+
+func toProductImageResList(images []*entities.ProductImage) []*dto.ProductImageRes {
+	res := make([]*dto.ProductImageRes, 0, len(images))
+	for _, image := range images {
+		res = append(res, &dto.ProductImageRes{
+			ID:        image.ID,
+			VariantID: image.VariantID,
+			URL:       image.URL,
+			AltText:   image.AltText,
+			IsPrimary: image.IsPrimary,
+			SortOrder: image.SortOrder,
+		})
+	}
+	return res
+}
+
+func toProductVariantRes(product *entities.Product, variant *entities.ProductVariant) *dto.ProductVariantRes {
+	return &dto.ProductVariantRes{
+		ID:             variant.ID,
+		SKU:            variant.SKU,
+		Options:        variant.Options,
+		Price:          variant.UnitPrice(product),
+		PriceOverride:  variant.Price,
+		StockQuantity:  variant.StockQuantity,
+		Weight:         variant.UnitWeight(product),
+		WeightOverride: variant.Weight,
+		IsActive:       variant.IsActive,
+		SortOrder:      variant.SortOrder,
+		Images:         toProductImageResList(variant.Images),
+	}
+}
+
+func toProductRes(product *entities.Product) *dto.ProductRes {
+	res := &dto.ProductRes{
+		ID:          product.ID,
+		Name:        product.Name,
+		Slug:        product.Slug,
+		Description: product.Description,
+		Price:       product.Price,
+		PriceRange: dto.PriceRangeRes{
+			Min: product.MinPrice,
+			Max: product.MaxPrice,
+		},
+		StockQuantity:  product.StockQuantity,
+		CategoryID:     product.CategoryID,
+		SKU:            product.SKU,
+		Images:         toProductImageResList(product.Images),
+		Specifications: product.Specifications,
+		IsActive:       product.IsActive,
+		Weight:         product.Weight,
+		Dimensions:     product.Dimensions,
+		Options:        product.Options,
+		Rating:         product.Rating,
+		ReviewCount:    product.ReviewCount,
+		CreatedAt:      product.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:      product.UpdatedAt.Format(time.RFC3339),
+	}
+	if product.Category != nil {
+		res.Category = &dto.ProductCategoryRes{
+			ID:   product.Category.ID,
+			Name: product.Category.Name,
+			Slug: product.Category.Slug,
+		}
+	}
+	for _, variant := range product.Variants {
+		res.Variants = append(res.Variants, toProductVariantRes(product, variant))
+	}
+	return res
+}
+
+func NewProductUseCase(productRepo repositories.ProductRepository, productImageRepo repositories.ProductImageRepository, productVariantRepo repositories.ProductVariantRepository, categoryRepo repositories.CategoryRepository) ProductUsecase {
+	return &productUseCaseImpl{
+		productRepo:        productRepo,
+		productImageRepo:   productImageRepo,
+		productVariantRepo: productVariantRepo,
+		categoryRepo:       categoryRepo,
+	}
+}
@@ -0,0 +1,244 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"mini-ecommerce/internal/domain/entities"
+	"mini-ecommerce/internal/domain/repositories"
+	"mini-ecommerce/internal/interfaces/http/dto"
+	"slices"
+	"testing"
+)
+
+// fakeProductRepository returns fresh copies of fixed products, as reading
+// them from the database would.
+type fakeProductRepository struct {
+	repositories.ProductRepository
+	products map[int]func() *entities.Product
+}
+
+func (f *fakeProductRepository) GetById(ctx context.Context, id int) (*entities.Product, error) {
+	product, ok := f.products[id]
+	if !ok {
+		return nil, repositories.ErrProductNotFound
+	}
+	return product(), nil
+}
+
+func (f *fakeProductRepository) GetBySlug(ctx context.Context, slug string) (*entities.Product, error) {
+	for _, product := range f.products {
+		if p := product(); p.Slug == slug {
+			return p, nil
+		}
+	}
+	return nil, repositories.ErrProductNotFound
+}
+
+func (f *fakeProductRepository) GetBySKU(ctx context.Context, sku string) (*entities.Product, error) {
+	for _, product := range f.products {
+		if p := product(); p.SKU == sku {
+			return p, nil
+		}
+	}
+	return nil, repositories.ErrProductNotFound
+}
+
+// List filters by status only, ordered by ID.
+func (f *fakeProductRepository) List(ctx context.Context, filter repositories.ProductFilter) ([]*entities.Product, int64, error) {
+	products, _ := f.ListAfterId(ctx, 0, len(f.products))
+	products = slices.DeleteFunc(products, func(p *entities.Product) bool {
+		return filter.IsActive != nil && p.IsActive != *filter.IsActive
+	})
+	return products, int64(len(products)), nil
+}
+
+func (f *fakeProductRepository) Facets(ctx context.Context, filter repositories.ProductFilter, priceBounds []float64) (*entities.ProductFacets, error) {
+	return &entities.ProductFacets{}, nil
+}
+
+func (f *fakeProductRepository) ListByIds(ctx context.Context, ids []int) ([]*entities.Product, error) {
+	products := make([]*entities.Product, 0, len(ids))
+	for id, product := range f.products {
+		if slices.Contains(ids, id) {
+			products = append(products, product())
+		}
+	}
+	return products, nil
+}
+
+func (f *fakeProductRepository) ListAfterId(ctx context.Context, afterID, limit int) ([]*entities.Product, error) {
+	ids := make([]int, 0, len(f.products))
+	for id := range f.products {
+		if id > afterID {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	products := make([]*entities.Product, 0, limit)
+	for _, id := range ids[:min(limit, len(ids))] {
+		products = append(products, f.products[id]())
+	}
+	return products, nil
+}
+
+// fakeProductVariantRepository keeps variants in memory, keyed by ID.
+type fakeProductVariantRepository struct {
+	repositories.ProductVariantRepository
+	variants map[int]*entities.ProductVariant
+}
+
+func (f *fakeProductVariantRepository) GetById(ctx context.Context, productID, id int) (*entities.ProductVariant, error) {
+	variant, ok := f.variants[id]
+	if !ok || variant.ProductID != productID {
+		return nil, repositories.ErrProductVariantNotFound
+	}
+	copied := *variant
+	return &copied, nil
+}
+
+func (f *fakeProductVariantRepository) UpdateStock(ctx context.Context, productID, id, quantity int) error {
+	variant, ok := f.variants[id]
+	if !ok || variant.ProductID != productID {
+		return repositories.ErrProductVariantNotFound
+	}
+	variant.StockQuantity = quantity
+	return nil
+}
+
+func TestProductVisibility(t *testing.T) {
+	shirt := func() *entities.Product {
+		return &entities.Product{
+			ID: 1, Slug: "shirt", IsActive: true, Options: []string{"size"},
+			Variants: []*entities.ProductVariant{
+				{ID: 10, SKU: "SHIRT-M", IsActive: true},
+				{ID: 11, SKU: "SHIRT-L", IsActive: false},
+			},
+			Images: []*entities.ProductImage{
+				{ID: 100},
+				{ID: 101, VariantID: intPtr(10)},
+				{ID: 102, VariantID: intPtr(11)},
+			},
+		}
+	}
+	retired := func() *entities.Product {
+		return &entities.Product{ID: 2, Slug: "retired", IsActive: false}
+	}
+	productUseCase := NewProductUseCase(&fakeProductRepository{products: map[int]func() *entities.Product{1: shirt, 2: retired}}, nil, nil, nil)
+	ctx := context.Background()
+
+	for _, activeOnly := range []bool{true, false} {
+		byID, err := productUseCase.GetById(ctx, 1, activeOnly)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bySlug, err := productUseCase.GetBySlug(ctx, "shirt", activeOnly)
+		if err != nil {
+			t.Fatal(err)
+		}
+		wantVariants, wantImages := 2, 3
+		if activeOnly {
+			wantVariants, wantImages = 1, 2
+		}
+		for _, res := range []*dto.ProductRes{byID, bySlug} {
+			if len(res.Variants) != wantVariants || len(res.Images) != wantImages {
+				t.Errorf("activeOnly %v: %d variants and %d images, want %d and %d", activeOnly, len(res.Variants), len(res.Images), wantVariants, wantImages)
+			}
+		}
+
+		_, errByID := productUseCase.GetById(ctx, 2, activeOnly)
+		_, errBySlug := productUseCase.GetBySlug(ctx, "retired", activeOnly)
+		for _, err := range []error{errByID, errBySlug} {
+			if activeOnly && !errors.Is(err, ErrProductNotFound) {
+				t.Errorf("inactive product for the public = %v, want ErrProductNotFound", err)
+			}
+			if !activeOnly && err != nil {
+				t.Errorf("inactive product for managers = %v", err)
+			}
+		}
+	}
+}
+
+func TestProductValidateChecksVariants(t *testing.T) {
+	shirt := func() *entities.Product {
+		return &entities.Product{
+			ID: 1, Options: []string{"size"},
+			Variants: []*entities.ProductVariant{
+				{ID: 10, SKU: "SHIRT-M", Options: map[string]string{"size": "M"}},
+			},
+		}
+	}
+	productUseCase := NewProductUseCase(&fakeProductRepository{products: map[int]func() *entities.Product{1: shirt}}, nil, nil, nil)
+	ctx := context.Background()
+
+	options := []string{"size", "color"}
+	if err := productUseCase.ValidateUpdate(ctx, &dto.UpdateProductReq{ID: 1, Options: &options}); !errors.Is(err, ErrOptionsInUse) {
+		t.Errorf("changing the options of a product with variants = %v, want ErrOptionsInUse", err)
+	}
+	variant := &dto.ProductVariantReq{SKU: "SHIRT-M2", Options: map[string]string{"size": " M "}}
+	if err := productUseCase.ValidateVariant(ctx, 1, 0, variant); !errors.Is(err, ErrDuplicateVariant) {
+		t.Errorf("adding a variant with taken options = %v, want ErrDuplicateVariant", err)
+	}
+	variant.Options = map[string]string{"color": "red"}
+	if err := productUseCase.ValidateVariant(ctx, 1, 0, variant); !errors.Is(err, ErrInvalidVariantOptions) {
+		t.Errorf("adding a variant with other options = %v, want ErrInvalidVariantOptions", err)
+	}
+}
+
+func TestProductUpdateVariantStock(t *testing.T) {
+	shirt := func() *entities.Product {
+		return &entities.Product{ID: 1, Price: 20, Options: []string{"size"}}
+	}
+	price := 25.0
+	variants := &fakeProductVariantRepository{variants: map[int]*entities.ProductVariant{
+		10: {ID: 10, ProductID: 1, SKU: "SHIRT-M", Options: map[string]string{"size": "M"}, Price: &price, StockQuantity: 3, IsActive: true},
+	}}
+	productUseCase := NewProductUseCase(&fakeProductRepository{products: map[int]func() *entities.Product{1: shirt}}, nil, variants, nil)
+	ctx := context.Background()
+
+	res, err := productUseCase.UpdateVariantStock(ctx, 3, 1, 10, 40)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StockQuantity != 40 || res.SKU != "SHIRT-M" || res.Price != 25 || !res.IsActive {
+		t.Errorf("variant after setting the stock = %+v, want stock 40 and the other fields unchanged", res)
+	}
+	if _, err := productUseCase.UpdateVariantStock(ctx, 3, 1, 11, 5); !errors.Is(err, ErrVariantNotFound) {
+		t.Errorf("stock of an unknown variant = %v, want ErrVariantNotFound", err)
+	}
+	if _, err := productUseCase.UpdateVariantStock(ctx, 3, 2, 10, 5); !errors.Is(err, ErrProductNotFound) {
+		t.Errorf("stock of a variant of an unknown product = %v, want ErrProductNotFound", err)
+	}
+}
+
+func TestProductListStatusFilter(t *testing.T) {
+	lamp := func() *entities.Product { return &entities.Product{ID: 1, Name: "Lamp", IsActive: true} }
+	retired := func() *entities.Product { return &entities.Product{ID: 2, Name: "Retired", IsActive: false} }
+	productUseCase := NewProductUseCase(&fakeProductRepository{products: map[int]func() *entities.Product{1: lamp, 2: retired}}, nil, nil, nil)
+	ctx := context.Background()
+
+	active, inactive := true, false
+	tests := []struct {
+		name     string
+		isActive *bool
+		want     []int
+	}{
+		{name: "active", isActive: &active, want: []int{1}},
+		{name: "inactive", isActive: &inactive, want: []int{2}},
+		{name: "any status", want: []int{1, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := productUseCase.List(ctx, &dto.ProductListReq{IsActive: tt.isActive})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var ids []int
+			for _, product := range res.Products {
+				ids = append(ids, product.ID)
+			}
+			if !slices.Equal(ids, tt.want) {
+				t.Errorf("listed products %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
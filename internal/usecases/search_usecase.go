@@ -0,0 +1,107 @@
+package usecases
+
+import (
+	"context"
+	"mini-ecommerce/internal/domain/entities"
+	"mini-ecommerce/internal/domain/repositories"
+	"mini-ecommerce/internal/infrastructure/search"
+	"mini-ecommerce/internal/interfaces/http/dto"
+	"mini-ecommerce/pkg/utils"
+)
+
+const (
+	// defaultSuggestionLimit fits an autocomplete dropdown
+	defaultSuggestionLimit = 8
+	// maxSuggestionLimit keeps suggestions cheap enough to run per keystroke
+	maxSuggestionLimit = 20
+)
+
+type SearchUsecase interface {
+	// Search returns active products ranked by relevance. When no product
+	// matches the query exactly, products with a similarly spelled name are
+	// returned instead and the result is marked fuzzy.
+	Search(ctx context.Context, req *dto.ProductSearchReq) (*dto.ProductSearchRes, error)
+	// Suggest completes the typed prefix to product names, at most 20.
+	Suggest(ctx context.Context, req *dto.ProductSuggestReq) ([]*dto.ProductSuggestionRes, error)
+}
+
+type searchUseCaseImpl struct {
+	index       search.SearchIndex
+	productRepo repositories.ProductRepository
+}
+
+// Search implements SearchUsecase.
+func (s *searchUseCaseImpl) Search(ctx context.Context, req *dto.ProductSearchReq) (*dto.ProductSearchRes, error) {
+	page, limit := utils.NormalizePage(req.Page, req.Limit, defaultProductLimit)
+	result, err := s.index.Search(ctx, search.Query{
+		Text:       req.Q,
+		CategoryID: req.CategoryID,
+		Offset:     utils.Offset(page, limit),
+		Limit:      limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int, 0, len(result.Hits))
+	for _, hit := range result.Hits {
+		ids = append(ids, hit.ProductID)
+	}
+	products, err := s.productRepo.ListByIds(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	productByID := make(map[int]*entities.Product, len(products))
+	for _, product := range products {
+		productByID[product.ID] = product
+	}
+	res := &dto.ProductSearchRes{
+		Products:   make([]*dto.ProductSearchHitRes, 0, len(result.Hits)),
+		Fuzzy:      result.Fuzzy,
+		Pagination: utils.NewPagination(page, limit, result.Total),
+	}
+	for _, hit := range result.Hits {
+		// An external index can lag behind deleted products.
+		product, ok := productByID[hit.ProductID]
+		if !ok {
+			continue
+		}
+		res.Products = append(res.Products, &dto.ProductSearchHitRes{
+			ProductRes: *toProductRes(product),
+			Score:      hit.Score,
+			Highlight: dto.SearchHighlightRes{
+				Name:    hit.Name,
+				Snippet: hit.Snippet,
+			},
+		})
+	}
+	return res, nil
+}
+
+// Suggest implements SearchUsecase.
+func (s *searchUseCaseImpl) Suggest(ctx context.Context, req *dto.ProductSuggestReq) ([]*dto.ProductSuggestionRes, error) {
+	limit := min(req.Limit, maxSuggestionLimit)
+	if limit <= 0 {
+		limit = defaultSuggestionLimit
+	}
+	suggestions, err := s.index.Suggest(ctx, req.Q, limit)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*dto.ProductSuggestionRes, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		res = append(res, &dto.ProductSuggestionRes{
+			ID:        suggestion.ProductID,
+			Name:      suggestion.Name,
+			Slug:      suggestion.Slug,
+			Highlight: suggestion.Highlight,
+		})
+	}
+	return res, nil
+}
+
+func NewSearchUseCase(index search.SearchIndex, productRepo repositories.ProductRepository) SearchUsecase {
+	return &searchUseCaseImpl{
+		index:       index,
+		productRepo: productRepo,
+	}
+}
@@ -0,0 +1,147 @@
+package usecases
+
+import (
+	"context"
+	"mini-ecommerce/internal/domain/entities"
+	"mini-ecommerce/internal/infrastructure/search"
+	"mini-ecommerce/internal/interfaces/http/dto"
+	"mini-ecommerce/pkg/utils"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// fakeSearchIndex matches names containing the query and, like the postgres
+// index, falls back to a looser match marked fuzzy when nothing matches:
+// names with a word starting with the query's first letter. It records the queries it got.
+type fakeSearchIndex struct {
+	names         map[int]string
+	queries       []search.Query
+	suggestLimits []int
+}
+
+func (f *fakeSearchIndex) Search(ctx context.Context, query search.Query) (*search.Result, error) {
+	f.queries = append(f.queries, query)
+	text := strings.ToLower(query.Text)
+	match := func(name string) bool { return strings.Contains(strings.ToLower(name), text) }
+	result := f.result(match)
+	if result.Total == 0 {
+		result = f.result(func(name string) bool {
+			return slices.ContainsFunc(strings.Fields(strings.ToLower(name)), func(word string) bool { return word[0] == text[0] })
+		})
+		result.Fuzzy = true
+	}
+	end := min(query.Offset+query.Limit, len(result.Hits))
+	result.Hits = result.Hits[min(query.Offset, end):end]
+	return result, nil
+}
+
+func (f *fakeSearchIndex) result(match func(name string) bool) *search.Result {
+	ids := make([]int, 0, len(f.names))
+	for id, name := range f.names {
+		if match(name) {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	result := &search.Result{Hits: make([]search.Hit, 0, len(ids)), Total: int64(len(ids))}
+	// Higher IDs rank first, so hits are not in the order products are loaded.
+	for i := len(ids) - 1; i >= 0; i-- {
+		result.Hits = append(result.Hits, search.Hit{ProductID: ids[i], Score: float64(ids[i]), Name: f.names[ids[i]]})
+	}
+	return result
+}
+
+func (f *fakeSearchIndex) Suggest(ctx context.Context, prefix string, limit int) ([]search.Suggestion, error) {
+	f.suggestLimits = append(f.suggestLimits, limit)
+	return []search.Suggestion{{ProductID: 1, Slug: "desk-lamp", Name: "Desk Lamp", Highlight: search.HighlightStart + "Desk" + search.HighlightEnd + " Lamp"}}, nil
+}
+
+func TestSearch(t *testing.T) {
+	names := map[int]string{1: "Desk Lamp", 2: "Floor Lamp", 3: "Lamp Shade", 4: "Laptop Stand"}
+	products := make(map[int]func() *entities.Product, len(names))
+	for id, name := range names {
+		products[id] = func() *entities.Product { return &entities.Product{ID: id, Name: name, IsActive: true} }
+	}
+	// Product 3 was deleted but the index has not caught up.
+	delete(products, 3)
+	index := &fakeSearchIndex{names: names}
+	searchUseCase := NewSearchUseCase(index, &fakeProductRepository{products: products})
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		req       dto.ProductSearchReq
+		wantIDs   []int
+		wantFuzzy bool
+		wantTotal int64
+		wantQuery search.Query
+	}{
+		{
+			name:      "full text",
+			req:       dto.ProductSearchReq{Q: "lamp"},
+			wantIDs:   []int{2, 1},
+			wantTotal: 3,
+			wantQuery: search.Query{Text: "lamp", Offset: 0, Limit: defaultProductLimit},
+		},
+		{
+			name:      "fallback",
+			req:       dto.ProductSearchReq{Q: "lmap"},
+			wantIDs:   []int{4, 2, 1},
+			wantFuzzy: true,
+			wantTotal: 4,
+			wantQuery: search.Query{Text: "lmap", Offset: 0, Limit: defaultProductLimit},
+		},
+		{
+			name:      "second page",
+			req:       dto.ProductSearchReq{Q: "lamp", Page: 2, Limit: 2},
+			wantIDs:   []int{1},
+			wantTotal: 3,
+			wantQuery: search.Query{Text: "lamp", Offset: 2, Limit: 2},
+		},
+		{
+			name:      "limit capped",
+			req:       dto.ProductSearchReq{Q: "lamp", Limit: 1000},
+			wantIDs:   []int{2, 1},
+			wantTotal: 3,
+			wantQuery: search.Query{Text: "lamp", Offset: 0, Limit: utils.MaxLimit},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := searchUseCase.Search(ctx, &tt.req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var ids []int
+			for _, product := range res.Products {
+				ids = append(ids, product.ID)
+			}
+			if !slices.Equal(ids, tt.wantIDs) || res.Fuzzy != tt.wantFuzzy || res.Pagination.TotalItems != tt.wantTotal {
+				t.Errorf("got products %v, fuzzy %v, total %d; want %v, %v, %d", ids, res.Fuzzy, res.Pagination.TotalItems, tt.wantIDs, tt.wantFuzzy, tt.wantTotal)
+			}
+			if query := index.queries[len(index.queries)-1]; query.Text != tt.wantQuery.Text || query.Offset != tt.wantQuery.Offset || query.Limit != tt.wantQuery.Limit {
+				t.Errorf("index queried with %+v, want %+v", query, tt.wantQuery)
+			}
+		})
+	}
+}
+
+func TestSuggestLimit(t *testing.T) {
+	index := &fakeSearchIndex{}
+	searchUseCase := NewSearchUseCase(index, nil)
+	ctx := context.Background()
+
+	for _, limit := range []int{0, 5, 20, 21, 1000} {
+		res, err := searchUseCase.Suggest(ctx, &dto.ProductSuggestReq{Q: "desk", Limit: limit})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res) != 1 || res[0].Highlight != "<mark>Desk</mark> Lamp" {
+			t.Errorf("suggestions %+v, want the index's suggestion", res)
+		}
+	}
+	if want := []int{defaultSuggestionLimit, 5, 20, 20, 20}; !slices.Equal(index.suggestLimits, want) {
+		t.Errorf("index asked for %v suggestions, want %v", index.suggestLimits, want)
+	}
+}
@@ -0,0 +1,85 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"mini-ecommerce/internal/domain/repositories"
+	"mini-ecommerce/internal/interfaces/http/dto"
+	"mini-ecommerce/pkg/logger"
+	"time"
+)
+
+var ErrSessionNotFound = errors.New("session not found")
+
+type SessionUsecase interface {
+	List(ctx context.Context, userID int, currentID string) ([]dto.SessionRes, error)
+	Revoke(ctx context.Context, userID int, id string) error
+	RevokeOthers(ctx context.Context, userID int, currentID string) error
+	// RevokeAll force-signs a user out of every device.
+	RevokeAll(ctx context.Context, userID int) error
+}
+
+type sessionUseCaseImpl struct {
+	sessionRepo repositories.SessionRepository
+	userRepo    repositories.UserRepository
+}
+
+// List implements SessionUsecase.
+func (s *sessionUseCaseImpl) List(ctx context.Context, userID int, currentID string) ([]dto.SessionRes, error) {
+	sessions, err := s.sessionRepo.ListActiveByUserId(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]dto.SessionRes, 0, len(sessions))
+	for _, session := range sessions {
+		res = append(res, dto.SessionRes{
+			ID:             session.ID,
+			DeviceID:       session.DeviceID,
+			UserAgent:      session.UserAgent,
+			IPAddress:      session.IPAddress,
+			Current:        session.ID == currentID,
+			LastActivityAt: session.LastActivityAt.Format(time.RFC3339),
+			ExpiresAt:      session.ExpiresAt.Format(time.RFC3339),
+			CreatedAt:      session.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	return res, nil
+}
+
+// Revoke implements SessionUsecase.
+func (s *sessionUseCaseImpl) Revoke(ctx context.Context, userID int, id string) error {
+	if err := s.sessionRepo.Revoke(ctx, userID, id); err != nil {
+		if errors.Is(err, repositories.ErrSessionNotFound) {
+			return ErrSessionNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// RevokeOthers implements SessionUsecase.
+func (s *sessionUseCaseImpl) RevokeOthers(ctx context.Context, userID int, currentID string) error {
+	return s.sessionRepo.RevokeAllByUserId(ctx, userID, currentID)
+}
+
+// RevokeAll implements SessionUsecase.
+func (s *sessionUseCaseImpl) RevokeAll(ctx context.Context, userID int) error {
+	if _, err := s.userRepo.GetById(ctx, userID); err != nil {
+		if err.Error() == "user not found" {
+			return ErrUserNotFound
+		}
+		return err
+	}
+	if err := s.sessionRepo.RevokeAllByUserId(ctx, userID, ""); err != nil {
+		return err
+	}
+	logger.Infof("[SessionUsecase] all sessions revoked for user %d", userID)
+	return nil
+}
+
+func NewSessionUseCase(sessionRepo repositories.SessionRepository, userRepo repositories.UserRepository) SessionUsecase {
+	return &sessionUseCaseImpl{
+		sessionRepo: sessionRepo,
+		userRepo:    userRepo,
+	}
+}
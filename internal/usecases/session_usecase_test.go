@@ -0,0 +1,78 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"mini-ecommerce/internal/domain/entities"
+	"mini-ecommerce/internal/interfaces/http/dto"
+	"testing"
+	"time"
+)
+
+func TestSessionRevoke(t *testing.T) {
+	userRepo := &fakeUserRepository{users: map[int]*entities.User{
+		1: {ID: 1, Email: "user@example.com", Role: entities.RoleCustomer, IsActive: true},
+	}}
+	sessions, tokens := newTestSessions("phone", "laptop")
+	sessionUseCase := NewSessionUseCase(sessions, userRepo)
+	authUseCase := newTestAuthUsecase(userRepo, sessions, tokens, &fakePasswordResetRepository{}, &fakeMailer{}, AuthSettings{})
+	ctx := context.Background()
+
+	if err := sessionUseCase.Revoke(ctx, 1, "phone"); err != nil {
+		t.Fatal(err)
+	}
+	if sessions.sessions["phone"].RevokedAt == nil || len(tokens.active("phone")) != 0 {
+		t.Error("the revoked session or its refresh tokens are still active")
+	}
+	if _, err := authUseCase.Refresh(ctx, &dto.RefreshTokenReq{RefreshToken: "phone-token"}); err == nil {
+		t.Error("the refresh token of a revoked session still works")
+	}
+	if sessions.sessions["laptop"].RevokedAt != nil || len(tokens.active("laptop")) != 1 {
+		t.Error("revoking one session ended another")
+	}
+	if err := sessionUseCase.Revoke(ctx, 1, "phone"); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("revoking a revoked session = %v, want ErrSessionNotFound", err)
+	}
+	if err := sessionUseCase.Revoke(ctx, 2, "laptop"); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("revoking another user's session = %v, want ErrSessionNotFound", err)
+	}
+	if sessions.sessions["laptop"].RevokedAt != nil {
+		t.Error("another user revoked the session")
+	}
+}
+
+func TestSessionRevokeOthersAndAll(t *testing.T) {
+	userRepo := &fakeUserRepository{users: map[int]*entities.User{
+		1: {ID: 1, Role: entities.RoleCustomer, IsActive: true},
+		2: {ID: 2, Role: entities.RoleCustomer, IsActive: true},
+	}}
+	sessions, tokens := newTestSessions("phone", "laptop", "tablet")
+	sessions.sessions["other"] = &entities.Session{ID: "other", UserID: 2, ExpiresAt: time.Now().Add(time.Hour)}
+	sessionUseCase := NewSessionUseCase(sessions, userRepo)
+	ctx := context.Background()
+
+	if err := sessionUseCase.RevokeOthers(ctx, 1, "laptop"); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"phone", "tablet"} {
+		if sessions.sessions[id].RevokedAt == nil || len(tokens.active(id)) != 0 {
+			t.Errorf("session %q or its refresh tokens are still active", id)
+		}
+	}
+	if sessions.sessions["laptop"].RevokedAt != nil || len(tokens.active("laptop")) != 1 {
+		t.Error("the current session was ended")
+	}
+
+	if err := sessionUseCase.RevokeAll(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if sessions.sessions["laptop"].RevokedAt == nil || len(tokens.active("laptop")) != 0 {
+		t.Error("signing the user out left a session active")
+	}
+	if sessions.sessions["other"].RevokedAt != nil {
+		t.Error("signing one user out ended another user's session")
+	}
+	if err := sessionUseCase.RevokeAll(ctx, 3); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("signing out an unknown user = %v, want ErrUserNotFound", err)
+	}
+}
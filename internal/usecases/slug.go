@@ -0,0 +1,36 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mini-ecommerce/pkg/utils"
+)
+
+const maxSlugAttempts = 50
+
+var ErrSlugTaken = errors.New("slug is already in use")
+
+// uniqueSlug derives a slug from name, falling back to fallback when the name
+// has no ASCII letters or digits, and appends -2, -3, ... until taken
+// reports it free.
+func uniqueSlug(ctx context.Context, name, fallback string, taken func(ctx context.Context, slug string) (bool, error)) (string, error) {
+	base := utils.Slugify(name)
+	if base == "" {
+		base = fallback
+	}
+	for i := 1; i <= maxSlugAttempts; i++ {
+		slug := base
+		if i > 1 {
+			slug = fmt.Sprintf("%s-%d", base, i)
+		}
+		exists, err := taken(ctx, slug)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return slug, nil
+		}
+	}
+	return "", ErrSlugTaken
+}
@@ -0,0 +1,48 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestUniqueSlug(t *testing.T) {
+	takenSlugs := func(slugs ...string) func(context.Context, string) (bool, error) {
+		set := make(map[string]bool, len(slugs))
+		for _, slug := range slugs {
+			set[slug] = true
+		}
+		return func(ctx context.Context, slug string) (bool, error) {
+			return set[slug], nil
+		}
+	}
+	tests := []struct {
+		name     string
+		input    string
+		taken    []string
+		fallback string
+		want     string
+	}{
+		{name: "free", input: "Running Shoes", want: "running-shoes"},
+		{name: "taken", input: "Running Shoes", taken: []string{"running-shoes"}, want: "running-shoes-2"},
+		{name: "suffix taken", input: "Running Shoes", taken: []string{"running-shoes", "running-shoes-2"}, want: "running-shoes-3"},
+		{name: "fallback", input: "日本語", fallback: "product-7", want: "product-7"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := uniqueSlug(context.Background(), tt.input, tt.fallback, takenSlugs(tt.taken...))
+			if err != nil || got != tt.want {
+				t.Errorf("uniqueSlug = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+
+	all := []string{"shoes"}
+	for i := 2; i <= maxSlugAttempts; i++ {
+		all = append(all, "shoes-"+strconv.Itoa(i))
+	}
+	if _, err := uniqueSlug(context.Background(), "Shoes", "", takenSlugs(all...)); !errors.Is(err, ErrSlugTaken) {
+		t.Errorf("uniqueSlug with every attempt taken = %v, want ErrSlugTaken", err)
+	}
+}
@@ -0,0 +1,227 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"mini-ecommerce/internal/domain/entities"
+	"mini-ecommerce/internal/domain/repositories"
+	"mini-ecommerce/internal/infrastructure/auth"
+	"mini-ecommerce/internal/interfaces/http/dto"
+	"mini-ecommerce/pkg/logger"
+	"time"
+)
+
+const recoveryCodeCount = 10
+
+var (
+	ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnabled     = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorNotSetUp       = errors.New("two-factor setup has not been started")
+	ErrInvalidTwoFactorCode    = errors.New("invalid two-factor code")
+)
+
+type TwoFactorUsecase interface {
+	Status(ctx context.Context, userID int, role string) (*dto.TwoFactorStatusRes, error)
+	Setup(ctx context.Context, userID int, email string) (*dto.TwoFactorSetupRes, error)
+	Enable(ctx context.Context, userID int, req *dto.TwoFactorCodeReq) (*dto.TwoFactorRecoveryCodesRes, error)
+	Disable(ctx context.Context, userID int, req *dto.TwoFactorCodeReq) error
+	RegenerateRecoveryCodes(ctx context.Context, userID int, req *dto.TwoFactorCodeReq) (*dto.TwoFactorRecoveryCodesRes, error)
+	// Verify accepts a TOTP code or consumes a recovery code of an enrolled user.
+	Verify(ctx context.Context, userID int, code string) error
+	IsRequired(role string) bool
+}
+
+type twoFactorUseCaseImpl struct {
+	twoFactorRepo repositories.TwoFactorRepository
+	cipher        auth.SecretCipher
+	issuer        string
+	requiredRoles map[string]bool
+}
+
+// Status implements TwoFactorUsecase.
+func (t *twoFactorUseCaseImpl) Status(ctx context.Context, userID int, role string) (*dto.TwoFactorStatusRes, error) {
+	res := &dto.TwoFactorStatusRes{Required: t.IsRequired(role)}
+	twoFactor, err := t.twoFactorRepo.GetByUserId(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrTwoFactorNotFound) {
+			return res, nil
+		}
+		return nil, err
+	}
+	res.Enabled = twoFactor.ConfirmedAt != nil
+	if res.Enabled {
+		res.RecoveryCodesRemaining, err = t.twoFactorRepo.CountRecoveryCodes(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// Setup implements TwoFactorUsecase.
+// It stores a new pending secret; enrollment only takes effect once Enable
+// confirms a code generated from it.
+func (t *twoFactorUseCaseImpl) Setup(ctx context.Context, userID int, email string) (*dto.TwoFactorSetupRes, error) {
+	existing, err := t.twoFactorRepo.GetByUserId(ctx, userID)
+	if err != nil && !errors.Is(err, repositories.ErrTwoFactorNotFound) {
+		return nil, err
+	}
+	if existing != nil && existing.ConfirmedAt != nil {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := t.cipher.Encrypt(secret)
+	if err != nil {
+		logger.Error(err, "[ErrTwoFactorUsecase-1] failed to encrypt totp secret")
+		return nil, err
+	}
+	if err := t.twoFactorRepo.SavePending(ctx, &entities.UserTwoFactor{
+		UserID:          userID,
+		SecretEncrypted: encrypted,
+	}); err != nil {
+		return nil, err
+	}
+	return &dto.TwoFactorSetupRes{
+		Secret:          secret,
+		ProvisioningURI: auth.TOTPProvisioningURI(t.issuer, email, secret),
+	}, nil
+}
+
+// Enable implements TwoFactorUsecase.
+func (t *twoFactorUseCaseImpl) Enable(ctx context.Context, userID int, req *dto.TwoFactorCodeReq) (*dto.TwoFactorRecoveryCodesRes, error) {
+	twoFactor, err := t.twoFactorRepo.GetByUserId(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrTwoFactorNotFound) {
+			return nil, ErrTwoFactorNotSetUp
+		}
+		return nil, err
+	}
+	if twoFactor.ConfirmedAt != nil {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+	step, err := t.matchTOTP(twoFactor, req.Code)
+	if err != nil {
+		return nil, err
+	}
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := t.twoFactorRepo.Enable(ctx, userID, step, hashes); err != nil {
+		if errors.Is(err, repositories.ErrTwoFactorNotFound) {
+			return nil, ErrTwoFactorNotSetUp
+		}
+		return nil, err
+	}
+	logger.Infof("[TwoFactorUsecase] two-factor authentication enabled for user %d", userID)
+	return &dto.TwoFactorRecoveryCodesRes{RecoveryCodes: codes}, nil
+}
+
+// Disable implements TwoFactorUsecase.
+func (t *twoFactorUseCaseImpl) Disable(ctx context.Context, userID int, req *dto.TwoFactorCodeReq) error {
+	if err := t.Verify(ctx, userID, req.Code); err != nil {
+		return err
+	}
+	if err := t.twoFactorRepo.Disable(ctx, userID); err != nil {
+		return err
+	}
+	logger.Infof("[TwoFactorUsecase] two-factor authentication disabled for user %d", userID)
+	return nil
+}
+
+// RegenerateRecoveryCodes implements TwoFactorUsecase.
+func (t *twoFactorUseCaseImpl) RegenerateRecoveryCodes(ctx context.Context, userID int, req *dto.TwoFactorCodeReq) (*dto.TwoFactorRecoveryCodesRes, error) {
+	if err := t.Verify(ctx, userID, req.Code); err != nil {
+		return nil, err
+	}
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := t.twoFactorRepo.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+	return &dto.TwoFactorRecoveryCodesRes{RecoveryCodes: codes}, nil
+}
+
+// Verify implements TwoFactorUsecase.
+func (t *twoFactorUseCaseImpl) Verify(ctx context.Context, userID int, code string) error {
+	twoFactor, err := t.twoFactorRepo.GetByUserId(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrTwoFactorNotFound) {
+			return ErrTwoFactorNotEnabled
+		}
+		return err
+	}
+	if twoFactor.ConfirmedAt == nil {
+		return ErrTwoFactorNotEnabled
+	}
+	step, err := t.matchTOTP(twoFactor, code)
+	if err == nil {
+		if err := t.twoFactorRepo.UseStep(ctx, userID, step); err != nil {
+			if errors.Is(err, repositories.ErrTOTPStepReplayed) {
+				return ErrInvalidTwoFactorCode
+			}
+			return err
+		}
+		return nil
+	}
+	if !errors.Is(err, ErrInvalidTwoFactorCode) {
+		return err
+	}
+	err = t.twoFactorRepo.UseRecoveryCode(ctx, userID, auth.HashToken(auth.NormalizeRecoveryCode(code)))
+	if err != nil {
+		if errors.Is(err, repositories.ErrRecoveryCodeNotFound) {
+			return ErrInvalidTwoFactorCode
+		}
+		return err
+	}
+	logger.Infof("[TwoFactorUsecase] recovery code used by user %d", userID)
+	return nil
+}
+
+// IsRequired implements TwoFactorUsecase.
+func (t *twoFactorUseCaseImpl) IsRequired(role string) bool {
+	return t.requiredRoles[role]
+}
+
+func (t *twoFactorUseCaseImpl) matchTOTP(twoFactor *entities.UserTwoFactor, code string) (int64, error) {
+	secret, err := t.cipher.Decrypt(twoFactor.SecretEncrypted)
+	if err != nil {
+		logger.Error(err, "[ErrTwoFactorUsecase-2] failed to decrypt totp secret")
+		return 0, err
+	}
+	step, ok := auth.ValidateTOTP(secret, code, time.Now())
+	if !ok || step <= twoFactor.LastUsedStep {
+		return 0, ErrInvalidTwoFactorCode
+	}
+	return step, nil
+}
+
+func newRecoveryCodes() ([]string, []string, error) {
+	codes, err := auth.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, nil, err
+	}
+	hashes := make([]string, 0, len(codes))
+	for _, code := range codes {
+		hashes = append(hashes, auth.HashToken(auth.NormalizeRecoveryCode(code)))
+	}
+	return codes, hashes, nil
+}
+
+func NewTwoFactorUseCase(twoFactorRepo repositories.TwoFactorRepository, cipher auth.SecretCipher, issuer string, requiredRoles []string) TwoFactorUsecase {
+	required := make(map[string]bool, len(requiredRoles))
+	for _, role := range requiredRoles {
+		required[role] = true
+	}
+	return &twoFactorUseCaseImpl{
+		twoFactorRepo: twoFactorRepo,
+		cipher:        cipher,
+		issuer:        issuer,
+		requiredRoles: required,
+	}
+}
@@ -0,0 +1,140 @@
+package usecases
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"mini-ecommerce/internal/domain/entities"
+	"mini-ecommerce/internal/domain/repositories"
+	"mini-ecommerce/internal/infrastructure/auth"
+	"testing"
+	"time"
+)
+
+// fakeTwoFactorRepository keeps one enrollment in memory; the methods the
+// tests do not reach are left to the embedded nil interface.
+type fakeTwoFactorRepository struct {
+	repositories.TwoFactorRepository
+	twoFactor     *entities.UserTwoFactor
+	recoveryCodes map[string]bool
+	// replayed makes UseStep report a step consumed by a concurrent request
+	replayed bool
+}
+
+func (f *fakeTwoFactorRepository) GetByUserId(ctx context.Context, userID int) (*entities.UserTwoFactor, error) {
+	if f.twoFactor == nil || f.twoFactor.UserID != userID {
+		return nil, repositories.ErrTwoFactorNotFound
+	}
+	copied := *f.twoFactor
+	return &copied, nil
+}
+
+func (f *fakeTwoFactorRepository) UseStep(ctx context.Context, userID int, step int64) error {
+	if f.replayed || step <= f.twoFactor.LastUsedStep {
+		return repositories.ErrTOTPStepReplayed
+	}
+	f.twoFactor.LastUsedStep = step
+	return nil
+}
+
+func (f *fakeTwoFactorRepository) UseRecoveryCode(ctx context.Context, userID int, codeHash string) error {
+	if !f.recoveryCodes[codeHash] {
+		return repositories.ErrRecoveryCodeNotFound
+	}
+	delete(f.recoveryCodes, codeHash)
+	return nil
+}
+
+// currentTOTP computes the code an authenticator app shows for secret now.
+func currentTOTP(t *testing.T, secret string) string {
+	t.Helper()
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(time.Now().Unix()/30))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	return fmt.Sprintf("%06d", (binary.BigEndian.Uint32(sum[offset:offset+4])&0x7fffffff)%1000000)
+}
+
+func newTestTwoFactor(t *testing.T) (TwoFactorUsecase, *fakeTwoFactorRepository, string) {
+	t.Helper()
+	cipher, err := auth.NewSecretCipher("test key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := cipher.Encrypt(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	confirmedAt := time.Now()
+	repo := &fakeTwoFactorRepository{
+		twoFactor: &entities.UserTwoFactor{
+			UserID:          1,
+			SecretEncrypted: encrypted,
+			ConfirmedAt:     &confirmedAt,
+		},
+		recoveryCodes: map[string]bool{
+			auth.HashToken("abcde-fghjk"): true,
+		},
+	}
+	return NewTwoFactorUseCase(repo, cipher, "test", nil), repo, secret
+}
+
+func TestTwoFactorVerifyRejectsReplayedCode(t *testing.T) {
+	ctx := context.Background()
+	twoFactor, _, secret := newTestTwoFactor(t)
+	code := currentTOTP(t, secret)
+
+	if err := twoFactor.Verify(ctx, 1, code); err != nil {
+		t.Fatalf("first use of the code: %v", err)
+	}
+	if err := twoFactor.Verify(ctx, 1, code); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Fatalf("second use of the code = %v, want ErrInvalidTwoFactorCode", err)
+	}
+}
+
+func TestTwoFactorVerifyRejectsConcurrentReplay(t *testing.T) {
+	twoFactor, repo, secret := newTestTwoFactor(t)
+	repo.replayed = true
+	if err := twoFactor.Verify(context.Background(), 1, currentTOTP(t, secret)); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Fatalf("Verify = %v, want ErrInvalidTwoFactorCode", err)
+	}
+}
+
+func TestTwoFactorVerifyRecoveryCode(t *testing.T) {
+	ctx := context.Background()
+	twoFactor, _, _ := newTestTwoFactor(t)
+
+	if err := twoFactor.Verify(ctx, 1, " ABCDE-FGHJK "); err != nil {
+		t.Fatalf("first use of the recovery code: %v", err)
+	}
+	if err := twoFactor.Verify(ctx, 1, "abcde-fghjk"); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Fatalf("second use of the recovery code = %v, want ErrInvalidTwoFactorCode", err)
+	}
+}
+
+func TestTwoFactorVerifyNotEnabled(t *testing.T) {
+	ctx := context.Background()
+	twoFactor, repo, secret := newTestTwoFactor(t)
+
+	if err := twoFactor.Verify(ctx, 2, "123456"); !errors.Is(err, ErrTwoFactorNotEnabled) {
+		t.Fatalf("Verify for a user without enrollment = %v, want ErrTwoFactorNotEnabled", err)
+	}
+	repo.twoFactor.ConfirmedAt = nil
+	if err := twoFactor.Verify(ctx, 1, currentTOTP(t, secret)); !errors.Is(err, ErrTwoFactorNotEnabled) {
+		t.Fatalf("Verify for a pending enrollment = %v, want ErrTwoFactorNotEnabled", err)
+	}
+}
@@ -8,86 +8,256 @@ import (
 	"mini-ecommerce/internal/infrastructure/auth"
 	"mini-ecommerce/internal/interfaces/http/dto"
 	"mini-ecommerce/pkg/logger"
+	"mini-ecommerce/pkg/utils"
+	"strings"
 	"time"
 )
 
+var (
+	ErrUserNotFound           = errors.New("user not found")
+	ErrInvalidRole            = errors.New("invalid role")
+	ErrCannotModifySelf       = errors.New("cannot change your own role or status")
+	ErrInvalidCurrentPassword = errors.New("current password is missing or incorrect")
+)
+
 type UserUsecase interface {
-	Login(ctx context.Context, req *dto.UserLoginReq) (*dto.UserLoginRes, error)
 	GetById(ctx context.Context, id int) (*dto.UserRes, error)
-	GetByEmail(ctx context.Context, email string) (*dto.UserRes, error)
-	Create(ctx context.Context, req *dto.UserReq) (*dto.UserRes, error)
-	Update(ctx context.Context, req *dto.UserReq) (*dto.UserRes, error)
-	Delete(ctx context.Context, id int) error
+	GetProfile(ctx context.Context, id int) (*dto.ProfileRes, error)
+	Update(ctx context.Context, req *dto.UpdateProfileReq) (*dto.UserRes, error)
+	Delete(ctx context.Context, actorID, id int) error
+	List(ctx context.Context, req *dto.UserListReq) (*dto.UserListRes, error)
+	UpdateRole(ctx context.Context, actorID, id int, req *dto.UpdateUserRoleReq) (*dto.UserRes, error)
+	SetActive(ctx context.Context, actorID, id int, active bool) (*dto.UserRes, error)
 }
 
 type userUseCaseImpl struct {
-	userRepo repositories.UserRepository
+	userRepo    repositories.UserRepository
+	sessionRepo repositories.SessionRepository
+	addressRepo repositories.AddressRepository
+	hasher      auth.PasswordHasher
+	authUseCase AuthUsecase
+}
+
+// Delete implements UserUsecase.
+// The account is anonymized rather than removed so its orders and payments
+// stay intact.
+func (u *userUseCaseImpl) Delete(ctx context.Context, actorID, id int) error {
+	if actorID == id {
+		return ErrCannotModifySelf
+	}
+	if err := u.userRepo.Anonymize(ctx, id); err != nil {
+		if err.Error() == "user not found" {
+			return ErrUserNotFound
+		}
+		return err
+	}
+	logger.Infof("[UserUsecase] user %d erased user %d", actorID, id)
+	return nil
 }
 
-// Create implements UserUsecase.
-func (u *userUseCaseImpl) Create(ctx context.Context, req *dto.UserReq) (*dto.UserRes, error) {
-	exists, err := u.userRepo.GetByEmail(ctx, req.Email)
+// GetById implements UserUsecase.
+func (u *userUseCaseImpl) GetById(ctx context.Context, id int) (*dto.UserRes, error) {
+	user, err := u.getUser(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	if exists != nil {
-		logger.Error(errors.New("unprocessable Entity"), "[ErrUserUsecase-1] email already exists")
-		return nil, errors.New("email already exists")
+	return toUserRes(user), nil
+}
+
+// GetProfile implements UserUsecase.
+func (u *userUseCaseImpl) GetProfile(ctx context.Context, id int) (*dto.ProfileRes, error) {
+	user, err := u.getUser(ctx, id)
+	if err != nil {
+		return nil, err
 	}
-	hashedPassword, err := auth.HashPassword(req.Password)
+	addresses, err := u.addressRepo.ListByUserId(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	user := &entities.User{
-		Email:    req.Email,
-		Name:     req.Name,
-		Password: hashedPassword,
-		Phone:    req.Phone,
+	return &dto.ProfileRes{
+		UserRes:   toUserRes(user),
+		Addresses: toAddressResList(addresses),
+	}, nil
+}
+
+// List implements UserUsecase.
+// A non-empty search term matches name, email or phone.
+func (u *userUseCaseImpl) List(ctx context.Context, req *dto.UserListReq) (*dto.UserListRes, error) {
+	page, limit := utils.NormalizePage(req.Page, req.Limit, utils.DefaultLimit)
+	filter := repositories.UserFilter{
 		Role:     req.Role,
+		IsActive: req.IsActive,
+		Offset:   utils.Offset(page, limit),
+		Limit:    limit,
+	}
+	var (
+		users []*entities.User
+		total int64
+		err   error
+	)
+	if search := strings.TrimSpace(req.Search); search != "" {
+		users, total, err = u.userRepo.Search(ctx, search, filter)
+	} else {
+		users, total, err = u.userRepo.List(ctx, filter)
 	}
-	err = u.userRepo.Create(ctx, user)
 	if err != nil {
 		return nil, err
 	}
-	return &dto.UserRes{
-		ID:        user.ID,
-		Email:     user.Email,
-		Name:      user.Name,
-		Phone:     user.Phone,
-		Role:      user.Role,
-		CreatedAt: time.Now().Format(time.RFC3339),
-		UpdatedAt: time.Now().Format(time.RFC3339),
-	}, nil
+	res := &dto.UserListRes{
+		Users:      make([]*dto.UserRes, 0, len(users)),
+		Pagination: utils.NewPagination(page, limit, total),
+	}
+	for _, user := range users {
+		res.Users = append(res.Users, toUserRes(user))
+	}
+	return res, nil
+}
 
+// UpdateRole implements UserUsecase.
+func (u *userUseCaseImpl) UpdateRole(ctx context.Context, actorID, id int, req *dto.UpdateUserRoleReq) (*dto.UserRes, error) {
+	if !entities.IsValidRole(req.Role) {
+		return nil, ErrInvalidRole
+	}
+	if actorID == id {
+		return nil, ErrCannotModifySelf
+	}
+	user, err := u.getUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.userRepo.SetRole(ctx, id, req.Role); err != nil {
+		return nil, userUpdateError(err)
+	}
+	user.Role = req.Role
+	logger.Infof("[UserUsecase] user %d changed role of user %d to %s", actorID, id, req.Role)
+	return toUserRes(user), nil
 }
 
-// Delete implements UserUsecase.
-func (u *userUseCaseImpl) Delete(ctx context.Context, id int) error {
-	panic("unimplemented")
+// SetActive implements UserUsecase.
+func (u *userUseCaseImpl) SetActive(ctx context.Context, actorID, id int, active bool) (*dto.UserRes, error) {
+	if actorID == id {
+		return nil, ErrCannotModifySelf
+	}
+	user, err := u.getUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.userRepo.SetActive(ctx, id, active); err != nil {
+		return nil, userUpdateError(err)
+	}
+	user.IsActive = active
+	logger.Infof("[UserUsecase] user %d set is_active=%t for user %d", actorID, active, id)
+	return toUserRes(user), nil
 }
 
-// GetByEmail implements UserUsecase.
-func (u *userUseCaseImpl) GetByEmail(ctx context.Context, email string) (*dto.UserRes, error) {
-	panic("unimplemented")
+func (u *userUseCaseImpl) getUser(ctx context.Context, id int) (*entities.User, error) {
+	user, err := u.userRepo.GetById(ctx, id)
+	if err != nil {
+		if err.Error() == "user not found" {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return user, nil
 }
 
-// GetById implements UserUsecase.
-func (u *userUseCaseImpl) GetById(ctx context.Context, id int) (*dto.UserRes, error) {
-	panic("unimplemented")
+// userUpdateError maps a user that disappeared or was anonymized since it
+// was read.
+func userUpdateError(err error) error {
+	if err.Error() == "user not found" {
+		return ErrUserNotFound
+	}
+	return err
 }
 
-// Login implements UserUsecase.
-func (u *userUseCaseImpl) Login(ctx context.Context, req *dto.UserLoginReq) (*dto.UserLoginRes, error) {
-	panic("unimplemented")
+func (u *userUseCaseImpl) ensureEmailAvailable(ctx context.Context, email string) error {
+	_, err := u.userRepo.GetByEmail(ctx, email)
+	if err == nil {
+		return ErrEmailExists
+	}
+	if err.Error() != "user not found" {
+		return err
+	}
+	return nil
 }
 
 // Update implements UserUsecase.
-func (u *userUseCaseImpl) Update(ctx context.Context, req *dto.UserReq) (*dto.UserRes, error) {
-	panic("unimplemented")
+// A new email must be verified again. A new password signs out every other
+// session and invalidates access tokens issued before the change, so the
+// current session has to refresh its access token.
+func (u *userUseCaseImpl) Update(ctx context.Context, req *dto.UpdateProfileReq) (*dto.UserRes, error) {
+	user, err := u.getUser(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	emailChanged := req.Email != nil && *req.Email != user.Email
+	if emailChanged || req.NewPassword != nil {
+		if req.CurrentPassword == "" || !u.hasher.Verify(req.CurrentPassword, user.Password) {
+			return nil, ErrInvalidCurrentPassword
+		}
+	}
+	update := repositories.UserProfileUpdate{
+		Name:  req.Name,
+		Phone: req.Phone,
+	}
+	if req.Name != nil {
+		user.Name = *req.Name
+	}
+	if req.Phone != nil {
+		user.Phone = *req.Phone
+	}
+	if emailChanged {
+		if err := u.ensureEmailAvailable(ctx, *req.Email); err != nil {
+			return nil, err
+		}
+		update.Email = req.Email
+		user.Email = *req.Email
+		user.EmailVerified = false
+	}
+	if req.NewPassword != nil {
+		hashedPassword, err := u.hasher.Hash(*req.NewPassword)
+		if err != nil {
+			return nil, err
+		}
+		update.Password = &hashedPassword
+	}
+	if err := u.userRepo.UpdateProfile(ctx, user.ID, update); err != nil {
+		return nil, userUpdateError(err)
+	}
+	if req.NewPassword != nil {
+		if err := u.sessionRepo.RevokeAllByUserId(ctx, user.ID, req.SessionID); err != nil {
+			logger.Error(err, "[ErrUserUsecase-1] failed to revoke sessions after password change")
+			return nil, err
+		}
+	}
+	if emailChanged {
+		if err := u.authUseCase.ResendVerification(ctx, &dto.ResendVerificationReq{Email: user.Email}); err != nil {
+			logger.Error(err, "[ErrUserUsecase-2] failed to send verification email")
+		}
+	}
+	return toUserRes(user), nil
+}
+
+func toUserRes(user *entities.User) *dto.UserRes {
+	return &dto.UserRes{
+		ID:            user.ID,
+		Email:         user.Email,
+		Name:          user.Name,
+		Phone:         user.Phone,
+		Role:          user.Role,
+		EmailVerified: user.EmailVerified,
+		IsActive:      user.IsActive,
+		CreatedAt:     user.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:     user.UpdatedAt.Format(time.RFC3339),
+	}
 }
 
-func NewUserUseCase(userRepo repositories.UserRepository) UserUsecase {
+func NewUserUseCase(userRepo repositories.UserRepository, sessionRepo repositories.SessionRepository, addressRepo repositories.AddressRepository, hasher auth.PasswordHasher, authUseCase AuthUsecase) UserUsecase {
 	return &userUseCaseImpl{
-		userRepo: userRepo,
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+		addressRepo: addressRepo,
+		hasher:      hasher,
+		authUseCase: authUseCase,
 	}
 }
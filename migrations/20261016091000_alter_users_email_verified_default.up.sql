@@ -0,0 +1 @@
+ALTER TABLE users ALTER COLUMN email_verified SET DEFAULT FALSE;
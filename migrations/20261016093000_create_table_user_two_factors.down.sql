@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS two_factor_recovery_codes;
+DROP TABLE IF EXISTS user_two_factors;
+ALTER TABLE users DROP COLUMN IF EXISTS two_factor_enabled;
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- Databases created from init.sql before the warehouse and support roles
+-- still carry the old check, which rejects them.
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+ALTER TABLE users ADD CONSTRAINT users_role_check CHECK (role IN ('customer', 'admin', 'warehouse', 'support'));
@@ -0,0 +1 @@
+ALTER TABLE user_addresses ALTER COLUMN country SET DEFAULT 'USA';
\ No newline at end of file
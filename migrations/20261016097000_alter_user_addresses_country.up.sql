@@ -0,0 +1,4 @@
+-- Addresses must name their country explicitly and store it as an ISO 3166-1 alpha-2 code
+ALTER TABLE user_addresses ALTER COLUMN country DROP DEFAULT;
+
+UPDATE user_addresses SET country = 'US' WHERE country = 'USA';
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_categories_unique_sibling_name;
+ALTER TABLE categories ADD CONSTRAINT categories_name_key UNIQUE (name);
+DROP INDEX IF EXISTS idx_categories_parent_id;
+ALTER TABLE categories DROP COLUMN IF EXISTS parent_id;
\ No newline at end of file
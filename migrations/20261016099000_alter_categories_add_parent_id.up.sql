@@ -0,0 +1,8 @@
+ALTER TABLE categories ADD COLUMN parent_id INTEGER REFERENCES categories(id) ON DELETE RESTRICT;
+
+CREATE INDEX idx_categories_parent_id ON categories(parent_id);
+
+-- Names only need to be unique among siblings, e.g. Phones > Accessories and
+-- Laptops > Accessories
+ALTER TABLE categories DROP CONSTRAINT IF EXISTS categories_name_key;
+CREATE UNIQUE INDEX idx_categories_unique_sibling_name ON categories(COALESCE(parent_id, 0), LOWER(name));
@@ -0,0 +1,21 @@
+-- Databases set up by init-scripts/init.sql already have the table
+CREATE TABLE IF NOT EXISTS products (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    price DECIMAL(10,2) NOT NULL CHECK (price >= 0),
+    stock_quantity INTEGER NOT NULL DEFAULT 0 CHECK (stock_quantity >= 0),
+    category_id INTEGER NOT NULL REFERENCES categories(id) ON DELETE RESTRICT,
+    sku VARCHAR(100) UNIQUE,
+    specifications JSONB,
+    is_active BOOLEAN DEFAULT TRUE,
+    weight DECIMAL(8,2),
+    dimensions JSONB,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_products_category_id ON products(category_id);
+CREATE INDEX IF NOT EXISTS idx_products_active ON products(is_active);
+CREATE INDEX IF NOT EXISTS idx_products_price ON products(price);
+CREATE INDEX IF NOT EXISTS idx_products_stock ON products(stock_quantity);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS slug_aliases;
+ALTER TABLE products DROP COLUMN IF EXISTS slug;
+ALTER TABLE categories DROP COLUMN IF EXISTS slug;
\ No newline at end of file
@@ -0,0 +1,60 @@
+-- A close match of utils.Slugify for existing rows, cut like it to
+-- utils.MaxSlugLength (100) to leave room for suffixes within VARCHAR(150)
+CREATE FUNCTION pg_temp.slugify(name TEXT) RETURNS TEXT AS $$
+    SELECT NULLIF(TRIM(BOTH '-' FROM LEFT(
+        TRIM(BOTH '-' FROM REGEXP_REPLACE(LOWER(REPLACE(name, '&', ' and ')), '[^a-z0-9]+', '-', 'g')),
+        100)), '');
+$$ LANGUAGE sql IMMUTABLE;
+
+-- Later rows sharing a slug get -2, -3, ... like uniqueSlug does, skipping
+-- suffixed slugs that are already in use
+CREATE FUNCTION pg_temp.dedupe_slugs(tbl regclass) RETURNS void AS $$
+DECLARE
+    dup RECORD;
+    candidate TEXT;
+    n INTEGER;
+    taken BOOLEAN;
+BEGIN
+    FOR dup IN EXECUTE format(
+        'SELECT t.id, t.slug FROM %s t WHERE EXISTS (SELECT 1 FROM %s o WHERE o.slug = t.slug AND o.id < t.id) ORDER BY t.id',
+        tbl, tbl)
+    LOOP
+        n := 2;
+        LOOP
+            candidate := dup.slug || '-' || n;
+            EXECUTE format('SELECT EXISTS (SELECT 1 FROM %s WHERE slug = $1)', tbl) INTO taken USING candidate;
+            EXIT WHEN NOT taken;
+            n := n + 1;
+        END LOOP;
+        EXECUTE format('UPDATE %s SET slug = $1 WHERE id = $2', tbl) USING candidate, dup.id;
+    END LOOP;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE categories ADD COLUMN slug VARCHAR(150);
+
+UPDATE categories SET slug = COALESCE(pg_temp.slugify(name), 'category-' || id);
+SELECT pg_temp.dedupe_slugs('categories');
+
+ALTER TABLE categories ALTER COLUMN slug SET NOT NULL;
+CREATE UNIQUE INDEX idx_categories_slug ON categories(slug);
+
+ALTER TABLE products ADD COLUMN slug VARCHAR(150);
+
+UPDATE products SET slug = COALESCE(pg_temp.slugify(name), 'product-' || id);
+SELECT pg_temp.dedupe_slugs('products');
+
+ALTER TABLE products ALTER COLUMN slug SET NOT NULL;
+CREATE UNIQUE INDEX idx_products_slug ON products(slug);
+
+-- Previous slugs keep resolving to their category or product
+CREATE TABLE slug_aliases (
+    id SERIAL PRIMARY KEY,
+    resource VARCHAR(20) NOT NULL CHECK (resource IN ('category', 'product')),
+    slug VARCHAR(150) NOT NULL,
+    target_id INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_slug_aliases_resource_slug ON slug_aliases(resource, slug);
+CREATE INDEX idx_slug_aliases_target ON slug_aliases(resource, target_id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_products_name_trgm;
+DROP INDEX IF EXISTS idx_products_description_search;
+DROP INDEX IF EXISTS idx_products_name_search;
\ No newline at end of file
@@ -0,0 +1,16 @@
+DO $$
+BEGIN
+    IF to_regclass('order_items') IS NOT NULL THEN
+        ALTER TABLE order_items DROP COLUMN IF EXISTS variant_id;
+    END IF;
+    IF to_regclass('cart_items') IS NOT NULL THEN
+        DROP INDEX IF EXISTS idx_cart_items_unique_product;
+        DELETE FROM cart_items WHERE variant_id IS NOT NULL;
+        ALTER TABLE cart_items DROP COLUMN IF EXISTS variant_id;
+        CREATE UNIQUE INDEX idx_cart_items_unique_product ON cart_items(cart_id, product_id);
+    END IF;
+END $$;
+
+ALTER TABLE product_images DROP COLUMN IF EXISTS variant_id;
+DROP TABLE IF EXISTS product_variants;
+DROP TABLE IF EXISTS product_options;
\ No newline at end of file
@@ -0,0 +1,45 @@
+-- Option axes of a product, such as size and color, in display order
+CREATE TABLE IF NOT EXISTS product_options (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_product_options_product_name ON product_options(product_id, LOWER(name));
+
+-- Purchasable combinations of a product's options
+CREATE TABLE IF NOT EXISTS product_variants (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    sku VARCHAR(100) NOT NULL UNIQUE,
+    options JSONB NOT NULL, -- {"size": "M", "color": "Red"}, one value per product option
+    price DECIMAL(10,2) CHECK (price >= 0), -- NULL uses the product price
+    stock_quantity INTEGER NOT NULL DEFAULT 0 CHECK (stock_quantity >= 0),
+    weight DECIMAL(8,2), -- NULL uses the product weight
+    is_active BOOLEAN DEFAULT TRUE,
+    sort_order INTEGER DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_variants_product_id ON product_variants(product_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_product_variants_unique_options ON product_variants(product_id, options);
+
+ALTER TABLE product_images ADD COLUMN IF NOT EXISTS variant_id INTEGER REFERENCES product_variants(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_product_images_variant_id ON product_images(variant_id);
+
+-- Cart and order items reference the chosen variant of variant products.
+-- Carts and orders may not be migrated yet.
+DO $$
+BEGIN
+    IF to_regclass('cart_items') IS NOT NULL THEN
+        ALTER TABLE cart_items ADD COLUMN IF NOT EXISTS variant_id INTEGER REFERENCES product_variants(id) ON DELETE CASCADE;
+        DROP INDEX IF EXISTS idx_cart_items_unique_product;
+        CREATE UNIQUE INDEX idx_cart_items_unique_product ON cart_items(cart_id, product_id, COALESCE(variant_id, 0));
+    END IF;
+    IF to_regclass('order_items') IS NOT NULL THEN
+        ALTER TABLE order_items ADD COLUMN IF NOT EXISTS variant_id INTEGER REFERENCES product_variants(id) ON DELETE RESTRICT;
+        CREATE INDEX IF NOT EXISTS idx_order_items_variant_id ON order_items(variant_id);
+    END IF;
+END $$;
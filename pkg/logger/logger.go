@@ -43,3 +43,9 @@ func Fatal(err error, message string) {
 func Fatalf(err error, message string, args ...interface{}) {
 	log.Fatal().Err(err).Msgf(message, args...)
 }
+
+// Audit writes a security event tagged with audit=true so it can be routed
+// separately from the application log.
+func Audit(event string, fields map[string]interface{}) {
+	log.Warn().Bool("audit", true).Str("event", event).Fields(fields).Msg("audit event")
+}
@@ -0,0 +1,11 @@
+package utils
+
+import "strings"
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// EscapeLike escapes the LIKE wildcards in s, so a pattern built from user
+// input matches it literally.
+func EscapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
@@ -0,0 +1,18 @@
+package utils
+
+import "testing"
+
+func TestEscapeLike(t *testing.T) {
+	tests := map[string]string{
+		"lamp":     "lamp",
+		"100%":     `100\%`,
+		"usb_c":    `usb\_c`,
+		`back\lit`: `back\\lit`,
+		`%_\`:      `\%\_\\`,
+	}
+	for s, want := range tests {
+		if got := EscapeLike(s); got != want {
+			t.Errorf("EscapeLike(%q) = %q, want %q", s, got, want)
+		}
+	}
+}
@@ -1 +1,47 @@
-package utils
\ No newline at end of file
+package utils
+
+const (
+	DefaultPage  = 1
+	DefaultLimit = 10
+	MaxLimit     = 100
+)
+
+// Pagination is the pagination block returned with list responses
+type Pagination struct {
+	CurrentPage int   `json:"current_page"`
+	TotalPages  int   `json:"total_pages"`
+	TotalItems  int64 `json:"total_items"`
+	PerPage     int   `json:"per_page"`
+}
+
+// NormalizePage clamps page and limit to sane values, falling back to defaultLimit.
+func NormalizePage(page, limit, defaultLimit int) (int, int) {
+	if page < 1 {
+		page = DefaultPage
+	}
+	if limit < 1 {
+		limit = defaultLimit
+	}
+	if limit > MaxLimit {
+		limit = MaxLimit
+	}
+	return page, limit
+}
+
+// Offset returns the number of rows to skip for the given page.
+func Offset(page, limit int) int {
+	return (page - 1) * limit
+}
+
+func NewPagination(page, limit int, total int64) Pagination {
+	totalPages := 0
+	if limit > 0 {
+		totalPages = int((total + int64(limit) - 1) / int64(limit))
+	}
+	return Pagination{
+		CurrentPage: page,
+		TotalPages:  totalPages,
+		TotalItems:  total,
+		PerPage:     limit,
+	}
+}
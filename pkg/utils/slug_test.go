@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "Café & Bar Stools", want: "cafe-and-bar-stools"},
+		{name: "  Men's T-Shirts  ", want: "men-s-t-shirts"},
+		{name: "USB-C -- Cables!!", want: "usb-c-cables"},
+		{name: "Straße Øl Æble", want: "strasse-ol-aeble"},
+		{name: "iPhone 15 Pro", want: "iphone-15-pro"},
+		{name: "ＦＵＬＬ　ＷＩＤＴＨ", want: "full-width"},
+		{name: "日本語", want: ""},
+		{name: "---", want: ""},
+	}
+	for _, tt := range tests {
+		if got := Slugify(tt.name); got != tt.want {
+			t.Errorf("Slugify(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSlugifyTruncates(t *testing.T) {
+	// Cut at a word boundary rather than in the middle of a word.
+	name := strings.Repeat("abcdefghi ", 15)
+	got := Slugify(name)
+	if len(got) > MaxSlugLength {
+		t.Fatalf("len(Slugify) = %d, want at most %d", len(got), MaxSlugLength)
+	}
+	if want := strings.TrimSuffix(strings.Repeat("abcdefghi-", 10), "-"); got != want {
+		t.Errorf("Slugify = %q, want %q", got, want)
+	}
+
+	// A single long word is cut at the limit.
+	if got := Slugify(strings.Repeat("a", 150)); got != strings.Repeat("a", MaxSlugLength) {
+		t.Errorf("Slugify of a long word = %q", got)
+	}
+}
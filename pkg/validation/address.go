@@ -0,0 +1,323 @@
+package validation
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Address is the country-dependent part of a postal address. Country is an
+// ISO 3166-1 alpha-2 code after normalization.
+type Address struct {
+	Phone      string
+	State      string
+	PostalCode string
+	Country    string
+}
+
+// AddressRule describes how addresses of one country are validated and
+// normalized.
+type AddressRule struct {
+	// Country is the ISO 3166-1 alpha-2 code, e.g. "US".
+	Country string
+	// Aliases are other accepted spellings, e.g. "USA" or "United States".
+	Aliases []string
+	// CallingCode is the international dialling prefix without "+", used to
+	// turn national phone numbers into E.164.
+	CallingCode string
+	// TrunkPrefix is dropped from national phone numbers before the calling
+	// code is prepended, e.g. "0" for 020 7946 0958 in GB.
+	TrunkPrefix string
+	// PostalCode matches a normalized postal code. Nil means the country does
+	// not use postal codes and any value is cleared.
+	PostalCode *regexp.Regexp
+	// PostalCodeExample is shown in error messages.
+	PostalCodeExample string
+	// FormatPostalCode rewrites an uppercased postal code without spaces into
+	// its canonical form. Nil keeps the uppercased input.
+	FormatPostalCode func(compact string) string
+	RequireState     bool
+}
+
+// AddressError lists the invalid fields keyed like validator field errors.
+type AddressError struct {
+	Fields map[string]string
+}
+
+func (e *AddressError) Error() string {
+	keys := make([]string, 0, len(e.Fields))
+	for key := range e.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	msgs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		msgs = append(msgs, e.Fields[key])
+	}
+	return "invalid address: " + strings.Join(msgs, "; ")
+}
+
+var (
+	e164Pattern     = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+	phoneSeparators = strings.NewReplacer(" ", "", "-", "", ".", "", "(", "", ")", "", "/", "")
+
+	addressRulesMu sync.RWMutex
+	addressRules   = map[string]AddressRule{}
+	countryAliases = map[string]string{}
+)
+
+func init() {
+	for _, rule := range defaultAddressRules {
+		RegisterAddressRule(rule)
+	}
+}
+
+// RegisterAddressRule adds or replaces the rule of a country. It is safe to
+// call at startup before addresses are validated.
+func RegisterAddressRule(rule AddressRule) {
+	code := strings.ToUpper(rule.Country)
+	rule.Country = code
+	addressRulesMu.Lock()
+	defer addressRulesMu.Unlock()
+	addressRules[code] = rule
+	countryAliases[code] = code
+	for _, alias := range rule.Aliases {
+		countryAliases[countryKey(alias)] = code
+	}
+}
+
+// LookupAddressRule resolves a country code, alias or name to its rule.
+func LookupAddressRule(country string) (AddressRule, bool) {
+	addressRulesMu.RLock()
+	defer addressRulesMu.RUnlock()
+	code, ok := countryAliases[countryKey(country)]
+	if !ok {
+		return AddressRule{}, false
+	}
+	return addressRules[code], true
+}
+
+// NormalizeAddress validates the address against the rule of its country and
+// rewrites it in canonical form: the country as its alpha-2 code, the postal
+// code in the national format and the phone number in E.164. The address is
+// left untouched when an *AddressError is returned.
+func NormalizeAddress(address *Address) error {
+	fields := make(map[string]string)
+	rule, ok := LookupAddressRule(address.Country)
+	if !ok {
+		fields["Country"] = fmt.Sprintf("Country %q is not supported", address.Country)
+		return &AddressError{Fields: fields}
+	}
+
+	state := strings.TrimSpace(address.State)
+	if rule.RequireState && state == "" {
+		fields["State"] = "State is required for " + rule.Country
+	}
+
+	postalCode := ""
+	if rule.PostalCode != nil {
+		compact := strings.ToUpper(strings.Join(strings.Fields(address.PostalCode), ""))
+		postalCode = compact
+		if rule.FormatPostalCode != nil {
+			postalCode = rule.FormatPostalCode(compact)
+		}
+		if !rule.PostalCode.MatchString(postalCode) {
+			fields["PostalCode"] = fmt.Sprintf("PostalCode must be a valid %s postal code, e.g. %s", rule.Country, rule.PostalCodeExample)
+		}
+	}
+
+	phone, ok := NormalizePhone(address.Phone, rule)
+	if !ok {
+		fields["Phone"] = "Phone must be a valid phone number in international format, e.g. +" + rule.CallingCode + "..."
+	}
+
+	if len(fields) > 0 {
+		return &AddressError{Fields: fields}
+	}
+	address.Country = rule.Country
+	address.State = state
+	address.PostalCode = postalCode
+	address.Phone = phone
+	return nil
+}
+
+// NormalizePhone converts a phone number to E.164. Numbers without an
+// international prefix are treated as national numbers of the rule's country.
+func NormalizePhone(phone string, rule AddressRule) (string, bool) {
+	phone = phoneSeparators.Replace(strings.TrimSpace(phone))
+	switch {
+	case strings.HasPrefix(phone, "+"):
+	case strings.HasPrefix(phone, "00"):
+		phone = "+" + phone[2:]
+	case rule.CallingCode != "":
+		if rule.TrunkPrefix != "" {
+			phone = strings.TrimPrefix(phone, rule.TrunkPrefix)
+		}
+		phone = "+" + rule.CallingCode + phone
+	}
+	if !e164Pattern.MatchString(phone) {
+		return "", false
+	}
+	return phone, true
+}
+
+func countryKey(country string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(country), " "))
+}
+
+// insertSpace formats postal codes like "SW1A1AA" as "SW1A 1AA", with the
+// space before the last n characters.
+func insertSpace(n int) func(string) string {
+	return func(compact string) string {
+		if len(compact) <= n {
+			return compact
+		}
+		return compact[:len(compact)-n] + " " + compact[len(compact)-n:]
+	}
+}
+
+// insertDash formats postal codes like "1000001" as "100-0001", with the dash
+// after the first n characters.
+func insertDash(n int) func(string) string {
+	return func(compact string) string {
+		if len(compact) <= n || strings.Contains(compact, "-") {
+			return compact
+		}
+		return compact[:n] + "-" + compact[n:]
+	}
+}
+
+var defaultAddressRules = []AddressRule{
+	{
+		Country:           "US",
+		Aliases:           []string{"USA", "United States", "United States of America"},
+		CallingCode:       "1",
+		TrunkPrefix:       "1",
+		PostalCode:        regexp.MustCompile(`^[0-9]{5}(-[0-9]{4})?$`),
+		PostalCodeExample: "94105 or 94105-1234",
+		FormatPostalCode: func(compact string) string {
+			if len(compact) == 9 && !strings.Contains(compact, "-") {
+				return compact[:5] + "-" + compact[5:]
+			}
+			return compact
+		},
+		RequireState: true,
+	},
+	{
+		Country:           "CA",
+		Aliases:           []string{"CAN", "Canada"},
+		CallingCode:       "1",
+		TrunkPrefix:       "1",
+		PostalCode:        regexp.MustCompile(`^[ABCEGHJ-NPRSTVXY][0-9][ABCEGHJ-NPRSTV-Z] [0-9][ABCEGHJ-NPRSTV-Z][0-9]$`),
+		PostalCodeExample: "K1A 0B1",
+		FormatPostalCode:  insertSpace(3),
+		RequireState:      true,
+	},
+	{
+		Country:           "GB",
+		Aliases:           []string{"GBR", "UK", "United Kingdom", "Great Britain"},
+		CallingCode:       "44",
+		TrunkPrefix:       "0",
+		PostalCode:        regexp.MustCompile(`^([A-Z]{1,2}[0-9][A-Z0-9]?|GIR) [0-9][A-Z]{2}$`),
+		PostalCodeExample: "SW1A 1AA",
+		FormatPostalCode:  insertSpace(3),
+	},
+	{
+		Country:           "DE",
+		Aliases:           []string{"DEU", "Germany", "Deutschland"},
+		CallingCode:       "49",
+		TrunkPrefix:       "0",
+		PostalCode:        regexp.MustCompile(`^[0-9]{5}$`),
+		PostalCodeExample: "10115",
+	},
+	{
+		Country:           "FR",
+		Aliases:           []string{"FRA", "France"},
+		CallingCode:       "33",
+		TrunkPrefix:       "0",
+		PostalCode:        regexp.MustCompile(`^[0-9]{5}$`),
+		PostalCodeExample: "75008",
+	},
+	{
+		Country:           "NL",
+		Aliases:           []string{"NLD", "Netherlands", "The Netherlands"},
+		CallingCode:       "31",
+		TrunkPrefix:       "0",
+		PostalCode:        regexp.MustCompile(`^[1-9][0-9]{3} [A-Z]{2}$`),
+		PostalCodeExample: "1012 AB",
+		FormatPostalCode:  insertSpace(2),
+	},
+	{
+		Country:           "AU",
+		Aliases:           []string{"AUS", "Australia"},
+		CallingCode:       "61",
+		TrunkPrefix:       "0",
+		PostalCode:        regexp.MustCompile(`^[0-9]{4}$`),
+		PostalCodeExample: "2000",
+		RequireState:      true,
+	},
+	{
+		Country:           "JP",
+		Aliases:           []string{"JPN", "Japan"},
+		CallingCode:       "81",
+		TrunkPrefix:       "0",
+		PostalCode:        regexp.MustCompile(`^[0-9]{3}-[0-9]{4}$`),
+		PostalCodeExample: "100-0001",
+		FormatPostalCode:  insertDash(3),
+		RequireState:      true,
+	},
+	{
+		Country:           "IN",
+		Aliases:           []string{"IND", "India"},
+		CallingCode:       "91",
+		TrunkPrefix:       "0",
+		PostalCode:        regexp.MustCompile(`^[1-9][0-9]{5}$`),
+		PostalCodeExample: "110001",
+		RequireState:      true,
+	},
+	{
+		Country:           "ID",
+		Aliases:           []string{"IDN", "Indonesia"},
+		CallingCode:       "62",
+		TrunkPrefix:       "0",
+		PostalCode:        regexp.MustCompile(`^[1-9][0-9]{4}$`),
+		PostalCodeExample: "10110",
+		RequireState:      true,
+	},
+	{
+		Country:           "SG",
+		Aliases:           []string{"SGP", "Singapore"},
+		CallingCode:       "65",
+		PostalCode:        regexp.MustCompile(`^[0-9]{6}$`),
+		PostalCodeExample: "018956",
+	},
+	{
+		Country:           "MY",
+		Aliases:           []string{"MYS", "Malaysia"},
+		CallingCode:       "60",
+		TrunkPrefix:       "0",
+		PostalCode:        regexp.MustCompile(`^[0-9]{5}$`),
+		PostalCodeExample: "50088",
+		RequireState:      true,
+	},
+	{
+		Country:           "BR",
+		Aliases:           []string{"BRA", "Brazil", "Brasil"},
+		CallingCode:       "55",
+		TrunkPrefix:       "0",
+		PostalCode:        regexp.MustCompile(`^[0-9]{5}-[0-9]{3}$`),
+		PostalCodeExample: "01310-200",
+		FormatPostalCode:  insertDash(5),
+		RequireState:      true,
+	},
+	{
+		Country:     "AE",
+		Aliases:     []string{"ARE", "United Arab Emirates", "UAE"},
+		CallingCode: "971",
+		TrunkPrefix: "0",
+		// The UAE does not use postal codes.
+		RequireState: true,
+	},
+}
@@ -0,0 +1,113 @@
+package validation
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		address Address
+		want    Address
+	}{
+		{
+			name:    "us zip+4 and national phone",
+			address: Address{Phone: "(415) 555-0100", State: " CA ", PostalCode: "941051234", Country: "usa"},
+			want:    Address{Phone: "+14155550100", State: "CA", PostalCode: "94105-1234", Country: "US"},
+		},
+		{
+			name:    "gb postcode spacing and trunk prefix",
+			address: Address{Phone: "020 7946 0958", PostalCode: "sw1a1aa", Country: "united  kingdom"},
+			want:    Address{Phone: "+442079460958", PostalCode: "SW1A 1AA", Country: "GB"},
+		},
+		{
+			name:    "international prefix 00",
+			address: Address{Phone: "0049 30 901820", PostalCode: "10115", Country: "DE"},
+			want:    Address{Phone: "+4930901820", PostalCode: "10115", Country: "DE"},
+		},
+		{
+			name:    "nl postcode",
+			address: Address{Phone: "+31 20 123 4567", PostalCode: "1012ab", Country: "Netherlands"},
+			want:    Address{Phone: "+31201234567", PostalCode: "1012 AB", Country: "NL"},
+		},
+		{
+			name:    "jp postcode dash",
+			address: Address{Phone: "03-1234-5678", State: "Tokyo", PostalCode: "1000001", Country: "JP"},
+			want:    Address{Phone: "+81312345678", State: "Tokyo", PostalCode: "100-0001", Country: "JP"},
+		},
+		{
+			name:    "country without postal codes",
+			address: Address{Phone: "+971 4 123 4567", State: "Dubai", PostalCode: "12345", Country: "UAE"},
+			want:    Address{Phone: "+97141234567", State: "Dubai", PostalCode: "", Country: "AE"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address := tt.address
+			if err := NormalizeAddress(&address); err != nil {
+				t.Fatalf("NormalizeAddress: %v", err)
+			}
+			if address != tt.want {
+				t.Errorf("NormalizeAddress = %+v, want %+v", address, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeAddressErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		address    Address
+		wantFields []string
+	}{
+		{
+			name:       "unsupported country",
+			address:    Address{Phone: "+14155550100", Country: "Atlantis"},
+			wantFields: []string{"Country"},
+		},
+		{
+			name:       "every field invalid",
+			address:    Address{Phone: "123", PostalCode: "ABCDE", Country: "US"},
+			wantFields: []string{"Phone", "PostalCode", "State"},
+		},
+		{
+			name:       "gb postcode",
+			address:    Address{Phone: "+442079460958", PostalCode: "12345", Country: "GB"},
+			wantFields: []string{"PostalCode"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address := tt.address
+			err := NormalizeAddress(&address)
+			var addressErr *AddressError
+			if !errors.As(err, &addressErr) {
+				t.Fatalf("NormalizeAddress = %v, want *AddressError", err)
+			}
+			var fields []string
+			for _, field := range []string{"Country", "Phone", "PostalCode", "State"} {
+				if _, ok := addressErr.Fields[field]; ok {
+					fields = append(fields, field)
+				}
+			}
+			if !reflect.DeepEqual(fields, tt.wantFields) {
+				t.Errorf("invalid fields = %v, want %v", fields, tt.wantFields)
+			}
+			if address != tt.address {
+				t.Errorf("address changed to %+v on error", address)
+			}
+		})
+	}
+}
+
+func TestLookupAddressRule(t *testing.T) {
+	rule, ok := LookupAddressRule(" great   britain ")
+	if !ok || rule.Country != "GB" {
+		t.Fatalf("LookupAddressRule = %+v, %v", rule, ok)
+	}
+	if _, ok := LookupAddressRule("Narnia"); ok {
+		t.Fatal("unknown country resolved")
+	}
+}
@@ -1 +1,119 @@
 package validation
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+)
+
+// PasswordPolicy describes the rules enforced by the "password" tag
+type PasswordPolicy struct {
+	MinLength     int
+	MaxLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	// MaxBytes caps the UTF-8 encoded length for hash algorithms that only
+	// take so many bytes, like bcrypt; zero means no cap
+	MaxBytes int
+}
+
+// DefaultPasswordPolicy is used until RegisterPasswordPolicy is called
+var DefaultPasswordPolicy = PasswordPolicy{
+	MinLength: 8,
+	MaxLength: 128,
+}
+
+// Allows reports whether the password satisfies the policy. Lengths are
+// counted in characters, not bytes, except for MaxBytes.
+func (p PasswordPolicy) Allows(password string) bool {
+	length := utf8.RuneCountInString(password)
+	if length < p.MinLength || (p.MaxLength > 0 && length > p.MaxLength) {
+		return false
+	}
+	if p.MaxBytes > 0 && len(password) > p.MaxBytes {
+		return false
+	}
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			symbol = true
+		}
+	}
+	return (!p.RequireUpper || upper) &&
+		(!p.RequireLower || lower) &&
+		(!p.RequireDigit || digit) &&
+		(!p.RequireSymbol || symbol)
+}
+
+// Describe renders the policy as a sentence fragment for error messages.
+func (p PasswordPolicy) Describe() string {
+	desc := fmt.Sprintf("at least %d characters", p.MinLength)
+	if p.MaxLength > 0 {
+		desc = fmt.Sprintf("between %d and %d characters", p.MinLength, p.MaxLength)
+	}
+	if p.MaxBytes > 0 {
+		desc += fmt.Sprintf(" (at most %d bytes)", p.MaxBytes)
+	}
+	var contains []string
+	if p.RequireUpper {
+		contains = append(contains, "an uppercase letter")
+	}
+	if p.RequireLower {
+		contains = append(contains, "a lowercase letter")
+	}
+	if p.RequireDigit {
+		contains = append(contains, "a digit")
+	}
+	if p.RequireSymbol {
+		contains = append(contains, "a symbol")
+	}
+	if len(contains) > 0 {
+		desc += " and contain " + strings.Join(contains, ", ")
+	}
+	return desc
+}
+
+// RegisterPasswordPolicy registers the "password" tag and its translation.
+// InitValidator must have been called first.
+func RegisterPasswordPolicy(policy PasswordPolicy) error {
+	if err := Validate.RegisterValidation("password", func(fl validator.FieldLevel) bool {
+		return policy.Allows(fl.Field().String())
+	}); err != nil {
+		return err
+	}
+	return Validate.RegisterTranslation("password", Trans, func(ut ut.Translator) error {
+		return ut.Add("password", "{0} must be "+policy.Describe(), true)
+	}, func(ut ut.Translator, fe validator.FieldError) string {
+		t, _ := ut.T("password", fe.Field())
+		return t
+	})
+}
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// registerSlug registers the "slug" tag for lowercase, hyphen separated slugs.
+func registerSlug() {
+	_ = Validate.RegisterValidation("slug", func(fl validator.FieldLevel) bool {
+		return slugPattern.MatchString(fl.Field().String())
+	})
+	_ = Validate.RegisterTranslation("slug", Trans, func(ut ut.Translator) error {
+		return ut.Add("slug", "{0} may only contain lowercase letters, digits and single hyphens", true)
+	}, func(ut ut.Translator, fe validator.FieldError) string {
+		t, _ := ut.T("slug", fe.Field())
+		return t
+	})
+}
@@ -0,0 +1,41 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPasswordPolicyAllows(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   PasswordPolicy
+		password string
+		want     bool
+	}{
+		{name: "long enough", policy: PasswordPolicy{MinLength: 8}, password: "abcdefgh", want: true},
+		{name: "too short", policy: PasswordPolicy{MinLength: 8}, password: "abcdefg"},
+		{name: "too long", policy: PasswordPolicy{MinLength: 1, MaxLength: 4}, password: "abcde"},
+		{name: "characters not bytes", policy: PasswordPolicy{MinLength: 1, MaxLength: 4}, password: "äöüß", want: true},
+		{name: "within byte cap", policy: PasswordPolicy{MinLength: 1, MaxLength: 72, MaxBytes: 72}, password: strings.Repeat("a", 72), want: true},
+		// 40 characters of two bytes each pass MaxLength but not bcrypt's 72 bytes.
+		{name: "over byte cap", policy: PasswordPolicy{MinLength: 1, MaxLength: 72, MaxBytes: 72}, password: strings.Repeat("ä", 40)},
+		{name: "missing upper", policy: PasswordPolicy{MinLength: 1, RequireUpper: true}, password: "abc1"},
+		{name: "all classes", policy: PasswordPolicy{MinLength: 1, RequireUpper: true, RequireLower: true, RequireDigit: true, RequireSymbol: true}, password: "Ab1!", want: true},
+		{name: "missing symbol", policy: PasswordPolicy{MinLength: 1, RequireSymbol: true}, password: "Ab1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.Allows(tt.password); got != tt.want {
+				t.Errorf("Allows(%q) = %v, want %v", tt.password, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPasswordPolicyDescribe(t *testing.T) {
+	policy := PasswordPolicy{MinLength: 8, MaxLength: 72, MaxBytes: 72, RequireDigit: true}
+	want := "between 8 and 72 characters (at most 72 bytes) and contain a digit"
+	if got := policy.Describe(); got != want {
+		t.Errorf("Describe() = %q, want %q", got, want)
+	}
+}
@@ -22,4 +22,7 @@ func InitValidator() {
 
 	// Register default translation
 	_ = en_translations.RegisterDefaultTranslations(Validate, Trans)
+
+	_ = RegisterPasswordPolicy(DefaultPasswordPolicy)
+	registerSlug()
 }